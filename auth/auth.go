@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"strings"
+)
+
+var (
+	// ErrUnauthenticated is returned when the credentials are missing or not valid.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied is returned when the caller does not have the role
+	// required by the operation.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Role is the level of access of a caller. Each role includes the roles below it.
+type Role string
+
+const (
+	// Public marks the operations that do not require any credentials.
+	Public Role = ""
+	// RoleReader reads the authors.
+	RoleReader Role = "reader"
+	// RoleEditor also creates, changes and deletes the authors.
+	RoleEditor Role = "editor"
+	// RoleAdmin also purges the authors and manages the service.
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders the roles from the least to the most privileged.
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole returns the role named by the string.
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := roleRanks[role]; !ok {
+		return "", fmt.Errorf("unknown role %q", name)
+	}
+	return role, nil
+}
+
+// Allows returns whether the role grants the access required by another role.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[required]
+}
+
+// Authentication methods of the principals.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	// Subject identifies the caller, such as the subject of the token or the
+	// name of the API key.
+	Subject string
+	// Roles are the roles granted to the caller.
+	Roles []Role
+	// Method is how the caller was authenticated.
+	Method string
+}
+
+// HasRole returns whether one of the roles of the principal allows the required one.
+func (p Principal) HasRole(required Role) bool {
+	for _, role := range p.Roles {
+		if role.Allows(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, and false when the
+// context does not carry one.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Authorize checks that the context carries a principal allowed the required
+// role. Public operations are always allowed.
+func Authorize(ctx context.Context, required Role) error {
+	if required == Public {
+		return nil
+	}
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("missing credentials: %w", ErrUnauthenticated)
+	}
+	if !principal.HasRole(required) {
+		return fmt.Errorf("%q requires the %s role: %w", principal.Subject, required, ErrPermissionDenied)
+	}
+	return nil
+}
+
+// APIKey is a static key authenticating another service.
+type APIKey struct {
+	Name string
+	Role Role
+	Key  string
+}
+
+// ParseAPIKeys parses a comma-separated list of API keys formatted as
+// "name:role:key".
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid API key %q, expected name:role:key", parts[0])
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key %q: %w", parts[0], err)
+		}
+		keys = append(keys, APIKey{Name: parts[0], Role: role, Key: parts[2]})
+	}
+	return keys, nil
+}
+
+// DefaultRolesClaim is the JWT claim holding the roles of the caller.
+const DefaultRolesClaim = "roles"
+
+// Authenticator verifies the credentials of the callers: JWT bearer tokens
+// signed with an HMAC secret or a key of a JWKS, and static API keys.
+type Authenticator struct {
+	hmacSecret []byte
+	keys       KeySet
+	apiKeys    []APIKey
+	issuer     string
+	audience   string
+	rolesClaim string
+}
+
+// Option configures an Authenticator.
+type Option func(*Authenticator)
+
+// WithHMACSecret accepts the tokens signed with the secret using HS256, HS384 or HS512.
+func WithHMACSecret(secret []byte) Option {
+	return func(a *Authenticator) {
+		a.hmacSecret = secret
+	}
+}
+
+// WithJWKS accepts the tokens signed with one of the RSA or ECDSA keys of the set.
+func WithJWKS(keys KeySet) Option {
+	return func(a *Authenticator) {
+		a.keys = keys
+	}
+}
+
+// WithAPIKeys accepts the API keys.
+func WithAPIKeys(keys ...APIKey) Option {
+	return func(a *Authenticator) {
+		a.apiKeys = append(a.apiKeys, keys...)
+	}
+}
+
+// WithIssuer only accepts the tokens issued by the issuer.
+func WithIssuer(issuer string) Option {
+	return func(a *Authenticator) {
+		a.issuer = issuer
+	}
+}
+
+// WithAudience only accepts the tokens intended for the audience.
+func WithAudience(audience string) Option {
+	return func(a *Authenticator) {
+		a.audience = audience
+	}
+}
+
+// WithRolesClaim sets the JWT claim holding the roles, DefaultRolesClaim by default.
+func WithRolesClaim(claim string) Option {
+	return func(a *Authenticator) {
+		a.rolesClaim = claim
+	}
+}
+
+// New creates a new authenticator.
+func New(opts ...Option) *Authenticator {
+	a := &Authenticator{rolesClaim: DefaultRolesClaim}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// AuthenticateAPIKey returns the principal of the API key.
+func (a *Authenticator) AuthenticateAPIKey(key string) (Principal, error) {
+	for _, apiKey := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey.Key), []byte(key)) == 1 {
+			return Principal{Subject: apiKey.Name, Roles: []Role{apiKey.Role}, Method: MethodAPIKey}, nil
+		}
+	}
+	return Principal{}, fmt.Errorf("unknown API key: %w", ErrUnauthenticated)
+}
+
+// AuthenticateToken verifies the signature, expiration, issuer and audience
+// of the JWT and returns its principal. Tokens must expire, and unknown roles
+// are ignored.
+func (a *Authenticator) AuthenticateToken(token string) (Principal, error) {
+	methods := a.validMethods()
+	if len(methods) == 0 {
+		return Principal{}, fmt.Errorf("tokens are not accepted: %w", ErrUnauthenticated)
+	}
+	parserOptions := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if a.issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(a.audience))
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, a.verificationKey, parserOptions...); err != nil {
+		return Principal{}, fmt.Errorf("invalid token: %v: %w", err, ErrUnauthenticated)
+	}
+	if expiration, err := claims.GetExpirationTime(); err != nil || expiration == nil {
+		return Principal{}, fmt.Errorf("invalid token: missing expiration: %w", ErrUnauthenticated)
+	}
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, fmt.Errorf("invalid token: missing subject: %w", ErrUnauthenticated)
+	}
+	return Principal{Subject: subject, Roles: a.tokenRoles(claims), Method: MethodJWT}, nil
+}
+
+// validMethods returns the signing methods of the configured keys, so that a
+// token cannot pick another kind of key than the one it is checked with.
+func (a *Authenticator) validMethods() []string {
+	var methods []string
+	if len(a.hmacSecret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if len(a.keys) > 0 {
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512")
+	}
+	return methods
+}
+
+// verificationKey returns the key checking the signature of the token.
+func (a *Authenticator) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.hmacSecret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	return a.keys.Find(kid)
+}
+
+// tokenRoles returns the known roles of the roles claim, which is either a
+// list or a space-separated string.
+func (a *Authenticator) tokenRoles(claims jwt.MapClaims) []Role {
+	var names []string
+	switch value := claims[a.rolesClaim].(type) {
+	case string:
+		names = strings.Fields(value)
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	var roles []Role
+	for _, name := range names {
+		if role, err := ParseRole(name); err == nil {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
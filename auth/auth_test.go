@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testSecret = []byte("a-test-secret-of-32-bytes-length")
+
+// signToken returns a token signed with the HMAC secret carrying the claims.
+func signToken(secret []byte, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// validClaims returns the claims of a token of the subject with the roles.
+func validClaims(subject string, roles ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   subject,
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestRoles(t *testing.T) {
+	Convey("Roles should include the roles below them", t, func() {
+		So(RoleAdmin.Allows(RoleEditor), ShouldBeTrue)
+		So(RoleAdmin.Allows(RoleReader), ShouldBeTrue)
+		So(RoleEditor.Allows(RoleReader), ShouldBeTrue)
+		So(RoleEditor.Allows(RoleAdmin), ShouldBeFalse)
+		So(RoleReader.Allows(RoleEditor), ShouldBeFalse)
+		So(Role("owner").Allows(RoleReader), ShouldBeFalse)
+	})
+
+	Convey("Roles should be parsed regardless of their case", t, func() {
+		role, err := ParseRole(" Editor ")
+		So(err, ShouldBeNil)
+		So(role, ShouldEqual, RoleEditor)
+
+		_, err = ParseRole("owner")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	Convey("Given a context", t, func() {
+		ctx := context.Background()
+
+		Convey("Public operations should always be allowed", func() {
+			So(Authorize(ctx, Public), ShouldBeNil)
+		})
+
+		Convey("Operations should fail without a principal", func() {
+			So(errors.Is(Authorize(ctx, RoleReader), ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Operations should fail when the principal lacks the role", func() {
+			ctx = WithPrincipal(ctx, Principal{Subject: "ada", Roles: []Role{RoleReader}})
+			So(Authorize(ctx, RoleReader), ShouldBeNil)
+			So(errors.Is(Authorize(ctx, RoleEditor), ErrPermissionDenied), ShouldBeTrue)
+		})
+	})
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	Convey("API keys should be parsed from name:role:key entries", t, func() {
+		keys, err := ParseAPIKeys("quotes:editor:k3y:with:colons, ,backup:admin:other")
+		So(err, ShouldBeNil)
+		So(keys, ShouldResemble, []APIKey{
+			{Name: "quotes", Role: RoleEditor, Key: "k3y:with:colons"},
+			{Name: "backup", Role: RoleAdmin, Key: "other"},
+		})
+	})
+
+	Convey("Invalid API keys should fail without revealing the key", t, func() {
+		_, err := ParseAPIKeys("quotes:owner:secret")
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldNotContainSubstring, "secret")
+
+		_, err = ParseAPIKeys("quotes:reader")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	Convey("Given an authenticator with an HMAC secret and an API key", t, func() {
+		authenticator := New(
+			WithHMACSecret(testSecret),
+			WithAPIKeys(APIKey{Name: "quotes", Role: RoleEditor, Key: "quotes-key"}),
+			WithIssuer("mosha"),
+			WithAudience("authors"),
+		)
+		claims := validClaims("ada", "editor", "unknown")
+		claims["iss"] = "mosha"
+		claims["aud"] = "authors"
+
+		Convey("Valid tokens should return their principal with the known roles", func() {
+			principal, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(err, ShouldBeNil)
+			So(principal, ShouldResemble, Principal{Subject: "ada", Roles: []Role{RoleEditor}, Method: MethodJWT})
+		})
+
+		Convey("Roles may be a space-separated string", func() {
+			claims["roles"] = "reader admin"
+			principal, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(err, ShouldBeNil)
+			So(principal.Roles, ShouldResemble, []Role{RoleReader, RoleAdmin})
+		})
+
+		Convey("Tokens signed with another secret should fail", func() {
+			_, err := authenticator.AuthenticateToken(signToken([]byte("another-secret"), claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Expired tokens should fail", func() {
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			_, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Tokens without expiration should fail", func() {
+			delete(claims, "exp")
+			_, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Tokens of another issuer or audience should fail", func() {
+			claims["iss"] = "other"
+			_, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+
+			claims["iss"] = "mosha"
+			claims["aud"] = "quotes"
+			_, err = authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Tokens without subject should fail", func() {
+			delete(claims, "sub")
+			_, err := authenticator.AuthenticateToken(signToken(testSecret, claims))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Unsigned tokens should fail", func() {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			_, err := authenticator.AuthenticateToken(token)
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("Known API keys should return their principal", func() {
+			principal, err := authenticator.AuthenticateAPIKey("quotes-key")
+			So(err, ShouldBeNil)
+			So(principal, ShouldResemble, Principal{Subject: "quotes", Roles: []Role{RoleEditor}, Method: MethodAPIKey})
+		})
+
+		Convey("Unknown API keys should fail", func() {
+			_, err := authenticator.AuthenticateAPIKey("quotes-key2")
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an authenticator without HMAC secret", t, func() {
+		authenticator := New(WithAPIKeys(APIKey{Name: "quotes", Role: RoleEditor, Key: "quotes-key"}))
+
+		Convey("HMAC tokens should fail, even signed with an empty secret", func() {
+			_, err := authenticator.AuthenticateToken(signToken([]byte{}, validClaims("ada", "admin")))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+	})
+}
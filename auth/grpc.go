@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the gRPC metadata carrying an API key.
+const APIKeyMetadata = "x-api-key"
+
+// MethodRoles maps the full gRPC method names to the role they require.
+// Methods missing from the map require RoleAdmin.
+type MethodRoles map[string]Role
+
+// required returns the role required by the method.
+func (m MethodRoles) required(fullMethod string) Role {
+	if role, ok := m[fullMethod]; ok {
+		return role
+	}
+	return RoleAdmin
+}
+
+// UnaryServerInterceptor authenticates the unary calls by the bearer token of
+// the authorization metadata or by the x-api-key metadata, and rejects the
+// ones whose principal is not allowed the role of the method.
+func (a *Authenticator) UnaryServerInterceptor(roles MethodRoles) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorizeGrpc(ctx, roles.required(info.FullMethod))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates and authorizes the streaming calls
+// like UnaryServerInterceptor.
+func (a *Authenticator) StreamServerInterceptor(roles MethodRoles) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorizeGrpc(ss.Context(), roles.required(info.FullMethod))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// principalStream is a server stream whose context carries the principal.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the principal.
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorizeGrpc puts the principal of the call in the context and checks it
+// is allowed the required role.
+func (a *Authenticator) authorizeGrpc(ctx context.Context, required Role) (context.Context, error) {
+	principal, ok, err := a.authenticateGrpc(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if ok {
+		ctx = WithPrincipal(ctx, principal)
+	}
+	if err := Authorize(ctx, required); err != nil {
+		return nil, grpcError(err)
+	}
+	return ctx, nil
+}
+
+// authenticateGrpc returns the principal of the credentials of the call, and
+// false when it has none.
+func (a *Authenticator) authenticateGrpc(ctx context.Context) (Principal, bool, error) {
+	if keys := metadata.ValueFromIncomingContext(ctx, APIKeyMetadata); len(keys) > 0 {
+		principal, err := a.AuthenticateAPIKey(keys[0])
+		return principal, true, err
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		token, ok := bearerToken(values[0])
+		if !ok {
+			return Principal{}, true, fmt.Errorf("unsupported authorization scheme: %w", ErrUnauthenticated)
+		}
+		principal, err := a.AuthenticateToken(token)
+		return principal, true, err
+	}
+	return Principal{}, false, nil
+}
+
+// grpcError converts the error to a PermissionDenied status when the caller
+// lacks a role, and to an Unauthenticated status otherwise.
+func grpcError(err error) error {
+	if errors.Is(err, ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream is a server stream with a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestGrpcInterceptors(t *testing.T) {
+	roles := MethodRoles{
+		"/authorservice.AuthorService/GetAuthor":    RoleReader,
+		"/authorservice.AuthorService/CreateAuthor": RoleEditor,
+		"/grpc.health.v1.Health/Watch":              Public,
+	}
+	authenticator := New(
+		WithHMACSecret(testSecret),
+		WithAPIKeys(APIKey{Name: "quotes", Role: RoleReader, Key: "quotes-key"}),
+	)
+	incoming := func(pairs ...string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	}
+
+	Convey("Given the unary interceptor", t, func() {
+		interceptor := authenticator.UnaryServerInterceptor(roles)
+		call := func(ctx context.Context, method string) (Principal, error) {
+			var principal Principal
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+				principal, _ = PrincipalFromContext(ctx)
+				return nil, nil
+			})
+			return principal, err
+		}
+
+		Convey("Calls with the required role should pass with their principal", func() {
+			principal, err := call(incoming("x-api-key", "quotes-key"), "/authorservice.AuthorService/GetAuthor")
+			So(err, ShouldBeNil)
+			So(principal.Subject, ShouldEqual, "quotes")
+
+			token := signToken(testSecret, validClaims("ada", "editor"))
+			principal, err = call(incoming("authorization", "Bearer "+token), "/authorservice.AuthorService/CreateAuthor")
+			So(err, ShouldBeNil)
+			So(principal.Subject, ShouldEqual, "ada")
+		})
+
+		Convey("Calls without or with invalid credentials should be unauthenticated", func() {
+			_, err := call(context.Background(), "/authorservice.AuthorService/GetAuthor")
+			So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+
+			_, err = call(incoming("authorization", "Bearer invalid"), "/authorservice.AuthorService/GetAuthor")
+			So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+		})
+
+		Convey("Calls of a caller without the role should be denied", func() {
+			_, err := call(incoming("x-api-key", "quotes-key"), "/authorservice.AuthorService/CreateAuthor")
+			So(status.Code(err), ShouldEqual, codes.PermissionDenied)
+		})
+
+		Convey("Unknown methods should require the admin role", func() {
+			token := signToken(testSecret, validClaims("ada", "editor"))
+			_, err := call(incoming("authorization", "Bearer "+token), "/authorservice.AuthorService/Unknown")
+			So(status.Code(err), ShouldEqual, codes.PermissionDenied)
+		})
+	})
+
+	Convey("Given the stream interceptor", t, func() {
+		interceptor := authenticator.StreamServerInterceptor(roles)
+
+		Convey("Public methods should accept calls without credentials", func() {
+			err := interceptor(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"},
+				func(srv any, stream grpc.ServerStream) error { return nil })
+			So(err, ShouldBeNil)
+		})
+
+		Convey("The stream context should carry the principal", func() {
+			var principal Principal
+			stream := &fakeStream{ctx: incoming("x-api-key", "quotes-key")}
+			err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/authorservice.AuthorService/GetAuthor"},
+				func(srv any, stream grpc.ServerStream) error {
+					principal, _ = PrincipalFromContext(stream.Context())
+					return nil
+				})
+			So(err, ShouldBeNil)
+			So(principal.Subject, ShouldEqual, "quotes")
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	mhttp "github.com/wcodesoft/mosha-service-common/http"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the HTTP header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+// HttpMiddleware puts the principal authenticated by the bearer token of the
+// Authorization header, or by the X-API-Key header, in the request context.
+// Requests without credentials go on anonymously, to be rejected by Require,
+// while requests with invalid credentials are rejected right away.
+func (a *Authenticator) HttpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok, err := a.authenticateHttp(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if ok {
+			r = r.WithContext(WithPrincipal(r.Context(), principal))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticateHttp returns the principal of the credentials of the request,
+// and false when it has none.
+func (a *Authenticator) authenticateHttp(r *http.Request) (Principal, bool, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		principal, err := a.AuthenticateAPIKey(key)
+		return principal, true, err
+	}
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, ok := bearerToken(header)
+		if !ok {
+			return Principal{}, true, fmt.Errorf("unsupported authorization scheme: %w", ErrUnauthenticated)
+		}
+		principal, err := a.AuthenticateToken(token)
+		return principal, true, err
+	}
+	return Principal{}, false, nil
+}
+
+// Require rejects the requests whose principal is not allowed the role.
+func Require(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := Authorize(r.Context(), role); err != nil {
+				writeError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken returns the token of a bearer Authorization header.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// writeError writes the error with 403 Forbidden when the caller lacks a role,
+// and 401 Unauthorized otherwise.
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrPermissionDenied) {
+		w.WriteHeader(http.StatusForbidden)
+	} else {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	mhttp.EncodeResponse(w, err.Error())
+}
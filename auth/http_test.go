@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHttpMiddleware(t *testing.T) {
+	Convey("Given a handler requiring the editor role", t, func() {
+		authenticator := New(
+			WithHMACSecret(testSecret),
+			WithAPIKeys(APIKey{Name: "quotes", Role: RoleReader, Key: "quotes-key"}),
+		)
+		var principal Principal
+		handler := authenticator.HttpMiddleware(Require(RoleEditor)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ = PrincipalFromContext(r.Context())
+		})))
+		serve := func(header string, value string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/author", nil)
+			if header != "" {
+				req.Header.Set(header, value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
+
+		Convey("Requests with a bearer token of an editor should pass", func() {
+			rr := serve("Authorization", "Bearer "+signToken(testSecret, validClaims("ada", "editor")))
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(principal.Subject, ShouldEqual, "ada")
+		})
+
+		Convey("Requests without credentials should be unauthorized", func() {
+			rr := serve("", "")
+			So(rr.Code, ShouldEqual, http.StatusUnauthorized)
+			So(rr.Header().Get("WWW-Authenticate"), ShouldEqual, "Bearer")
+		})
+
+		Convey("Requests with invalid credentials should be unauthorized", func() {
+			So(serve("Authorization", "Bearer invalid").Code, ShouldEqual, http.StatusUnauthorized)
+			So(serve("Authorization", "Basic YWRhOnNlY3JldA==").Code, ShouldEqual, http.StatusUnauthorized)
+			So(serve(APIKeyHeader, "other-key").Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Requests of a caller without the role should be forbidden", func() {
+			So(serve(APIKeyHeader, "quotes-key").Code, ShouldEqual, http.StatusForbidden)
+			So(serve("Authorization", "Bearer "+signToken(testSecret, validClaims("ada"))).Code, ShouldEqual, http.StatusForbidden)
+		})
+	})
+
+	Convey("Public routes should accept requests without credentials", t, func() {
+		handler := New(WithHMACSecret(testSecret)).HttpMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		So(rr.Code, ShouldEqual, http.StatusOK)
+	})
+}
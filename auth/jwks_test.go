@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// encodeInt encodes a big-endian integer in base64url like a JWK.
+func encodeInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// jwksOf returns the JWKS of the keys.
+func jwksOf(keys ...map[string]string) []byte {
+	content, _ := json.Marshal(map[string]interface{}{"keys": keys})
+	return content
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaJWK := map[string]string{
+		"kty": "RSA", "kid": "rsa", "use": "sig",
+		"n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E))),
+	}
+	ecJWK := map[string]string{
+		"kty": "EC", "kid": "ec", "crv": "P-256",
+		"x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y),
+	}
+
+	Convey("Given a JWKS file with an RSA and an ECDSA key", t, func() {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		So(os.WriteFile(path, jwksOf(rsaJWK, ecJWK, map[string]string{"kty": "oct", "kid": "hmac"}), 0o600), ShouldBeNil)
+		keys, err := LoadJWKS(path)
+		So(err, ShouldBeNil)
+		authenticator := New(WithJWKS(keys))
+
+		Convey("Keys of unsupported types should be skipped", func() {
+			So(keys, ShouldHaveLength, 2)
+		})
+
+		Convey("Tokens signed with the RSA key should be accepted", func() {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims("ada", "reader"))
+			token.Header["kid"] = "rsa"
+			signed, _ := token.SignedString(rsaKey)
+
+			principal, err := authenticator.AuthenticateToken(signed)
+			So(err, ShouldBeNil)
+			So(principal.Subject, ShouldEqual, "ada")
+		})
+
+		Convey("Tokens signed with the ECDSA key should be accepted", func() {
+			token := jwt.NewWithClaims(jwt.SigningMethodES256, validClaims("ada", "reader"))
+			token.Header["kid"] = "ec"
+			signed, _ := token.SignedString(ecKey)
+
+			_, err := authenticator.AuthenticateToken(signed)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Tokens naming an unknown or another key should fail", func() {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims("ada", "reader"))
+			token.Header["kid"] = "ec"
+			signed, _ := token.SignedString(rsaKey)
+			_, err := authenticator.AuthenticateToken(signed)
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+
+			token.Header["kid"] = "other"
+			signed, _ = token.SignedString(rsaKey)
+			_, err = authenticator.AuthenticateToken(signed)
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+
+		Convey("HMAC tokens should fail", func() {
+			_, err := authenticator.AuthenticateToken(signToken(testSecret, validClaims("ada", "admin")))
+			So(errors.Is(err, ErrUnauthenticated), ShouldBeTrue)
+		})
+	})
+
+	Convey("Tokens without key ID should use the only key of the set", t, func() {
+		keys, err := ParseJWKS(jwksOf(rsaJWK))
+		So(err, ShouldBeNil)
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims("ada", "reader")).SignedString(rsaKey)
+
+		_, err = New(WithJWKS(keys)).AuthenticateToken(signed)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Invalid JWKS should fail", t, func() {
+		_, err := ParseJWKS([]byte("not json"))
+		So(err, ShouldNotBeNil)
+
+		_, err = ParseJWKS(jwksOf())
+		So(err, ShouldNotBeNil)
+
+		_, err = ParseJWKS(jwksOf(map[string]string{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.X)}))
+		So(err, ShouldNotBeNil)
+
+		_, err = LoadJWKS(filepath.Join(t.TempDir(), "missing.json"))
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"github.com/wcodesoft/mosha-author-service/service"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// commands are the subcommands of the service binary, run instead of the
+// service when named by the first argument.
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"export": exportCommand,
+	"import": importCommand,
+}
+
+// runCommand runs the subcommand named by the first argument and returns the
+// exit code of the binary.
+func runCommand(args []string) int {
+	command, ok := commands[args[0]]
+	if !ok {
+		log.Errorf("unknown command %q, expected export or import", args[0])
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := command(ctx, args[1:]); err != nil {
+		log.Error(args[0]+" failed", "err", err)
+		return 1
+	}
+	return 0
+}
+
+// exportCommand writes the authors of the database to a file or the standard output.
+func exportCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := flags.String("format", string(repository.FormatNDJSON), "format of the authors, ndjson or csv")
+	output := flags.String("output", "-", "file the authors are written to, - for the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	format, err := repository.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	db, _, closeStorage, err := openCommandStorage()
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	count, err := repository.ExportAuthors(ctx, db, w, format)
+	if err != nil {
+		return err
+	}
+	log.Infof("exported %d authors", count)
+	return nil
+}
+
+// importCommand adds the authors of a file or the standard input to the
+// database, publishing their events, and writes the report of the import to
+// the standard output.
+func importCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatName := flags.String("format", string(repository.FormatNDJSON), "format of the authors, ndjson or csv")
+	input := flags.String("input", "-", "file the authors are read from, - for the standard input")
+	modeName := flags.String("mode", string(repository.ImportUpsert), "upsert to replace the existing authors, insert to fail their rows")
+	dryRun := flags.Bool("dry-run", false, "check the rows without storing anything")
+	actor := flags.String("actor", "import", "actor recorded in the audit log")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	format, err := repository.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	mode, err := repository.ParseImportMode(*modeName)
+	if err != nil {
+		return err
+	}
+
+	r := io.Reader(os.Stdin)
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	db, audit, closeStorage, err := openCommandStorage()
+	if err != nil {
+		return err
+	}
+	defer closeStorage()
+
+	// The import runs through the service, as it does over HTTP, so that the
+	// imported authors are published to the broker of EVENTS_NATS_URL. It does
+	// not delete quotes, so the repository has no quote service client.
+	var repoOptions []repository.Option
+	if audit != nil {
+		repoOptions = append(repoOptions, repository.WithAudit(audit))
+	}
+	var serviceOptions []service.Option
+	if publisher := setupEventPublisher(); publisher != nil {
+		defer func() {
+			if err := publisher.Close(); err != nil {
+				log.Error("unable to close the event publisher", "err", err)
+			}
+		}()
+		serviceOptions = append(serviceOptions, service.WithPublisher(publisher))
+	}
+	s := service.New(repository.New(db, nil, repoOptions...), serviceOptions...)
+
+	report, err := s.ImportAuthors(repository.WithActor(ctx, *actor), r, format, repository.ImportOptions{
+		Mode:   mode,
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows were not imported", report.Failed)
+	}
+	return nil
+}
+
+// openCommandStorage opens the storage of STORAGE_BACKEND, which must outlive
+// the command, and returns the authors with their revisions, the audit log and
+// the function closing the storage.
+func openCommandStorage() (repository.Database, repository.AuditStore, func(), error) {
+	backend := getEnv("STORAGE_BACKEND", storageMongo)
+	if backend == storageMemory {
+		return nil, nil, nil, fmt.Errorf("STORAGE_BACKEND %s is lost once the command exits", storageMemory)
+	}
+	storage, err := openStorage(backend)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closeStorage := func() {
+		if err := storage.Close(context.Background()); err != nil {
+			log.Error("unable to close the storage", "err", err)
+		}
+	}
+	return storage.Database, storage.Audit, closeStorage, nil
+}
@@ -1,6 +1,9 @@
 package data
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // Author represents an author.
 type Author struct {
@@ -10,6 +13,31 @@ type Author struct {
 	Name string `json:"name"`
 	//	PicURL is the URL of the author's picture.
 	PicURL string `json:"picUrl"`
+	// Biography is a short biography of the author.
+	Biography string `json:"biography,omitempty"`
+	// BirthDate is the date of birth of the author, if known.
+	BirthDate *PartialDate `json:"birthDate,omitempty"`
+	// DeathDate is the date of death of the author, if known.
+	DeathDate *PartialDate `json:"deathDate,omitempty"`
+	// Nationality is the nationality of the author.
+	Nationality string `json:"nationality,omitempty"`
+	// Occupations are the occupations of the author, such as "poet" or "philosopher".
+	Occupations []string `json:"occupations,omitempty"`
+	// AlternateNames are the other names the author is known by.
+	AlternateNames []string `json:"alternateNames,omitempty"`
+	// Version is incremented on each change of the author. It is managed by the
+	// database and ignored when the author is created or updated.
+	Version int64 `json:"version"`
+	// DeletedAt is when the author was deleted, nil while it is not. Deleted
+	// authors are kept until they are purged.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	// DeletedBy is who deleted the author.
+	DeletedBy string `json:"deletedBy,omitempty"`
+}
+
+// IsDeleted reports whether the author was deleted and not restored.
+func (a Author) IsDeleted() bool {
+	return a.DeletedAt != nil
 }
 
 // AuthorBuilder is the interface that builds an author.
@@ -17,13 +45,26 @@ type AuthorBuilder interface {
 	WithId(id string) AuthorBuilder
 	WithName(name string) AuthorBuilder
 	WithPicUrl(picUrl string) AuthorBuilder
+	WithBiography(biography string) AuthorBuilder
+	WithBirthDate(date PartialDate) AuthorBuilder
+	WithDeathDate(date PartialDate) AuthorBuilder
+	WithNationality(nationality string) AuthorBuilder
+	WithOccupations(occupations ...string) AuthorBuilder
+	WithAlternateNames(names ...string) AuthorBuilder
 	Build() Author
+	BuildValidated() (Author, error)
 }
 
 type authorBuilder struct {
-	id     string
-	name   string
-	picUrl string
+	id             string
+	name           string
+	picUrl         string
+	biography      string
+	birthDate      *PartialDate
+	deathDate      *PartialDate
+	nationality    string
+	occupations    []string
+	alternateNames []string
 }
 
 // NewAuthorBuilder creates a new author builder.
@@ -53,11 +94,80 @@ func (ab *authorBuilder) WithPicUrl(picUrl string) AuthorBuilder {
 	return ab
 }
 
+// WithBiography sets the biography of the author.
+func (ab *authorBuilder) WithBiography(biography string) AuthorBuilder {
+	ab.biography = biography
+	return ab
+}
+
+// WithBirthDate sets the date of birth of the author.
+func (ab *authorBuilder) WithBirthDate(date PartialDate) AuthorBuilder {
+	ab.birthDate = &date
+	return ab
+}
+
+// WithDeathDate sets the date of death of the author.
+func (ab *authorBuilder) WithDeathDate(date PartialDate) AuthorBuilder {
+	ab.deathDate = &date
+	return ab
+}
+
+// WithNationality sets the nationality of the author.
+func (ab *authorBuilder) WithNationality(nationality string) AuthorBuilder {
+	ab.nationality = nationality
+	return ab
+}
+
+// WithOccupations sets the occupations of the author.
+func (ab *authorBuilder) WithOccupations(occupations ...string) AuthorBuilder {
+	ab.occupations = occupations
+	return ab
+}
+
+// WithAlternateNames sets the other names the author is known by.
+func (ab *authorBuilder) WithAlternateNames(names ...string) AuthorBuilder {
+	ab.alternateNames = names
+	return ab
+}
+
 // Build builds the author.
 func (ab *authorBuilder) Build() Author {
 	return Author{
-		ID:     ab.id,
-		Name:   ab.name,
-		PicURL: ab.picUrl,
+		ID:             ab.id,
+		Name:           ab.name,
+		PicURL:         ab.picUrl,
+		Biography:      ab.biography,
+		BirthDate:      copyDate(ab.birthDate),
+		DeathDate:      copyDate(ab.deathDate),
+		Nationality:    ab.nationality,
+		Occupations:    copyStrings(ab.occupations),
+		AlternateNames: copyStrings(ab.alternateNames),
+	}
+}
+
+// BuildValidated builds the author and validates it, returning a *ValidationError
+// listing the invalid fields.
+func (ab *authorBuilder) BuildValidated() (Author, error) {
+	author := ab.Build()
+	if err := author.Validate(); err != nil {
+		return Author{}, err
+	}
+	return author, nil
+}
+
+// copyDate returns a copy of the date so built authors do not share it.
+func copyDate(date *PartialDate) *PartialDate {
+	if date == nil {
+		return nil
+	}
+	c := *date
+	return &c
+}
+
+// copyStrings returns a copy of the values so built authors do not share them.
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
 	}
+	return append([]string{}, values...)
 }
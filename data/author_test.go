@@ -45,6 +45,54 @@ func TestAuthor(t *testing.T) {
 			})
 		})
 
+		Convey("When building an author with a profile", func() {
+			birth := PartialDate{Year: 1828, Month: 9, Day: 9}
+			death := PartialDate{Year: 1910, Month: 11, Day: 20}
+			author := builder.
+				WithBiography("Russian writer").
+				WithBirthDate(birth).
+				WithDeathDate(death).
+				WithNationality("Russian").
+				WithOccupations("novelist", "philosopher").
+				WithAlternateNames("Lev Tolstoy").
+				Build()
+
+			Convey("The author should be initialized with the given profile", func() {
+				So(author.Biography, ShouldEqual, "Russian writer")
+				So(*author.BirthDate, ShouldResemble, birth)
+				So(*author.DeathDate, ShouldResemble, death)
+				So(author.Nationality, ShouldEqual, "Russian")
+				So(author.Occupations, ShouldResemble, []string{"novelist", "philosopher"})
+				So(author.AlternateNames, ShouldResemble, []string{"Lev Tolstoy"})
+			})
+
+			Convey("Authors built with the same builder should not share the profile", func() {
+				other := builder.Build()
+				other.BirthDate.Year = 1900
+				other.Occupations[0] = "poet"
+				So(author.BirthDate.Year, ShouldEqual, 1828)
+				So(author.Occupations[0], ShouldEqual, "novelist")
+			})
+		})
+
+		Convey("When building a valid author with validation", func() {
+			name := faker.Name()
+			author, err := builder.WithName(name).BuildValidated()
+
+			Convey("The author should be built without error", func() {
+				So(err, ShouldBeNil)
+				So(author.Name, ShouldEqual, name)
+			})
+		})
+
+		Convey("When building an invalid author with validation", func() {
+			_, err := builder.WithPicUrl("invalid").BuildValidated()
+
+			Convey("The validation error should be returned", func() {
+				So(err, ShouldHaveSameTypeAs, &ValidationError{})
+			})
+		})
+
 		Convey("Two authors built with the same builder should be equal", func() {
 			author1 := builder.Build()
 			author2 := builder.Build()
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// partialDatePattern matches dates such as "1828-09-09", "1828-09", "c. 400 BC" or "44-03-15 BC".
+var partialDatePattern = regexp.MustCompile(`^(c\. )?(\d{1,4})(?:-(\d{2})(?:-(\d{2}))?)?( BC)?$`)
+
+// PartialDate represents a date that may only be known up to the year or the month,
+// and may be approximate.
+type PartialDate struct {
+	// Year is the year of the date, negative before Christ. There is no year zero.
+	Year int
+	// Month is the month of the date, from 1 to 12, or 0 when unknown.
+	Month int
+	// Day is the day of the month, or 0 when unknown.
+	Day int
+	// Circa reports whether the date is approximate.
+	Circa bool
+}
+
+// ParsePartialDate parses a date formatted as "[c. ]YYYY[-MM[-DD]][ BC]".
+func ParsePartialDate(value string) (PartialDate, error) {
+	matches := partialDatePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return PartialDate{}, fmt.Errorf("invalid partial date %q", value)
+	}
+	date := PartialDate{Circa: matches[1] != ""}
+	date.Year, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		date.Month, _ = strconv.Atoi(matches[3])
+	}
+	if matches[4] != "" {
+		date.Day, _ = strconv.Atoi(matches[4])
+	}
+	if matches[5] != "" {
+		date.Year = -date.Year
+	}
+	if err := date.Validate(); err != nil {
+		return PartialDate{}, err
+	}
+	return date, nil
+}
+
+// Validate checks that the date exists.
+func (d PartialDate) Validate() error {
+	if d.Year == 0 {
+		return fmt.Errorf("year must not be zero")
+	}
+	if d.Month < 0 || d.Month > 12 {
+		return fmt.Errorf("month must be between 1 and 12")
+	}
+	if d.Day != 0 {
+		if d.Month == 0 {
+			return fmt.Errorf("day requires a month")
+		}
+		// The proleptic Gregorian calendar, which has a year zero, is used to know
+		// the number of days in the month.
+		year := d.Year
+		if year < 0 {
+			year++
+		}
+		days := time.Date(year, time.Month(d.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if d.Day < 0 || d.Day > days {
+			return fmt.Errorf("day must be between 1 and %d", days)
+		}
+	}
+	return nil
+}
+
+// Before reports whether the date is before the other one, comparing only the
+// parts known in both dates.
+func (d PartialDate) Before(other PartialDate) bool {
+	if d.Year != other.Year {
+		return d.Year < other.Year
+	}
+	if d.Month == 0 || other.Month == 0 || d.Month != other.Month {
+		return d.Month != 0 && other.Month != 0 && d.Month < other.Month
+	}
+	return d.Day != 0 && other.Day != 0 && d.Day < other.Day
+}
+
+// String formats the date as "[c. ]YYYY[-MM[-DD]][ BC]".
+func (d PartialDate) String() string {
+	var b strings.Builder
+	if d.Circa {
+		b.WriteString("c. ")
+	}
+	year := d.Year
+	if year < 0 {
+		year = -year
+	}
+	b.WriteString(strconv.Itoa(year))
+	if d.Month != 0 {
+		fmt.Fprintf(&b, "-%02d", d.Month)
+		if d.Day != 0 {
+			fmt.Fprintf(&b, "-%02d", d.Day)
+		}
+	}
+	if d.Year < 0 {
+		b.WriteString(" BC")
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes the date as its string representation.
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes the date from its string representation.
+func (d *PartialDate) UnmarshalJSON(raw []byte) error {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	date, err := ParsePartialDate(value)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPartialDate(t *testing.T) {
+	Convey("When parsing partial dates", t, func() {
+		cases := map[string]PartialDate{
+			"1828-09-09":  {Year: 1828, Month: 9, Day: 9},
+			"1828-09":     {Year: 1828, Month: 9},
+			"1828":        {Year: 1828},
+			"c. 400 BC":   {Year: -400, Circa: true},
+			"44-03-15 BC": {Year: -44, Month: 3, Day: 15},
+			"c. 1340":     {Year: 1340, Circa: true},
+		}
+		for value, expected := range cases {
+			date, err := ParsePartialDate(value)
+			So(err, ShouldBeNil)
+			So(date, ShouldResemble, expected)
+			So(date.String(), ShouldEqual, value)
+		}
+	})
+
+	Convey("When parsing invalid partial dates", t, func() {
+		for _, value := range []string{"", "yesterday", "1828-13", "1900-02-29", "0", "1828-9-9", "c.400 BC"} {
+			_, err := ParsePartialDate(value)
+			So(err, ShouldNotBeNil)
+		}
+	})
+
+	Convey("When validating leap days", t, func() {
+		So(PartialDate{Year: 2000, Month: 2, Day: 29}.Validate(), ShouldBeNil)
+		So(PartialDate{Year: -1, Month: 2, Day: 29}.Validate(), ShouldBeNil)
+		So(PartialDate{Year: 1, Month: 2, Day: 29}.Validate(), ShouldNotBeNil)
+		So(PartialDate{Year: 1900, Day: 1}.Validate(), ShouldNotBeNil)
+	})
+
+	Convey("When comparing partial dates", t, func() {
+		So(PartialDate{Year: -400}.Before(PartialDate{Year: 1}), ShouldBeTrue)
+		So(PartialDate{Year: 1828, Month: 9}.Before(PartialDate{Year: 1828, Month: 10}), ShouldBeTrue)
+		So(PartialDate{Year: 1828, Month: 9, Day: 1}.Before(PartialDate{Year: 1828, Month: 9, Day: 2}), ShouldBeTrue)
+		So(PartialDate{Year: 1828}.Before(PartialDate{Year: 1828, Month: 10}), ShouldBeFalse)
+		So(PartialDate{Year: 1910}.Before(PartialDate{Year: 1828}), ShouldBeFalse)
+	})
+
+	Convey("When encoding partial dates to JSON", t, func() {
+		date := PartialDate{Year: -400, Circa: true}
+		raw, err := json.Marshal(date)
+		So(err, ShouldBeNil)
+		So(string(raw), ShouldEqual, `"c. 400 BC"`)
+
+		var decoded PartialDate
+		So(json.Unmarshal(raw, &decoded), ShouldBeNil)
+		So(decoded, ShouldResemble, date)
+
+		So(json.Unmarshal([]byte(`"invalid"`), &decoded), ShouldNotBeNil)
+		So(json.Unmarshal([]byte(`1828`), &decoded), ShouldNotBeNil)
+	})
+}
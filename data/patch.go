@@ -0,0 +1,122 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Names of the author fields that can be patched, as named in JSON.
+const (
+	FieldName           = "name"
+	FieldPicURL         = "picUrl"
+	FieldBiography      = "biography"
+	FieldBirthDate      = "birthDate"
+	FieldDeathDate      = "deathDate"
+	FieldNationality    = "nationality"
+	FieldOccupations    = "occupations"
+	FieldAlternateNames = "alternateNames"
+)
+
+// PatchableFields are the author fields that can be changed by a patch.
+var PatchableFields = []string{
+	FieldName,
+	FieldPicURL,
+	FieldBiography,
+	FieldBirthDate,
+	FieldDeathDate,
+	FieldNationality,
+	FieldOccupations,
+	FieldAlternateNames,
+}
+
+// AuthorPatch represents a partial update of an author. Only the fields listed
+// in Fields are changed, to the value they have in Author. A listed field with
+// an empty value is cleared.
+type AuthorPatch struct {
+	// Author holds the new values of the patched fields.
+	Author Author
+	// Fields are the names of the patched fields.
+	Fields []string
+}
+
+// ParseMergePatch parses a JSON Merge Patch (RFC 7396) of an author. Fields
+// set to null are cleared and fields absent from the document are left as is.
+func ParseMergePatch(raw []byte) (AuthorPatch, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return AuthorPatch{}, fmt.Errorf("invalid merge patch: %v", err)
+	}
+	var patch AuthorPatch
+	if err := json.Unmarshal(raw, &patch.Author); err != nil {
+		return AuthorPatch{}, fmt.Errorf("invalid merge patch: %v", err)
+	}
+	for field := range members {
+		patch.Fields = append(patch.Fields, field)
+	}
+	sort.Strings(patch.Fields)
+	if err := patch.Validate(); err != nil {
+		return AuthorPatch{}, err
+	}
+	return patch, nil
+}
+
+// Validate checks that the patch changes at least one field and only fields
+// that can be patched.
+func (p AuthorPatch) Validate() error {
+	verr := &ValidationError{}
+	if len(p.Fields) == 0 {
+		verr.add("fields", "must not be empty")
+	}
+	for _, field := range p.Fields {
+		if !isPatchable(field) {
+			verr.add(field, "cannot be patched")
+		}
+	}
+	return verr.err()
+}
+
+// Has reports whether the patch changes the field.
+func (p AuthorPatch) Has(field string) bool {
+	for _, f := range p.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the author with the patched fields changed.
+func (p AuthorPatch) Apply(author Author) Author {
+	for _, field := range p.Fields {
+		switch field {
+		case FieldName:
+			author.Name = p.Author.Name
+		case FieldPicURL:
+			author.PicURL = p.Author.PicURL
+		case FieldBiography:
+			author.Biography = p.Author.Biography
+		case FieldBirthDate:
+			author.BirthDate = copyDate(p.Author.BirthDate)
+		case FieldDeathDate:
+			author.DeathDate = copyDate(p.Author.DeathDate)
+		case FieldNationality:
+			author.Nationality = p.Author.Nationality
+		case FieldOccupations:
+			author.Occupations = copyStrings(p.Author.Occupations)
+		case FieldAlternateNames:
+			author.AlternateNames = copyStrings(p.Author.AlternateNames)
+		}
+	}
+	return author
+}
+
+// isPatchable reports whether the field can be changed by a patch.
+func isPatchable(field string) bool {
+	for _, f := range PatchableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
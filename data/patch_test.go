@@ -0,0 +1,81 @@
+package data
+
+import (
+	"testing"
+
+	faker "github.com/brianvoe/gofakeit/v6"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPatch(t *testing.T) {
+	author := NewAuthorBuilder().
+		WithName(faker.Name()).
+		WithPicUrl(faker.ImageURL(100, 100)).
+		WithBiography(faker.Sentence(5)).
+		WithOccupations("poet").
+		Build()
+
+	Convey("When parsing a merge patch", t, func() {
+		Convey("Only the fields in the document should be patched", func() {
+			patch, err := ParseMergePatch([]byte(`{"name":"Rumi","birthDate":"1207-09-30","picUrl":null}`))
+			So(err, ShouldBeNil)
+			So(patch.Fields, ShouldResemble, []string{FieldBirthDate, FieldName, FieldPicURL})
+			So(patch.Has(FieldName), ShouldBeTrue)
+			So(patch.Has(FieldBiography), ShouldBeFalse)
+
+			patched := patch.Apply(author)
+			So(patched.ID, ShouldEqual, author.ID)
+			So(patched.Name, ShouldEqual, "Rumi")
+			So(*patched.BirthDate, ShouldResemble, PartialDate{Year: 1207, Month: 9, Day: 30})
+			So(patched.PicURL, ShouldBeEmpty)
+			So(patched.Biography, ShouldEqual, author.Biography)
+			So(patched.Occupations, ShouldResemble, author.Occupations)
+		})
+
+		Convey("A null list should clear it", func() {
+			patch, err := ParseMergePatch([]byte(`{"occupations":null}`))
+			So(err, ShouldBeNil)
+			So(patch.Apply(author).Occupations, ShouldBeNil)
+		})
+
+		Convey("Fields that cannot be patched should be rejected", func() {
+			_, err := ParseMergePatch([]byte(`{"id":"other","unknown":1}`))
+			So(err, ShouldHaveSameTypeAs, &ValidationError{})
+			So(err.(*ValidationError).Fields, ShouldResemble, []FieldError{
+				{Field: "id", Message: "cannot be patched"},
+				{Field: "unknown", Message: "cannot be patched"},
+			})
+		})
+
+		Convey("An empty patch should be rejected", func() {
+			_, err := ParseMergePatch([]byte(`{}`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A document that is not an object should be rejected", func() {
+			_, err := ParseMergePatch([]byte(`["name"]`))
+			So(err, ShouldNotBeNil)
+			_, err = ParseMergePatch([]byte(`{"name":1}`))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When applying a patch", t, func() {
+		patch := AuthorPatch{
+			Author: Author{Nationality: "Persian", Occupations: []string{"poet", "scholar"}},
+			Fields: []string{FieldNationality, FieldOccupations},
+		}
+		patched := patch.Apply(author)
+
+		Convey("The patched fields should change", func() {
+			So(patched.Nationality, ShouldEqual, "Persian")
+			So(patched.Occupations, ShouldResemble, []string{"poet", "scholar"})
+			So(patched.Name, ShouldEqual, author.Name)
+		})
+
+		Convey("The patched author should not share the patch values", func() {
+			patch.Author.Occupations[0] = "mystic"
+			So(patched.Occupations[0], ShouldEqual, "poet")
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package data
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxIDLength is the maximum length of an author ID.
+	MaxIDLength = 64
+	// MaxNameLength is the maximum length of an author name.
+	MaxNameLength = 256
+	// MaxPicURLLength is the maximum length of an author picture URL.
+	MaxPicURLLength = 2048
+	// MaxBiographyLength is the maximum length of an author biography.
+	MaxBiographyLength = 10000
+	// MaxNationalityLength is the maximum length of an author nationality.
+	MaxNationalityLength = 100
+	// MaxOccupationLength is the maximum length of each author occupation.
+	MaxOccupationLength = 100
+	// MaxOccupations is the maximum number of occupations of an author.
+	MaxOccupations = 20
+	// MaxAlternateNames is the maximum number of alternate names of an author.
+	MaxAlternateNames = 50
+)
+
+// idPattern is the format of an author ID, which covers UUIDs and Mongo object IDs.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FieldError describes why a field of an author is not valid.
+type FieldError struct {
+	// Field is the JSON name of the invalid field.
+	Field string `json:"field"`
+	// Message describes why the field is not valid.
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when an author is not valid.
+type ValidationError struct {
+	// Fields are the errors of each invalid field.
+	Fields []FieldError `json:"fields"`
+}
+
+// Error returns the description of all invalid fields.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for index, field := range e.Fields {
+		messages[index] = fmt.Sprintf("%s %s", field.Field, field.Message)
+	}
+	return fmt.Sprintf("invalid author: %s", strings.Join(messages, "; "))
+}
+
+// add records an invalid field.
+func (e *ValidationError) add(field string, format string, a ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// err returns the validation error when at least one field is invalid.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the author fields and returns a *ValidationError listing the invalid ones.
+func (a Author) Validate() error {
+	verr := &ValidationError{}
+
+	switch {
+	case a.ID == "":
+		verr.add("id", "must not be empty")
+	case len(a.ID) > MaxIDLength:
+		verr.add("id", "must be at most %d characters", MaxIDLength)
+	case !idPattern.MatchString(a.ID):
+		verr.add("id", "must only contain letters, digits, '-' and '_'")
+	}
+
+	switch {
+	case strings.TrimSpace(a.Name) == "":
+		verr.add("name", "must not be blank")
+	case utf8.RuneCountInString(a.Name) > MaxNameLength:
+		verr.add("name", "must be at most %d characters", MaxNameLength)
+	}
+
+	if a.PicURL != "" {
+		if len(a.PicURL) > MaxPicURLLength {
+			verr.add("picUrl", "must be at most %d characters", MaxPicURLLength)
+		} else if !isHttpURL(a.PicURL) {
+			verr.add("picUrl", "must be a valid http or https URL")
+		}
+	}
+
+	if utf8.RuneCountInString(a.Biography) > MaxBiographyLength {
+		verr.add("biography", "must be at most %d characters", MaxBiographyLength)
+	}
+	if utf8.RuneCountInString(a.Nationality) > MaxNationalityLength {
+		verr.add("nationality", "must be at most %d characters", MaxNationalityLength)
+	}
+	validateList(verr, "occupations", a.Occupations, MaxOccupations, MaxOccupationLength)
+	validateList(verr, "alternateNames", a.AlternateNames, MaxAlternateNames, MaxNameLength)
+
+	if a.BirthDate != nil {
+		if err := a.BirthDate.Validate(); err != nil {
+			verr.add("birthDate", "%v", err)
+		}
+	}
+	if a.DeathDate != nil {
+		if err := a.DeathDate.Validate(); err != nil {
+			verr.add("deathDate", "%v", err)
+		} else if a.BirthDate != nil && a.DeathDate.Before(*a.BirthDate) {
+			verr.add("deathDate", "must not be before the birth date")
+		}
+	}
+
+	return verr.err()
+}
+
+// validateList checks the number of values and that each of them is not blank
+// and not too long.
+func validateList(verr *ValidationError, field string, values []string, maxValues int, maxLength int) {
+	if len(values) > maxValues {
+		verr.add(field, "must have at most %d values", maxValues)
+		return
+	}
+	for index, value := range values {
+		switch {
+		case strings.TrimSpace(value) == "":
+			verr.add(fmt.Sprintf("%s[%d]", field, index), "must not be blank")
+		case utf8.RuneCountInString(value) > maxLength:
+			verr.add(fmt.Sprintf("%s[%d]", field, index), "must be at most %d characters", maxLength)
+		}
+	}
+}
+
+// isHttpURL reports whether the value is an absolute http(s) URL.
+func isHttpURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,105 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	faker "github.com/brianvoe/gofakeit/v6"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidation(t *testing.T) {
+	Convey("When validating an author", t, func() {
+		author := Author{ID: faker.UUID(), Name: faker.Name(), PicURL: faker.ImageURL(100, 100)}
+
+		Convey("A complete author should be valid", func() {
+			So(author.Validate(), ShouldBeNil)
+		})
+
+		Convey("An author without picture should be valid", func() {
+			author.PicURL = ""
+			So(author.Validate(), ShouldBeNil)
+		})
+
+		Convey("A blank name should be invalid", func() {
+			author.Name = "  "
+			err := author.Validate()
+			So(err, ShouldHaveSameTypeAs, &ValidationError{})
+			So(err.(*ValidationError).Fields, ShouldResemble, []FieldError{{Field: "name", Message: "must not be blank"}})
+		})
+
+		Convey("A name that is too long should be invalid", func() {
+			author.Name = strings.Repeat("a", MaxNameLength+1)
+			err := author.Validate().(*ValidationError)
+			So(err.Fields[0].Field, ShouldEqual, "name")
+		})
+
+		Convey("A picture URL that is not http should be invalid", func() {
+			for _, picUrl := range []string{"not a url", "ftp://example.com/pic.png", "https://", "/pic.png"} {
+				author.PicURL = picUrl
+				err := author.Validate().(*ValidationError)
+				So(err.Fields[0].Field, ShouldEqual, "picUrl")
+			}
+		})
+
+		Convey("A picture URL that is too long should be invalid", func() {
+			author.PicURL = "https://example.com/" + strings.Repeat("a", MaxPicURLLength)
+			err := author.Validate().(*ValidationError)
+			So(err.Fields[0].Field, ShouldEqual, "picUrl")
+		})
+
+		Convey("A malformed ID should be invalid", func() {
+			for _, id := range []string{"", "with space", "../etc", strings.Repeat("a", MaxIDLength+1)} {
+				author.ID = id
+				err := author.Validate().(*ValidationError)
+				So(err.Fields[0].Field, ShouldEqual, "id")
+			}
+		})
+
+		Convey("A complete profile should be valid", func() {
+			author.Biography = "Greek philosopher"
+			author.BirthDate = &PartialDate{Year: -428, Circa: true}
+			author.DeathDate = &PartialDate{Year: -348}
+			author.Nationality = "Greek"
+			author.Occupations = []string{"philosopher"}
+			author.AlternateNames = []string{"Aristocles"}
+			So(author.Validate(), ShouldBeNil)
+		})
+
+		Convey("A death date before the birth date should be invalid", func() {
+			author.BirthDate = &PartialDate{Year: 1828}
+			author.DeathDate = &PartialDate{Year: 1800}
+			err := author.Validate().(*ValidationError)
+			So(err.Fields, ShouldResemble, []FieldError{{Field: "deathDate", Message: "must not be before the birth date"}})
+		})
+
+		Convey("An impossible date should be invalid", func() {
+			author.BirthDate = &PartialDate{Year: 1828, Month: 2, Day: 30}
+			err := author.Validate().(*ValidationError)
+			So(err.Fields[0].Field, ShouldEqual, "birthDate")
+		})
+
+		Convey("Blank or too many list values should be invalid", func() {
+			author.Occupations = []string{"poet", " "}
+			author.AlternateNames = make([]string, MaxAlternateNames+1)
+			err := author.Validate().(*ValidationError)
+			So(err.Fields, ShouldResemble, []FieldError{
+				{Field: "occupations[1]", Message: "must not be blank"},
+				{Field: "alternateNames", Message: "must have at most 50 values"},
+			})
+		})
+
+		Convey("Too long texts should be invalid", func() {
+			author.Biography = strings.Repeat("a", MaxBiographyLength+1)
+			author.Nationality = strings.Repeat("a", MaxNationalityLength+1)
+			err := author.Validate().(*ValidationError)
+			So(len(err.Fields), ShouldEqual, 2)
+		})
+
+		Convey("All invalid fields should be reported", func() {
+			err := Author{PicURL: "invalid"}.Validate()
+			So(len(err.(*ValidationError).Fields), ShouldEqual, 3)
+			So(err.Error(), ShouldEqual, "invalid author: id must not be empty; name must not be blank; picUrl must be a valid http or https URL")
+		})
+	})
+}
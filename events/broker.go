@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultBufferSize is the number of events a subscriber can fall behind
+// before its subscription ends.
+const DefaultBufferSize = 64
+
+// ErrSubscriberLagging ends the subscriptions that did not keep up with the
+// events. Their subscribers missed events and must reload the authors they need.
+var ErrSubscriberLagging = errors.New("subscriber is lagging behind the events")
+
+// Broker is an in-process publisher delivering the events to its subscribers.
+// Publishing never waits for a subscriber.
+type Broker struct {
+	mu          sync.Mutex
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// BrokerOption configures a Broker.
+type BrokerOption func(*Broker)
+
+// WithBufferSize sets the number of events a subscriber can fall behind,
+// DefaultBufferSize by default.
+func WithBufferSize(size int) BrokerOption {
+	return func(b *Broker) {
+		b.bufferSize = size
+	}
+}
+
+// NewBroker creates a new in-process broker.
+func NewBroker(opts ...BrokerOption) *Broker {
+	b := &Broker{
+		bufferSize:  DefaultBufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscribe returns a subscription to the events published from now on.
+func (b *Broker) Subscribe() *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscription := &Subscription{
+		broker: b,
+		events: make(chan Event, b.bufferSize),
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Publish delivers the event to the subscribers. The subscriptions whose
+// buffer is full end with ErrSubscriberLagging.
+func (b *Broker) Publish(_ context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			b.remove(subscription, ErrSubscriberLagging)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove ends the subscription with the error. The lock must be held.
+func (b *Broker) remove(subscription *Subscription, err error) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+// Subscription receives the events of a broker until it is closed.
+type Subscription struct {
+	broker *Broker
+	events chan Event
+	err    error
+}
+
+// Events returns the channel of the events, closed when the subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns why the subscription ended once its channel is closed, nil when
+// it was closed by its subscriber.
+func (s *Subscription) Err() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s, nil)
+}
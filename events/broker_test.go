@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a broker with two subscribers", t, func() {
+		broker := NewBroker(WithBufferSize(2))
+		first := broker.Subscribe()
+		second := broker.Subscribe()
+		So(broker.Subscribers(), ShouldEqual, 2)
+
+		Convey("Published events should be delivered to each subscriber in order", func() {
+			created := New(Created, "author", nil, now)
+			deleted := New(Deleted, "author", nil, now)
+			So(broker.Publish(ctx, created), ShouldBeNil)
+			So(broker.Publish(ctx, deleted), ShouldBeNil)
+			for _, subscription := range []*Subscription{first, second} {
+				So((<-subscription.Events()).ID, ShouldEqual, created.ID)
+				So((<-subscription.Events()).ID, ShouldEqual, deleted.ID)
+			}
+		})
+
+		Convey("A closed subscription should not receive the events", func() {
+			first.Close()
+			first.Close()
+			_, open := <-first.Events()
+			So(open, ShouldBeFalse)
+			So(first.Err(), ShouldBeNil)
+			So(broker.Subscribers(), ShouldEqual, 1)
+
+			So(broker.Publish(ctx, New(Created, "author", nil, now)), ShouldBeNil)
+			So((<-second.Events()).Type, ShouldEqual, Created)
+		})
+
+		Convey("A subscriber falling behind should be dropped without blocking the others", func() {
+			for i := 0; i < 3; i++ {
+				So(broker.Publish(ctx, New(Updated, "author", nil, now)), ShouldBeNil)
+				<-second.Events()
+			}
+			So(broker.Subscribers(), ShouldEqual, 1)
+			received := 0
+			for range first.Events() {
+				received++
+			}
+			So(received, ShouldEqual, 2)
+			So(first.Err(), ShouldEqual, ErrSubscriberLagging)
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+// Types of the author events.
+const (
+	// Created is published when an author is created.
+	Created = "created"
+	// Updated is published when an author is updated, patched, restored or reverted.
+	Updated = "updated"
+	// Deleted is published when an author is deleted.
+	Deleted = "deleted"
+)
+
+// Event is a change of an author published to the other services.
+type Event struct {
+	// ID identifies the event.
+	ID string `json:"id"`
+	// Type is the change, such as Updated.
+	Type string `json:"type"`
+	// AuthorID is the changed author.
+	AuthorID string `json:"authorId"`
+	// Version is the version of the author after the change, when known.
+	Version int64 `json:"version,omitempty"`
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+	// Actor is who made the change, when known.
+	Actor string `json:"actor,omitempty"`
+	// RequestID is the ID of the request that made the change.
+	RequestID string `json:"requestId,omitempty"`
+	// Author is the author after the change, absent when it was deleted.
+	Author *data.Author `json:"author,omitempty"`
+}
+
+// New creates an event of the author changed at the time. The author is nil
+// when it was deleted.
+func New(eventType string, authorID string, author *data.Author, now time.Time) Event {
+	event := Event{
+		ID:       uuid.New().String(),
+		Type:     eventType,
+		AuthorID: authorID,
+		Time:     now,
+		Author:   author,
+	}
+	if author != nil {
+		event.Version = author.Version
+	}
+	return event
+}
+
+// Publisher publishes the author events.
+type Publisher interface {
+	// Publish sends the event to the subscribers.
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("An event of a changed author should carry its version", t, func() {
+		event := New(Updated, "author", &data.Author{ID: "author", Name: "Ada", Version: 3}, now)
+		So(event.ID, ShouldNotBeEmpty)
+		So(event.Type, ShouldEqual, Updated)
+		So(event.AuthorID, ShouldEqual, "author")
+		So(event.Version, ShouldEqual, 3)
+		So(event.Time, ShouldEqual, now)
+		So(event.Author.Name, ShouldEqual, "Ada")
+	})
+
+	Convey("An event of a deleted author should not carry it", t, func() {
+		event := New(Deleted, "author", nil, now)
+		So(event.Author, ShouldBeNil)
+		So(event.Version, ShouldEqual, 0)
+		So(New(Deleted, "author", nil, now).ID, ShouldNotEqual, event.ID)
+	})
+}
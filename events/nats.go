@@ -0,0 +1,211 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultNATSTimeout bounds the sends without a deadline.
+const defaultNATSTimeout = 5 * time.Second
+
+// NATSTransport sends the messages to a NATS server using the core NATS
+// protocol. NATS has no partition key, so the key of the messages is ignored.
+//
+// Each send waits for the server to acknowledge it with a PONG. A send failing
+// on a broken connection is retried once on a new one, so that subscribers
+// may receive an event twice and should deduplicate them by ID.
+type NATSTransport struct {
+	address  string
+	user     string
+	password string
+	token    string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATSTransport creates a transport to the NATS server of the URL, such as
+// "nats://localhost:4222". The user info of the URL is sent as the credentials,
+// a user without a password being a token. The server is connected on the
+// first send.
+func NewNATSTransport(rawURL string) (*NATSTransport, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "nats://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if parsed.Scheme != "nats" {
+		return nil, fmt.Errorf("unsupported NATS URL scheme %q", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return nil, errors.New("invalid NATS URL: missing host")
+	}
+	t := &NATSTransport{address: parsed.Host}
+	if parsed.Port() == "" {
+		t.address = net.JoinHostPort(parsed.Hostname(), "4222")
+	}
+	if parsed.User != nil {
+		if password, ok := parsed.User.Password(); ok {
+			t.user = parsed.User.Username()
+			t.password = password
+		} else {
+			t.token = parsed.User.Username()
+		}
+	}
+	return t, nil
+}
+
+// Send publishes the message on its subject and waits for the server to
+// acknowledge it.
+func (t *NATSTransport) Send(ctx context.Context, message Message) error {
+	if message.Subject == "" || strings.ContainsAny(message.Subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject %q", message.Subject)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	reused := t.conn != nil
+	err := t.send(ctx, message)
+	if err != nil && reused && ctx.Err() == nil {
+		t.reset()
+		err = t.send(ctx, message)
+	}
+	if err != nil {
+		t.reset()
+	}
+	return err
+}
+
+// Close closes the connection to the server.
+func (t *NATSTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	t.reader = nil
+	return err
+}
+
+// send publishes the message, connecting to the server when needed.
+func (t *NATSTransport) send(ctx context.Context, message Message) error {
+	if t.conn == nil {
+		if err := t.connect(ctx); err != nil {
+			return err
+		}
+	}
+	t.setDeadline(ctx)
+	frame := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", message.Subject, len(message.Data), message.Data)
+	if _, err := t.conn.Write([]byte(frame)); err != nil {
+		return fmt.Errorf("unable to publish to NATS: %w", err)
+	}
+	return t.waitPong()
+}
+
+// connect opens the connection and sends the credentials, waiting for the
+// server to accept them.
+func (t *NATSTransport) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to NATS: %w", err)
+	}
+	t.conn = conn
+	t.reader = bufio.NewReader(conn)
+	t.setDeadline(ctx)
+
+	line, err := t.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting %q", line)
+	}
+	options, err := json.Marshal(t.connectOptions())
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(t.conn, "CONNECT %s\r\nPING\r\n", options); err != nil {
+		return fmt.Errorf("unable to connect to NATS: %w", err)
+	}
+	return t.waitPong()
+}
+
+// connectOptions returns the options of the CONNECT message.
+func (t *NATSTransport) connectOptions() map[string]interface{} {
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "mosha-author-service",
+		"lang":     "go",
+		"protocol": 1,
+	}
+	if t.user != "" {
+		options["user"] = t.user
+		options["pass"] = t.password
+	}
+	if t.token != "" {
+		options["auth_token"] = t.token
+	}
+	return options
+}
+
+// waitPong reads the messages of the server until its PONG, answering its PINGs.
+func (t *NATSTransport) waitPong() error {
+	for {
+		line, err := t.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := t.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("unable to answer NATS: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+		}
+	}
+}
+
+// readLine reads a message of the server without its line ending.
+func (t *NATSTransport) readLine() (string, error) {
+	line, err := t.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("unable to read from NATS: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// setDeadline bounds the exchange by the deadline of the context, or by the
+// default timeout.
+func (t *NATSTransport) setDeadline(ctx context.Context) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultNATSTimeout)
+	}
+	_ = t.conn.SetDeadline(deadline)
+}
+
+// reset drops the connection after a failure.
+func (t *NATSTransport) reset() {
+	if t.conn != nil {
+		_ = t.conn.Close()
+	}
+	t.conn = nil
+	t.reader = nil
+}
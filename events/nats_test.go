@@ -0,0 +1,171 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// natsStandIn is a local stand-in of a NATS server recording the options of
+// the clients and the messages they publish.
+type natsStandIn struct {
+	listener net.Listener
+	connects chan map[string]interface{}
+	messages chan Message
+	// connectErr is the error answered to the CONNECT of the clients.
+	connectErr string
+	// dropAfter closes the connections once they published that many messages.
+	dropAfter int
+}
+
+func newNATSStandIn() *natsStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &natsStandIn{
+		listener: listener,
+		connects: make(chan map[string]interface{}, 10),
+		messages: make(chan Message, 10),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *natsStandIn) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *natsStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	_, _ = fmt.Fprint(conn, "INFO {\"server_id\":\"stand-in\",\"max_payload\":1048576}\r\n")
+	reader := bufio.NewReader(conn)
+	published := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			var options map[string]interface{}
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &options)
+			s.connects <- options
+			if s.connectErr != "" {
+				_, _ = fmt.Fprintf(conn, "-ERR '%s'\r\n", s.connectErr)
+				return
+			}
+		case line == "PING":
+			_, _ = fmt.Fprint(conn, "PONG\r\n")
+			if s.dropAfter > 0 && published == s.dropAfter {
+				return
+			}
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)
+			size, _ := strconv.Atoi(fields[2])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			s.messages <- Message{Subject: fields[1], Data: payload[:size]}
+			published++
+		}
+	}
+}
+
+func TestNATSTransport(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a NATS server", t, func() {
+		server := newNATSStandIn()
+		defer server.listener.Close()
+
+		Convey("Messages should be published on their subject", func() {
+			transport, err := NewNATSTransport(server.url())
+			So(err, ShouldBeNil)
+			defer transport.Close()
+			So(transport.Send(ctx, Message{Subject: "mosha.authors.created", Key: "a", Data: []byte(`{"id":"1"}`)}), ShouldBeNil)
+			So(transport.Send(ctx, Message{Subject: "mosha.authors.deleted", Key: "a", Data: []byte("{}")}), ShouldBeNil)
+
+			options := <-server.connects
+			So(options["verbose"], ShouldEqual, false)
+			So(options["name"], ShouldEqual, "mosha-author-service")
+			So(server.connects, ShouldBeEmpty)
+			first := <-server.messages
+			So(first.Subject, ShouldEqual, "mosha.authors.created")
+			So(string(first.Data), ShouldEqual, `{"id":"1"}`)
+			So((<-server.messages).Subject, ShouldEqual, "mosha.authors.deleted")
+		})
+
+		Convey("A message should be published again on a new connection when the server drops it", func() {
+			server.dropAfter = 1
+			transport, _ := NewNATSTransport(server.url())
+			defer transport.Close()
+			So(transport.Send(ctx, Message{Subject: "first", Data: []byte("1")}), ShouldBeNil)
+			So(transport.Send(ctx, Message{Subject: "second", Data: []byte("2")}), ShouldBeNil)
+			So((<-server.messages).Subject, ShouldEqual, "first")
+			So((<-server.messages).Subject, ShouldEqual, "second")
+			So(server.connects, ShouldHaveLength, 2)
+		})
+
+		Convey("The credentials of the URL should be sent", func() {
+			server.connectErr = "Authorization Violation"
+			address := server.listener.Addr().String()
+
+			transport, _ := NewNATSTransport("nats://user:secret@" + address)
+			err := transport.Send(ctx, Message{Subject: "subject", Data: []byte("{}")})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "Authorization Violation")
+			options := <-server.connects
+			So(options["user"], ShouldEqual, "user")
+			So(options["pass"], ShouldEqual, "secret")
+
+			transport, _ = NewNATSTransport("token@" + address)
+			_ = transport.Send(ctx, Message{Subject: "subject", Data: []byte("{}")})
+			So((<-server.connects)["auth_token"], ShouldEqual, "token")
+		})
+
+		Convey("Invalid subjects should be rejected", func() {
+			transport, _ := NewNATSTransport(server.url())
+			So(transport.Send(ctx, Message{Subject: "two words"}), ShouldNotBeNil)
+			So(transport.Send(ctx, Message{}), ShouldNotBeNil)
+		})
+	})
+
+	Convey("An unreachable server should fail the sends", t, func() {
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		address := listener.Addr().String()
+		_ = listener.Close()
+		transport, err := NewNATSTransport(address)
+		So(err, ShouldBeNil)
+		So(transport.Send(ctx, Message{Subject: "subject"}), ShouldNotBeNil)
+		So(transport.Close(), ShouldBeNil)
+	})
+
+	Convey("Invalid URLs should be rejected", t, func() {
+		_, err := NewNATSTransport("http://localhost:4222")
+		So(err, ShouldNotBeNil)
+		_, err = NewNATSTransport("nats://")
+		So(err, ShouldNotBeNil)
+		transport, err := NewNATSTransport("nats://localhost")
+		So(err, ShouldBeNil)
+		So(transport.address, ShouldEqual, "localhost:4222")
+	})
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultSubjectPrefix is the prefix of the subjects the events are published to.
+const DefaultSubjectPrefix = "mosha.authors"
+
+// Message is an event encoded for a message broker such as NATS or Kafka.
+type Message struct {
+	// Subject is the subject, or topic, of the message.
+	Subject string
+	// Key orders the messages of an author, such as the Kafka partition key.
+	Key string
+	// Data is the event encoded in JSON.
+	Data []byte
+}
+
+// Transport sends the messages to a message broker.
+type Transport interface {
+	// Send sends the message to the broker.
+	Send(ctx context.Context, message Message) error
+	// Close closes the connection to the broker.
+	Close() error
+}
+
+// StreamPublisher publishes the events to a message broker through its
+// transport, on the "<prefix>.<type>" subject and keyed by the author ID.
+type StreamPublisher struct {
+	transport Transport
+	prefix    string
+}
+
+// StreamOption configures a StreamPublisher.
+type StreamOption func(*StreamPublisher)
+
+// WithSubjectPrefix sets the prefix of the subjects, DefaultSubjectPrefix by default.
+func WithSubjectPrefix(prefix string) StreamOption {
+	return func(p *StreamPublisher) {
+		p.prefix = prefix
+	}
+}
+
+// NewStreamPublisher creates a new publisher sending the events through the transport.
+func NewStreamPublisher(transport Transport, opts ...StreamOption) *StreamPublisher {
+	p := &StreamPublisher{
+		transport: transport,
+		prefix:    DefaultSubjectPrefix,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Subject returns the subject of the events of the type.
+func (p *StreamPublisher) Subject(eventType string) string {
+	return fmt.Sprintf("%s.%s", p.prefix, eventType)
+}
+
+// Publish encodes the event and sends it to the message broker.
+func (p *StreamPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to encode event %q: %w", event.ID, err)
+	}
+	message := Message{
+		Subject: p.Subject(event.Type),
+		Key:     event.AuthorID,
+		Data:    payload,
+	}
+	if err := p.transport.Send(ctx, message); err != nil {
+		return fmt.Errorf("unable to send event %q to %s: %w", event.ID, message.Subject, err)
+	}
+	return nil
+}
+
+// Close closes the transport.
+func (p *StreamPublisher) Close() error {
+	return p.transport.Close()
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+// recordingTransport records the messages it sends.
+type recordingTransport struct {
+	messages []Message
+	err      error
+	closed   bool
+}
+
+func (r *recordingTransport) Send(_ context.Context, message Message) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *recordingTransport) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestStreamPublisher(t *testing.T) {
+	ctx := context.Background()
+	event := New(Updated, "author", &data.Author{ID: "author", Name: "Ada", Version: 2}, time.Now().UTC())
+
+	Convey("Given a stream publisher", t, func() {
+		transport := &recordingTransport{}
+		publisher := NewStreamPublisher(transport)
+
+		Convey("Events should be sent encoded on the subject of their type", func() {
+			So(publisher.Publish(ctx, event), ShouldBeNil)
+			So(transport.messages, ShouldHaveLength, 1)
+			So(transport.messages[0].Subject, ShouldEqual, "mosha.authors.updated")
+			So(transport.messages[0].Key, ShouldEqual, "author")
+			var decoded Event
+			So(json.Unmarshal(transport.messages[0].Data, &decoded), ShouldBeNil)
+			So(decoded.ID, ShouldEqual, event.ID)
+			So(decoded.Version, ShouldEqual, 2)
+			So(decoded.Author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("The subject prefix should be configurable", func() {
+			publisher := NewStreamPublisher(transport, WithSubjectPrefix("catalog"))
+			So(publisher.Subject(Deleted), ShouldEqual, "catalog.deleted")
+		})
+
+		Convey("Transport failures should be returned", func() {
+			transport.err = errors.New("broker down")
+			So(publisher.Publish(ctx, event), ShouldNotBeNil)
+		})
+
+		Convey("Closing the publisher should close the transport", func() {
+			So(publisher.Close(), ShouldBeNil)
+			So(transport.closed, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/getsentry/sentry-go"
+	mhttp "github.com/wcodesoft/mosha-service-common/http"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"time"
+)
+
+// HttpService returns the component serving a Mosha HTTP service. Stopping it
+// stops accepting new requests and waits for the ongoing ones to finish.
+func HttpService(mhs mhttp.MoshaHttpService) Component {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", mhs.GetPort()),
+		Handler:           mhs.MakeHandler(),
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+	return Component{
+		Name: mhs.GetName() + " http",
+		Run: func() error {
+			log.Infof("Starting %s http on %s", mhs.GetName(), mhs.GetPort())
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("unable to start service %q: %w", mhs.GetName(), err)
+			}
+			return nil
+		},
+		Stop: server.Shutdown,
+	}
+}
+
+// Worker returns the component running a background task until it is stopped.
+func Worker(name string, run func(ctx context.Context)) Component {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	return Component{
+		Name: name,
+		Run: func() error {
+			defer close(done)
+			run(ctx)
+			return nil
+		},
+		Stop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	}
+}
+
+// FlushSentry is the shutdown step sending the buffered Sentry events. It does
+// nothing when Sentry was not set up.
+func FlushSentry(ctx context.Context) error {
+	if sentry.CurrentHub().Client() == nil {
+		return nil
+	}
+	timeout := 2 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if !sentry.Flush(timeout) {
+		return errors.New("unable to flush all the events")
+	}
+	return nil
+}
+
+// DisconnectMongo returns the shutdown step closing the connections of a mongo client.
+func DisconnectMongo(client *mongo.Client) func(ctx context.Context) error {
+	return client.Disconnect
+}
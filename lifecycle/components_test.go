@@ -0,0 +1,98 @@
+package lifecycle
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	mhttp "github.com/wcodesoft/mosha-service-common/http"
+)
+
+type testHttpService struct {
+	port    string
+	handler http.Handler
+	mhttp.MoshaHttpService
+}
+
+func (s *testHttpService) GetName() string           { return "TestService" }
+func (s *testHttpService) GetPort() string           { return s.port }
+func (s *testHttpService) MakeHandler() http.Handler { return s.handler }
+
+func TestHttpService(t *testing.T) {
+	Convey("Given a running HTTP service", t, func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusNoContent)
+		})
+		component := HttpService(&testHttpService{port: "18399", handler: handler})
+		runErr := make(chan error, 1)
+		go func() { runErr <- component.Run() }()
+
+		var response *http.Response
+		var requestErr error
+		requestDone := make(chan struct{})
+		go func() {
+			defer close(requestDone)
+			for i := 0; i < 50; i++ {
+				response, requestErr = http.Get("http://localhost:18399/")
+				if requestErr == nil {
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+		<-started
+
+		Convey("Stopping it should let the ongoing request finish", func() {
+			stopErr := make(chan error, 1)
+			go func() { stopErr <- component.Stop(context.Background()) }()
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			<-requestDone
+
+			So(requestErr, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusNoContent)
+			So(<-stopErr, ShouldBeNil)
+			So(<-runErr, ShouldBeNil)
+		})
+	})
+
+	Convey("Given an HTTP service on a port in use", t, func() {
+		first := HttpService(&testHttpService{port: "18398", handler: http.NotFoundHandler()})
+		go func() { _ = first.Run() }()
+		time.Sleep(50 * time.Millisecond)
+		defer func() { _ = first.Stop(context.Background()) }()
+
+		second := HttpService(&testHttpService{port: "18398", handler: http.NotFoundHandler()})
+		So(second.Run(), ShouldNotBeNil)
+	})
+}
+
+func TestWorker(t *testing.T) {
+	Convey("Given a running worker", t, func() {
+		canceled := make(chan struct{})
+		component := Worker("worker", func(ctx context.Context) {
+			<-ctx.Done()
+			close(canceled)
+		})
+		runErr := make(chan error, 1)
+		go func() { runErr <- component.Run() }()
+
+		Convey("Stopping it should cancel its context and wait for it", func() {
+			So(component.Stop(context.Background()), ShouldBeNil)
+			<-canceled
+			So(<-runErr, ShouldBeNil)
+		})
+	})
+}
+
+func TestFlushSentry(t *testing.T) {
+	Convey("Flushing Sentry when it is not set up should succeed", t, func() {
+		So(FlushSentry(context.Background()), ShouldBeNil)
+	})
+}
@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long the components have to stop once the
+// shutdown started.
+const DefaultShutdownTimeout = 15 * time.Second
+
+// Component is a long-running part of the service, such as a server.
+type Component struct {
+	// Name identifies the component in the logs and errors.
+	Name string
+	// Run blocks until the component stops. It returns nil when the component
+	// was stopped by Stop, and an error when it failed.
+	Run func() error
+	// Stop asks the component to stop, waiting for the ongoing work to finish
+	// until the context is done.
+	Stop func(ctx context.Context) error
+}
+
+// hook is a step run once all the components stopped.
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs the components of the service until a signal is received or
+// one of them fails, then stops them and runs the shutdown hooks.
+type Manager struct {
+	components      []Component
+	hooks           []hook
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithShutdownTimeout sets how long the components and hooks have to stop.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.shutdownTimeout = timeout
+	}
+}
+
+// WithSignals sets the signals starting the shutdown, SIGINT and SIGTERM by default.
+func WithSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.signals = signals
+	}
+}
+
+// New creates a new lifecycle manager.
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		shutdownTimeout: DefaultShutdownTimeout,
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Add registers a component started by Run.
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// OnShutdown registers a step run once all the components stopped, such as
+// closing a connection. The steps run in the reverse order of registration.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Run starts the components and blocks until the context is done, a signal is
+// received or a component fails. It then stops the components in the reverse
+// order of registration and runs the shutdown hooks. It returns nil when the
+// service shut down cleanly, and the errors of the failed components and
+// steps otherwise.
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, m.signals...)
+	defer stopSignals()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(m.components))
+	for _, component := range m.components {
+		component := component
+		go func() {
+			results <- result{name: component.Name, err: component.Run()}
+		}()
+	}
+
+	var errs []error
+	running := len(m.components)
+	select {
+	case <-ctx.Done():
+		log.Info("shutting down")
+	case res := <-results:
+		running--
+		if res.err != nil {
+			log.Error("component failed, shutting down", "component", res.name, "err", res.err)
+			errs = append(errs, fmt.Errorf("%s: %w", res.name, res.err))
+		} else {
+			log.Info("component stopped, shutting down", "component", res.name)
+		}
+	}
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+	for i := len(m.components) - 1; i >= 0; i-- {
+		component := m.components[i]
+		if component.Stop == nil {
+			continue
+		}
+		if err := component.Stop(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, err))
+		}
+	}
+wait:
+	for ; running > 0; running-- {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", res.name, res.err))
+			}
+		case <-shutdownCtx.Done():
+			errs = append(errs, fmt.Errorf("%d components did not stop: %w", running, shutdownCtx.Err()))
+			break wait
+		}
+	}
+	for i := len(m.hooks) - 1; i >= 0; i-- {
+		if err := m.hooks[i].fn(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// blockingComponent runs until it is stopped and records the calls in the events.
+func blockingComponent(name string, events *[]string) Component {
+	stop := make(chan struct{})
+	return Component{
+		Name: name,
+		Run: func() error {
+			<-stop
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			*events = append(*events, "stop "+name)
+			close(stop)
+			return nil
+		},
+	}
+}
+
+func recordingHook(name string, events *[]string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		*events = append(*events, name)
+		return nil
+	}
+}
+
+func TestManager(t *testing.T) {
+	Convey("Given a manager with components and hooks", t, func() {
+		var events []string
+		manager := New(WithShutdownTimeout(time.Second), WithSignals(syscall.SIGUSR1))
+		manager.OnShutdown("mongo", recordingHook("mongo", &events))
+		manager.OnShutdown("sentry", recordingHook("sentry", &events))
+		manager.Add(blockingComponent("worker", &events))
+		manager.Add(blockingComponent("http", &events))
+
+		Convey("When the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := manager.Run(ctx)
+
+			Convey("It should stop the components and run the hooks in reverse order", func() {
+				So(err, ShouldBeNil)
+				So(events, ShouldResemble, []string{"stop http", "stop worker", "sentry", "mongo"})
+			})
+		})
+
+		Convey("When a signal is received", func() {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+			}()
+			err := manager.Run(context.Background())
+
+			Convey("It should shut down cleanly", func() {
+				So(err, ShouldBeNil)
+				So(events, ShouldResemble, []string{"stop http", "stop worker", "sentry", "mongo"})
+			})
+		})
+
+		Convey("When a component fails", func() {
+			failure := errors.New("address already in use")
+			manager.Add(Component{
+				Name: "grpc",
+				Run:  func() error { return failure },
+			})
+			err := manager.Run(context.Background())
+
+			Convey("It should stop the other components and return the failure", func() {
+				So(err, ShouldWrap, failure)
+				So(err.Error(), ShouldContainSubstring, "grpc")
+				So(events, ShouldResemble, []string{"stop http", "stop worker", "sentry", "mongo"})
+			})
+		})
+
+		Convey("When a component does not stop in time", func() {
+			manager := New(WithShutdownTimeout(50 * time.Millisecond))
+			manager.OnShutdown("mongo", recordingHook("mongo", &events))
+			manager.Add(Component{
+				Name: "stuck",
+				Run:  func() error { select {} },
+				Stop: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := manager.Run(ctx)
+
+			Convey("It should still run the hooks and return the timeout", func() {
+				So(err, ShouldWrap, context.DeadlineExceeded)
+				So(events, ShouldResemble, []string{"mongo"})
+			})
+		})
+
+		Convey("When a hook fails", func() {
+			failure := errors.New("disconnect failed")
+			manager.OnShutdown("broken", func(ctx context.Context) error { return failure })
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := manager.Run(ctx)
+
+			Convey("It should run the other hooks and return the failure", func() {
+				So(err, ShouldWrap, failure)
+				So(events, ShouldResemble, []string{"stop http", "stop worker", "sentry", "mongo"})
+			})
+		})
+	})
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"github.com/charmbracelet/log"
+	"github.com/wcodesoft/mosha-author-service/auth"
+	"github.com/wcodesoft/mosha-author-service/events"
+	"github.com/wcodesoft/mosha-author-service/lifecycle"
+	"github.com/wcodesoft/mosha-author-service/metrics"
 	"github.com/wcodesoft/mosha-author-service/repository"
 	"github.com/wcodesoft/mosha-author-service/service"
-	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"github.com/wcodesoft/mosha-author-service/telemetry"
 	mgrpc "github.com/wcodesoft/mosha-service-common/grpc"
-	mhttp "github.com/wcodesoft/mosha-service-common/http"
 	"github.com/wcodesoft/mosha-service-common/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"net/http"
 	"os"
 	"strconv"
-	"sync"
+	"time"
 )
 
 const (
@@ -30,13 +36,123 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getDurationEnv returns the duration of the environment variable, such as "1m30s".
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Fatalf("invalid duration %q for %s", value, key)
+	}
+	return duration
+}
+
+// getIntEnv returns the non-negative integer of the environment variable.
+func getIntEnv(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		log.Fatalf("invalid number %q for %s", value, key)
+	}
+	return number
+}
+
+// setupAuthenticator returns the authenticator configured by the AUTH_*
+// environment variables, or nil when none is set and authentication is disabled.
+func setupAuthenticator() *auth.Authenticator {
+	var opts []auth.Option
+	if secret := getEnv("AUTH_JWT_HMAC_SECRET", ""); secret != "" {
+		opts = append(opts, auth.WithHMACSecret([]byte(secret)))
+	}
+	if path := getEnv("AUTH_JWKS_FILE", ""); path != "" {
+		keys, err := auth.LoadJWKS(path)
+		if err != nil {
+			log.Fatal("unable to load AUTH_JWKS_FILE: ", err)
+		}
+		opts = append(opts, auth.WithJWKS(keys))
+	}
+	if spec := getEnv("AUTH_API_KEYS", ""); spec != "" {
+		keys, err := auth.ParseAPIKeys(spec)
+		if err != nil {
+			log.Fatal("invalid AUTH_API_KEYS: ", err)
+		}
+		opts = append(opts, auth.WithAPIKeys(keys...))
+	}
+	if len(opts) == 0 {
+		log.Warn("authentication is disabled, set AUTH_JWT_HMAC_SECRET, AUTH_JWKS_FILE or AUTH_API_KEYS to enable it")
+		return nil
+	}
+	return auth.New(append(opts,
+		auth.WithIssuer(getEnv("AUTH_JWT_ISSUER", "")),
+		auth.WithAudience(getEnv("AUTH_JWT_AUDIENCE", "")),
+		auth.WithRolesClaim(getEnv("AUTH_JWT_ROLES_CLAIM", auth.DefaultRolesClaim)),
+	)...)
+}
+
+// setupEventPublisher returns the publisher sending the author events to the
+// NATS server of EVENTS_NATS_URL, or nil when it is not set.
+func setupEventPublisher() *events.StreamPublisher {
+	natsURL := getEnv("EVENTS_NATS_URL", "")
+	if natsURL == "" {
+		return nil
+	}
+	transport, err := events.NewNATSTransport(natsURL)
+	if err != nil {
+		log.Fatal("invalid EVENTS_NATS_URL: ", err)
+	}
+	return events.NewStreamPublisher(transport,
+		events.WithSubjectPrefix(getEnv("EVENTS_SUBJECT_PREFIX", events.DefaultSubjectPrefix)),
+	)
+}
+
+// setupLogger configures the format and level of the default logger.
+func setupLogger(format string, level string) {
+	switch format {
+	case "json":
+		log.SetFormatter(log.JSONFormatter)
+	case "logfmt":
+		log.SetFormatter(log.LogfmtFormatter)
+	case "text":
+		log.SetFormatter(log.TextFormatter)
+	default:
+		log.Fatalf("invalid log format %q for LOG_FORMAT", format)
+	}
+	log.SetReportTimestamp(true)
+	log.SetLevel(log.ParseLevel(level))
+}
+
 func main() {
+	setupLogger(getEnv("LOG_FORMAT", "json"), getEnv("LOG_LEVEL", "info"))
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
 	log.Printf("Starting %s", AuthorServiceName)
 	httpPort := getEnv("COMPONENT_PORT", defaultHttpPort)
 	quoteServiceAddress := getEnv("QUOTE_SERVICE_ADDRESS", quoteGrpcAddress)
-	mongoHost := getEnv("MONGO_DB_HOST", defaultMongoHost)
 	grpcPort := getEnv("GRPC_PORT", defaultGrpcPort)
 	releaseVersion := getEnv("RELEASE_VERSION", defaultReleaseVersion)
+	retention := getDurationEnv("DELETED_AUTHOR_RETENTION", repository.DefaultRetention)
+	outboxInterval := getDurationEnv("OUTBOX_INTERVAL", repository.DefaultOutboxInterval)
+	if outboxInterval == 0 {
+		log.Fatal("OUTBOX_INTERVAL must not be zero")
+	}
+	maxImportBytes := getIntEnv("IMPORT_MAX_BYTES", service.DefaultMaxImportBytes)
+	shutdownTimeout := getDurationEnv("SHUTDOWN_TIMEOUT", lifecycle.DefaultShutdownTimeout)
+
+	defaultClientConfig := repository.DefaultClientConfig()
+	clientConfig := repository.ClientConfig{
+		Timeout:          getDurationEnv("QUOTE_SERVICE_TIMEOUT", defaultClientConfig.Timeout),
+		MaxAttempts:      getIntEnv("QUOTE_SERVICE_MAX_ATTEMPTS", defaultClientConfig.MaxAttempts),
+		InitialBackoff:   getDurationEnv("QUOTE_SERVICE_INITIAL_BACKOFF", defaultClientConfig.InitialBackoff),
+		MaxBackoff:       getDurationEnv("QUOTE_SERVICE_MAX_BACKOFF", defaultClientConfig.MaxBackoff),
+		BreakerThreshold: getIntEnv("QUOTE_SERVICE_BREAKER_THRESHOLD", defaultClientConfig.BreakerThreshold),
+		BreakerCooldown:  getDurationEnv("QUOTE_SERVICE_BREAKER_COOLDOWN", defaultClientConfig.BreakerCooldown),
+	}
 
 	sentryDsn := getEnv("SENTRY_DSN", "__DSN__")
 	sentrySampleRate, err := strconv.ParseFloat(getEnv("SENTRY_SAMPLE_RATE", "1.0"), 64)
@@ -48,49 +164,94 @@ func main() {
 		log.Error("unable to setup sentry: ", err)
 	}
 
+	traceSampleRatio, err := strconv.ParseFloat(getEnv("OTEL_TRACES_SAMPLER_ARG", "1.0"), 64)
+	if err != nil {
+		traceSampleRatio = 1.0
+	}
+	shutdownTracing, err := telemetry.Setup(context.Background(), telemetry.Config{
+		ServiceName: AuthorServiceName,
+		Version:     releaseVersion,
+		Exporter:    getEnv("OTEL_TRACES_EXPORTER", telemetry.ExporterNone),
+		FilePath:    getEnv("OTEL_TRACES_FILE", "traces.json"),
+		SampleRatio: traceSampleRatio,
+	})
+	if err != nil {
+		log.Fatal("unable to setup tracing: ", err)
+	}
+
 	quoteGrpcClientInfo := mgrpc.ClientInfo{
 		Name:    "QuoteService",
 		Address: quoteServiceAddress,
 	}
-	clientsRepository, err := repository.NewClientRepository(quoteGrpcClientInfo)
+	serviceMetrics := metrics.New()
+	clientsRepository, err := repository.NewClientRepository(quoteGrpcClientInfo, clientConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
+	clientsRepository = serviceMetrics.InstrumentClient(clientsRepository)
 
-	mongoClient, err := mdb.NewMongoClient(mongoHost)
+	storage, err := openStorage(getEnv("STORAGE_BACKEND", storageMongo))
 	if err != nil {
 		log.Fatal(err)
 	}
-	connection := mdb.NewMongoConnection(mongoClient, defaultDatabase, "authors")
-	database := repository.NewMongoDatabase(connection)
-	repo := repository.New(database, clientsRepository)
-	s := service.New(repo)
-
-	wg := new(sync.WaitGroup)
-
-	wg.Add(2)
-
-	go func() {
-		// Create a new AuthorService.
-		hs := service.AuthorService{
-			Service: s,
-			Port:    httpPort,
-			Name:    AuthorServiceName,
-		}
-		err := mhttp.StartHttpService(&hs)
-		if err != nil {
-			log.Fatal(err)
-		}
-		wg.Done()
-	}()
+	database := serviceMetrics.InstrumentDatabase(telemetry.TraceDatabase(storage.Database, storage.System, "authors"))
+	repoOptions := []repository.Option{repository.WithRetention(retention)}
+	if storage.Outbox != nil {
+		repoOptions = append(repoOptions, repository.WithOutbox(storage.Outbox))
+	}
+	if storage.Audit != nil {
+		repoOptions = append(repoOptions, repository.WithAudit(storage.Audit))
+	}
+	repo := repository.New(database, clientsRepository, repoOptions...)
+	var serviceOptions []service.Option
+	publisher := setupEventPublisher()
+	if publisher != nil {
+		serviceOptions = append(serviceOptions, service.WithPublisher(publisher))
+	}
+	s := service.New(repo, serviceOptions...)
+	authenticator := setupAuthenticator()
 
-	go func() {
-		grpcRouter := service.NewGrpcRouter(s, AuthorServiceName)
-		if err := grpcRouter.Start(grpcPort); err != nil {
-			log.Fatal(err)
-		}
-		wg.Done()
-	}()
+	manager := lifecycle.New(lifecycle.WithShutdownTimeout(shutdownTimeout))
+	manager.OnShutdown("storage", storage.Close)
+	if publisher != nil {
+		manager.OnShutdown("events", func(context.Context) error {
+			return publisher.Close()
+		})
+	}
+	manager.OnShutdown("sentry", lifecycle.FlushSentry)
+	manager.OnShutdown("tracing", shutdownTracing)
+	manager.Add(lifecycle.Worker("outbox worker", repository.NewOutboxWorker(repo, outboxInterval).Run))
+	manager.Add(lifecycle.HttpService(&service.AuthorService{
+		Service: s,
+		Port:    httpPort,
+		Name:    AuthorServiceName,
+		Metrics: serviceMetrics,
+		Middlewares: []func(http.Handler) http.Handler{
+			telemetry.HttpMiddleware,
+		},
+		Auth:           authenticator,
+		MaxImportBytes: int64(maxImportBytes),
+	}))
+	grpcOptions := []service.GrpcOption{
+		service.WithInterceptors(otelgrpc.UnaryServerInterceptor(), otelgrpc.StreamServerInterceptor()),
+		service.WithInterceptors(serviceMetrics.UnaryServerInterceptor(), serviceMetrics.StreamServerInterceptor()),
+	}
+	if authenticator != nil {
+		grpcOptions = append(grpcOptions, service.WithAuthenticator(authenticator))
+	}
+	grpcRouter := service.NewGrpcRouter(s, AuthorServiceName, grpcOptions...)
+	manager.Add(lifecycle.Component{
+		Name: AuthorServiceName + " grpc",
+		Run: func() error {
+			log.Infof("Starting %s grpc on %s", AuthorServiceName, grpcPort)
+			return grpcRouter.Start(grpcPort)
+		},
+		Stop: grpcRouter.Stop,
+	})
 
-	wg.Wait()
+	if err := manager.Run(context.Background()); err != nil {
+		log.Error("shutdown with errors", "err", err)
+		os.Exit(1)
+	}
+	log.Infof("%s stopped", AuthorServiceName)
 }
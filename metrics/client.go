@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"time"
+)
+
+// instrumentedClient observes the calls to QuoteService.
+type instrumentedClient struct {
+	client  repository.ClientRepository
+	metrics *Metrics
+}
+
+// InstrumentClient returns a client repository counting the calls to
+// QuoteService by result and observing their latency. Pings are not counted,
+// as they do not call QuoteService.
+func (m *Metrics) InstrumentClient(client repository.ClientRepository) repository.ClientRepository {
+	return &instrumentedClient{client: client, metrics: m}
+}
+
+// DeleteAuthorQuotes deletes all quotes from an author. A call answered
+// without success is counted with the "unsuccessful" result.
+func (c *instrumentedClient) DeleteAuthorQuotes(ctx context.Context, authorID string) (bool, error) {
+	start := time.Now()
+	success, err := c.client.DeleteAuthorQuotes(ctx, authorID)
+	callResult := result(err)
+	if err == nil && !success {
+		callResult = "unsuccessful"
+	}
+	c.metrics.quoteCalls.WithLabelValues("DeleteAuthorQuotes", callResult).Inc()
+	c.metrics.quoteDuration.WithLabelValues("DeleteAuthorQuotes").Observe(time.Since(start).Seconds())
+	return success, err
+}
+
+func (c *instrumentedClient) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx)
+}
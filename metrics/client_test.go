@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/repository"
+)
+
+func TestInstrumentClient(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an instrumented client repository", t, func() {
+		m := New()
+		fake := repository.NewFakeClientRepository()
+		client := m.InstrumentClient(fake)
+
+		Convey("Calls should be counted by result", func() {
+			success, err := client.DeleteAuthorQuotes(ctx, "1")
+			So(err, ShouldBeNil)
+			So(success, ShouldBeTrue)
+			fake.SetDeleteAuthorQuotesReturn(false, nil)
+			_, _ = client.DeleteAuthorQuotes(ctx, "2")
+			fake.SetDeleteAuthorQuotesReturn(false, fmt.Errorf("QuoteService: %w", repository.ErrCircuitOpen))
+			_, err = client.DeleteAuthorQuotes(ctx, "3")
+			So(err, ShouldWrap, repository.ErrCircuitOpen)
+
+			So(testutil.ToFloat64(m.quoteCalls.WithLabelValues("DeleteAuthorQuotes", "ok")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.quoteCalls.WithLabelValues("DeleteAuthorQuotes", "unsuccessful")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.quoteCalls.WithLabelValues("DeleteAuthorQuotes", "circuit_open")), ShouldEqual, 1)
+		})
+
+		Convey("Pings should not be counted", func() {
+			fake.SetPingError(repository.ErrCircuitOpen)
+			So(client.Ping(ctx), ShouldEqual, repository.ErrCircuitOpen)
+			So(testutil.CollectAndCount(m.quoteCalls), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"context"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"time"
+)
+
+// databaseObserver counts the database operations and observes their latency.
+type databaseObserver struct {
+	metrics *Metrics
+}
+
+// Observe starts the timing of an operation.
+func (o databaseObserver) Observe(ctx context.Context, operation string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		o.metrics.dbOperations.WithLabelValues(operation, result(err)).Inc()
+		o.metrics.dbDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
+
+// InstrumentDatabase returns a database counting the operations of db by
+// result and observing their latency.
+func (m *Metrics) InstrumentDatabase(db repository.Database) repository.Database {
+	return repository.ObserveDatabase(db, databaseObserver{metrics: m})
+}
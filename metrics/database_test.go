@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	faker "github.com/brianvoe/gofakeit/v6"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/repository"
+)
+
+func TestInstrumentDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an instrumented database", t, func() {
+		m := New()
+		db := m.InstrumentDatabase(repository.NewInMemoryDatabase())
+		author := data.NewAuthorBuilder().WithId(faker.UUID()).WithName(faker.Name()).Build()
+
+		Convey("Operations should be counted by result", func() {
+			_, err := db.AddAuthor(ctx, author)
+			So(err, ShouldBeNil)
+			_, err = db.AddAuthor(ctx, author)
+			So(err, ShouldWrap, repository.ErrAlreadyExists)
+			_, err = db.GetAuthor(ctx, author.ID)
+			So(err, ShouldBeNil)
+			_, err = db.GetAuthor(ctx, "missing")
+			So(err, ShouldWrap, repository.ErrNotFound)
+
+			So(testutil.ToFloat64(m.dbOperations.WithLabelValues("AddAuthor", "ok")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.dbOperations.WithLabelValues("AddAuthor", "already_exists")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.dbOperations.WithLabelValues("GetAuthor", "ok")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.dbOperations.WithLabelValues("GetAuthor", "not_found")), ShouldEqual, 1)
+			So(testutil.CollectAndCount(m.dbDuration), ShouldEqual, 2)
+		})
+
+		Convey("The results of the database should be returned unchanged", func() {
+			_, _ = db.AddAuthor(ctx, author)
+			So(len(db.ListAll(ctx)), ShouldEqual, 1)
+			page, err := db.ListPage(ctx, repository.PageRequest{})
+			So(err, ShouldBeNil)
+			So(len(page.Authors), ShouldEqual, 1)
+			author.Name = faker.Name()
+			updated, err := db.UpdateAuthor(ctx, author, 0)
+			So(err, ShouldBeNil)
+			So(updated.Name, ShouldEqual, author.Name)
+			patched, err := db.PatchAuthor(ctx, author.ID, data.AuthorPatch{Author: data.Author{Nationality: "French"}, Fields: []string{data.FieldNationality}}, 0)
+			So(err, ShouldBeNil)
+			So(patched.Nationality, ShouldEqual, "French")
+			_, err = db.SearchAuthors(ctx, author.Name, 10)
+			So(err, ShouldBeNil)
+			_, err = db.SoftDeleteAuthor(ctx, author.ID, "admin", time.Now(), 0)
+			So(err, ShouldBeNil)
+			_, err = db.GetDeletedAuthor(ctx, author.ID)
+			So(err, ShouldBeNil)
+			deleted, err := db.ListDeleted(ctx, time.Time{})
+			So(err, ShouldBeNil)
+			So(len(deleted), ShouldEqual, 1)
+			_, err = db.RestoreAuthor(ctx, author.ID, 0)
+			So(err, ShouldBeNil)
+			revisions, err := db.ListRevisions(ctx, author.ID)
+			So(err, ShouldBeNil)
+			So(len(revisions), ShouldEqual, 5)
+			_, err = db.GetRevision(ctx, author.ID, 2)
+			So(err, ShouldBeNil)
+			_, err = db.GetRevisionAt(ctx, author.ID, time.Now())
+			So(err, ShouldBeNil)
+			So(db.DeleteAuthor(ctx, author.ID, 0), ShouldBeNil)
+			results, err := db.BulkWrite(ctx, []repository.BulkOperation{{Kind: repository.BulkCreate, Author: author}}, repository.BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldBeNil)
+			So(db.Ping(ctx), ShouldBeNil)
+			So(testutil.CollectAndCount(m.dbDuration), ShouldEqual, 16)
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcInterceptors(t *testing.T) {
+	ctx := context.Background()
+	method := "/authorservice.AuthorService/GetAuthor"
+
+	Convey("Given the gRPC interceptors", t, func() {
+		m := New()
+
+		Convey("Unary calls should be labeled by method and code", func() {
+			interceptor := m.UnaryServerInterceptor()
+			info := &grpc.UnaryServerInfo{FullMethod: method}
+			_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return "author", nil
+			})
+			_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			})
+
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+			So(testutil.ToFloat64(m.grpcRequests.WithLabelValues(method, "OK")), ShouldEqual, 1)
+			So(testutil.ToFloat64(m.grpcRequests.WithLabelValues(method, "NotFound")), ShouldEqual, 1)
+		})
+
+		Convey("Streaming calls should be labeled by method and code", func() {
+			watch := "/grpc.health.v1.Health/Watch"
+			interceptor := m.StreamServerInterceptor()
+			err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: watch}, func(srv any, stream grpc.ServerStream) error {
+				return status.Error(codes.Canceled, "stream has ended")
+			})
+
+			So(status.Code(err), ShouldEqual, codes.Canceled)
+			So(testutil.ToFloat64(m.grpcRequests.WithLabelValues(watch, "Canceled")), ShouldEqual, 1)
+		})
+	})
+}
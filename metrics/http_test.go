@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHttpMiddleware(t *testing.T) {
+	Convey("Given a router instrumented by the middleware", t, func() {
+		m := New()
+		r := chi.NewRouter()
+		r.Use(m.HttpMiddleware)
+		r.Get("/api/v1/author/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "id") == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("{}"))
+		})
+
+		Convey("Requests should be labeled by route pattern and status", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/author/1", nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/author/2", nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/author/missing", nil))
+
+			So(testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/v1/author/{id}", "200")), ShouldEqual, 2)
+			So(testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/v1/author/{id}", "404")), ShouldEqual, 1)
+			So(testutil.CollectAndCount(m.httpDuration), ShouldEqual, 1)
+		})
+
+		Convey("Requests matching no route should share a label", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown/1", nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown/2", nil))
+
+			So(testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", unmatchedRoute, "404")), ShouldEqual, 2)
+		})
+
+		Convey("Unknown methods should share a label", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/api/v1/author/1", nil))
+
+			So(testutil.ToFloat64(m.httpRequests.WithLabelValues("OTHER", unmatchedRoute, "405")), ShouldEqual, 1)
+		})
+	})
+}
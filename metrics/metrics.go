@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"net/http"
+)
+
+// namespace prefixes the names of the metrics of the service.
+const namespace = "mosha_author"
+
+// Metrics holds the Prometheus metrics of the service. Their labels only take
+// a bounded set of values: routes are the patterns of the router, methods the
+// registered gRPC methods and errors are reduced to their kind.
+type Metrics struct {
+	registry      *prometheus.Registry
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	grpcRequests  *prometheus.CounterVec
+	grpcDuration  *prometheus.HistogramVec
+	dbOperations  *prometheus.CounterVec
+	dbDuration    *prometheus.HistogramVec
+	quoteCalls    *prometheus.CounterVec
+	quoteDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics of the service, along with the Go runtime and process
+// metrics, in a registry of their own.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Latency of the gRPC calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		dbOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "database_operations_total",
+			Help:      "Number of database operations by operation and result.",
+		}, []string{"operation", "result"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "database_operation_duration_seconds",
+			Help:      "Latency of the database operations by operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		quoteCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quote_client_calls_total",
+			Help:      "Number of calls to QuoteService by operation and result.",
+		}, []string{"operation", "result"}),
+		quoteDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "quote_client_call_duration_seconds",
+			Help:      "Latency of the calls to QuoteService by operation, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.dbOperations,
+		m.dbDuration,
+		m.quoteCalls,
+		m.quoteDuration,
+	)
+	return m
+}
+
+// Handler returns the handler exposing the metrics to Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry of the metrics, to register more collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// result reduces an error to its kind, "ok" when there is none.
+func result(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, repository.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return "already_exists"
+	case errors.Is(err, repository.ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, repository.ErrConflict):
+		return "conflict"
+	case errors.Is(err, repository.ErrFailedPrecondition):
+		return "failed_precondition"
+	case errors.Is(err, repository.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, repository.ErrDependencyFailed):
+		return "dependency_failed"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	default:
+		return "error"
+	}
+}
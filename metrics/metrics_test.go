@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/repository"
+)
+
+func TestResult(t *testing.T) {
+	Convey("When reducing errors to their kind", t, func() {
+		So(result(nil), ShouldEqual, "ok")
+		So(result(fmt.Errorf("wrapped: %w", repository.ErrNotFound)), ShouldEqual, "not_found")
+		So(result(repository.ErrAlreadyExists), ShouldEqual, "already_exists")
+		So(result(repository.ErrInvalidArgument), ShouldEqual, "invalid_argument")
+		So(result(repository.ErrConflict), ShouldEqual, "conflict")
+		So(result(repository.ErrFailedPrecondition), ShouldEqual, "failed_precondition")
+		So(result(fmt.Errorf("QuoteService: %w", repository.ErrCircuitOpen)), ShouldEqual, "circuit_open")
+		So(result(repository.ErrDependencyFailed), ShouldEqual, "dependency_failed")
+		So(result(context.Canceled), ShouldEqual, "canceled")
+		So(result(context.DeadlineExceeded), ShouldEqual, "deadline_exceeded")
+		So(result(fmt.Errorf("unexpected")), ShouldEqual, "error")
+	})
+}
+
+func TestHandler(t *testing.T) {
+	Convey("When scraping the metrics", t, func() {
+		m := New()
+		m.dbOperations.WithLabelValues("GetAuthor", "ok").Inc()
+		rr := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+		Convey("The service and runtime metrics should be exposed", func() {
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			body := rr.Body.String()
+			So(body, ShouldContainSubstring, `mosha_author_database_operations_total{operation="GetAuthor",result="ok"} 1`)
+			So(body, ShouldContainSubstring, "go_goroutines")
+			So(strings.Contains(body, "mosha_author_http_requests_total"), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,857 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: authorcatalog/author_catalog.proto
+
+package authorcatalog
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AuthorCatalogService_GetAuthor_FullMethodName           = "/authorcatalog.AuthorCatalogService/GetAuthor"
+	AuthorCatalogService_CreateAuthor_FullMethodName        = "/authorcatalog.AuthorCatalogService/CreateAuthor"
+	AuthorCatalogService_UpdateAuthor_FullMethodName        = "/authorcatalog.AuthorCatalogService/UpdateAuthor"
+	AuthorCatalogService_PatchAuthor_FullMethodName         = "/authorcatalog.AuthorCatalogService/PatchAuthor"
+	AuthorCatalogService_DeleteAuthor_FullMethodName        = "/authorcatalog.AuthorCatalogService/DeleteAuthor"
+	AuthorCatalogService_RestoreAuthor_FullMethodName       = "/authorcatalog.AuthorCatalogService/RestoreAuthor"
+	AuthorCatalogService_ListDeletedAuthors_FullMethodName  = "/authorcatalog.AuthorCatalogService/ListDeletedAuthors"
+	AuthorCatalogService_PurgeAuthor_FullMethodName         = "/authorcatalog.AuthorCatalogService/PurgeAuthor"
+	AuthorCatalogService_PurgeExpiredAuthors_FullMethodName = "/authorcatalog.AuthorCatalogService/PurgeExpiredAuthors"
+	AuthorCatalogService_ListAuthorsPage_FullMethodName     = "/authorcatalog.AuthorCatalogService/ListAuthorsPage"
+	AuthorCatalogService_SearchAuthors_FullMethodName       = "/authorcatalog.AuthorCatalogService/SearchAuthors"
+	AuthorCatalogService_GetAuthorHistory_FullMethodName    = "/authorcatalog.AuthorCatalogService/GetAuthorHistory"
+	AuthorCatalogService_ListAuthorRevisions_FullMethodName = "/authorcatalog.AuthorCatalogService/ListAuthorRevisions"
+	AuthorCatalogService_GetAuthorRevision_FullMethodName   = "/authorcatalog.AuthorCatalogService/GetAuthorRevision"
+	AuthorCatalogService_RevertAuthor_FullMethodName        = "/authorcatalog.AuthorCatalogService/RevertAuthor"
+	AuthorCatalogService_WatchAuthors_FullMethodName        = "/authorcatalog.AuthorCatalogService/WatchAuthors"
+	AuthorCatalogService_BulkWriteAuthors_FullMethodName    = "/authorcatalog.AuthorCatalogService/BulkWriteAuthors"
+	AuthorCatalogService_StreamWriteAuthors_FullMethodName  = "/authorcatalog.AuthorCatalogService/StreamWriteAuthors"
+)
+
+// AuthorCatalogServiceClient is the client API for AuthorCatalogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuthorCatalogServiceClient interface {
+	// GetAuthor returns an author with its full profile by id.
+	GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// CreateAuthor creates a new author with its full profile.
+	CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// UpdateAuthor replaces an existing author with its full profile.
+	UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// PatchAuthor changes only the fields of an author listed in the update mask.
+	PatchAuthor(ctx context.Context, in *PatchAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// DeleteAuthor deletes an author by id. The author can be restored until it is purged.
+	DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteAuthorResponse, error)
+	// RestoreAuthor restores a deleted author.
+	RestoreAuthor(ctx context.Context, in *RestoreAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// ListDeletedAuthors returns the deleted authors that can be restored.
+	ListDeletedAuthors(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListDeletedAuthorsResponse, error)
+	// PurgeAuthor permanently deletes a deleted author once its retention period
+	// is over. Its quotes are deleted afterwards, retrying until QuoteService succeeds.
+	PurgeAuthor(ctx context.Context, in *PurgeAuthorRequest, opts ...grpc.CallOption) (*PurgeAuthorResponse, error)
+	// PurgeExpiredAuthors permanently deletes the deleted authors whose retention
+	// period is over. Their quotes are deleted afterwards.
+	PurgeExpiredAuthors(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PurgeExpiredAuthorsResponse, error)
+	// ListAuthorsPage returns a page of authors sorted by name.
+	ListAuthorsPage(ctx context.Context, in *ListAuthorsPageRequest, opts ...grpc.CallOption) (*ListAuthorsPageResponse, error)
+	// SearchAuthors returns the authors whose name matches a query.
+	SearchAuthors(ctx context.Context, in *SearchAuthorsRequest, opts ...grpc.CallOption) (*SearchAuthorsResponse, error)
+	// GetAuthorHistory returns the changes of an author, the oldest first. The
+	// history of a purged author is kept.
+	GetAuthorHistory(ctx context.Context, in *GetAuthorHistoryRequest, opts ...grpc.CallOption) (*GetAuthorHistoryResponse, error)
+	// ListAuthorRevisions returns the revisions of an author, the oldest first.
+	ListAuthorRevisions(ctx context.Context, in *ListAuthorRevisionsRequest, opts ...grpc.CallOption) (*ListAuthorRevisionsResponse, error)
+	// GetAuthorRevision returns the revision of an author at a version, or as it
+	// was at a time.
+	GetAuthorRevision(ctx context.Context, in *GetAuthorRevisionRequest, opts ...grpc.CallOption) (*Revision, error)
+	// RevertAuthor replaces an author with its revision at a version, creating a
+	// new revision.
+	RevertAuthor(ctx context.Context, in *RevertAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	// WatchAuthors streams the events of the authors changed after the call
+	// starts, until it is canceled. The stream fails with ABORTED when the caller
+	// does not keep up with the events, after which it should reload the authors.
+	WatchAuthors(ctx context.Context, in *WatchAuthorsRequest, opts ...grpc.CallOption) (AuthorCatalogService_WatchAuthorsClient, error)
+	// BulkWriteAuthors applies a batch of at most 500 creations, updates and
+	// deletions, and returns the result of each of them.
+	BulkWriteAuthors(ctx context.Context, in *BulkWriteAuthorsRequest, opts ...grpc.CallOption) (*BulkWriteAuthorsResponse, error)
+	// StreamWriteAuthors applies the operations of all the streamed requests as a
+	// single batch once the stream is closed. The mode of the first request is
+	// used for the batch.
+	StreamWriteAuthors(ctx context.Context, opts ...grpc.CallOption) (AuthorCatalogService_StreamWriteAuthorsClient, error)
+}
+
+type authorCatalogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthorCatalogServiceClient(cc grpc.ClientConnInterface) AuthorCatalogServiceClient {
+	return &authorCatalogServiceClient{cc}
+}
+
+func (c *authorCatalogServiceClient) GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_GetAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_CreateAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_UpdateAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) PatchAuthor(ctx context.Context, in *PatchAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_PatchAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteAuthorResponse, error) {
+	out := new(DeleteAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_DeleteAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) RestoreAuthor(ctx context.Context, in *RestoreAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_RestoreAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) ListDeletedAuthors(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListDeletedAuthorsResponse, error) {
+	out := new(ListDeletedAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_ListDeletedAuthors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) PurgeAuthor(ctx context.Context, in *PurgeAuthorRequest, opts ...grpc.CallOption) (*PurgeAuthorResponse, error) {
+	out := new(PurgeAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_PurgeAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) PurgeExpiredAuthors(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PurgeExpiredAuthorsResponse, error) {
+	out := new(PurgeExpiredAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_PurgeExpiredAuthors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) ListAuthorsPage(ctx context.Context, in *ListAuthorsPageRequest, opts ...grpc.CallOption) (*ListAuthorsPageResponse, error) {
+	out := new(ListAuthorsPageResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_ListAuthorsPage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) SearchAuthors(ctx context.Context, in *SearchAuthorsRequest, opts ...grpc.CallOption) (*SearchAuthorsResponse, error) {
+	out := new(SearchAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_SearchAuthors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) GetAuthorHistory(ctx context.Context, in *GetAuthorHistoryRequest, opts ...grpc.CallOption) (*GetAuthorHistoryResponse, error) {
+	out := new(GetAuthorHistoryResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_GetAuthorHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) ListAuthorRevisions(ctx context.Context, in *ListAuthorRevisionsRequest, opts ...grpc.CallOption) (*ListAuthorRevisionsResponse, error) {
+	out := new(ListAuthorRevisionsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_ListAuthorRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) GetAuthorRevision(ctx context.Context, in *GetAuthorRevisionRequest, opts ...grpc.CallOption) (*Revision, error) {
+	out := new(Revision)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_GetAuthorRevision_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) RevertAuthor(ctx context.Context, in *RevertAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_RevertAuthor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) WatchAuthors(ctx context.Context, in *WatchAuthorsRequest, opts ...grpc.CallOption) (AuthorCatalogService_WatchAuthorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AuthorCatalogService_ServiceDesc.Streams[0], AuthorCatalogService_WatchAuthors_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authorCatalogServiceWatchAuthorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AuthorCatalogService_WatchAuthorsClient interface {
+	Recv() (*AuthorEvent, error)
+	grpc.ClientStream
+}
+
+type authorCatalogServiceWatchAuthorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authorCatalogServiceWatchAuthorsClient) Recv() (*AuthorEvent, error) {
+	m := new(AuthorEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authorCatalogServiceClient) BulkWriteAuthors(ctx context.Context, in *BulkWriteAuthorsRequest, opts ...grpc.CallOption) (*BulkWriteAuthorsResponse, error) {
+	out := new(BulkWriteAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorCatalogService_BulkWriteAuthors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorCatalogServiceClient) StreamWriteAuthors(ctx context.Context, opts ...grpc.CallOption) (AuthorCatalogService_StreamWriteAuthorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AuthorCatalogService_ServiceDesc.Streams[1], AuthorCatalogService_StreamWriteAuthors_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authorCatalogServiceStreamWriteAuthorsClient{stream}
+	return x, nil
+}
+
+type AuthorCatalogService_StreamWriteAuthorsClient interface {
+	Send(*BulkWriteAuthorsRequest) error
+	CloseAndRecv() (*BulkWriteAuthorsResponse, error)
+	grpc.ClientStream
+}
+
+type authorCatalogServiceStreamWriteAuthorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authorCatalogServiceStreamWriteAuthorsClient) Send(m *BulkWriteAuthorsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authorCatalogServiceStreamWriteAuthorsClient) CloseAndRecv() (*BulkWriteAuthorsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BulkWriteAuthorsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AuthorCatalogServiceServer is the server API for AuthorCatalogService service.
+// All implementations must embed UnimplementedAuthorCatalogServiceServer
+// for forward compatibility
+type AuthorCatalogServiceServer interface {
+	// GetAuthor returns an author with its full profile by id.
+	GetAuthor(context.Context, *GetAuthorRequest) (*Author, error)
+	// CreateAuthor creates a new author with its full profile.
+	CreateAuthor(context.Context, *CreateAuthorRequest) (*Author, error)
+	// UpdateAuthor replaces an existing author with its full profile.
+	UpdateAuthor(context.Context, *UpdateAuthorRequest) (*Author, error)
+	// PatchAuthor changes only the fields of an author listed in the update mask.
+	PatchAuthor(context.Context, *PatchAuthorRequest) (*Author, error)
+	// DeleteAuthor deletes an author by id. The author can be restored until it is purged.
+	DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteAuthorResponse, error)
+	// RestoreAuthor restores a deleted author.
+	RestoreAuthor(context.Context, *RestoreAuthorRequest) (*Author, error)
+	// ListDeletedAuthors returns the deleted authors that can be restored.
+	ListDeletedAuthors(context.Context, *emptypb.Empty) (*ListDeletedAuthorsResponse, error)
+	// PurgeAuthor permanently deletes a deleted author once its retention period
+	// is over. Its quotes are deleted afterwards, retrying until QuoteService succeeds.
+	PurgeAuthor(context.Context, *PurgeAuthorRequest) (*PurgeAuthorResponse, error)
+	// PurgeExpiredAuthors permanently deletes the deleted authors whose retention
+	// period is over. Their quotes are deleted afterwards.
+	PurgeExpiredAuthors(context.Context, *emptypb.Empty) (*PurgeExpiredAuthorsResponse, error)
+	// ListAuthorsPage returns a page of authors sorted by name.
+	ListAuthorsPage(context.Context, *ListAuthorsPageRequest) (*ListAuthorsPageResponse, error)
+	// SearchAuthors returns the authors whose name matches a query.
+	SearchAuthors(context.Context, *SearchAuthorsRequest) (*SearchAuthorsResponse, error)
+	// GetAuthorHistory returns the changes of an author, the oldest first. The
+	// history of a purged author is kept.
+	GetAuthorHistory(context.Context, *GetAuthorHistoryRequest) (*GetAuthorHistoryResponse, error)
+	// ListAuthorRevisions returns the revisions of an author, the oldest first.
+	ListAuthorRevisions(context.Context, *ListAuthorRevisionsRequest) (*ListAuthorRevisionsResponse, error)
+	// GetAuthorRevision returns the revision of an author at a version, or as it
+	// was at a time.
+	GetAuthorRevision(context.Context, *GetAuthorRevisionRequest) (*Revision, error)
+	// RevertAuthor replaces an author with its revision at a version, creating a
+	// new revision.
+	RevertAuthor(context.Context, *RevertAuthorRequest) (*Author, error)
+	// WatchAuthors streams the events of the authors changed after the call
+	// starts, until it is canceled. The stream fails with ABORTED when the caller
+	// does not keep up with the events, after which it should reload the authors.
+	WatchAuthors(*WatchAuthorsRequest, AuthorCatalogService_WatchAuthorsServer) error
+	// BulkWriteAuthors applies a batch of at most 500 creations, updates and
+	// deletions, and returns the result of each of them.
+	BulkWriteAuthors(context.Context, *BulkWriteAuthorsRequest) (*BulkWriteAuthorsResponse, error)
+	// StreamWriteAuthors applies the operations of all the streamed requests as a
+	// single batch once the stream is closed. The mode of the first request is
+	// used for the batch.
+	StreamWriteAuthors(AuthorCatalogService_StreamWriteAuthorsServer) error
+	mustEmbedUnimplementedAuthorCatalogServiceServer()
+}
+
+// UnimplementedAuthorCatalogServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAuthorCatalogServiceServer struct {
+}
+
+func (UnimplementedAuthorCatalogServiceServer) GetAuthor(context.Context, *GetAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) CreateAuthor(context.Context, *CreateAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) UpdateAuthor(context.Context, *UpdateAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) PatchAuthor(context.Context, *PatchAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) RestoreAuthor(context.Context, *RestoreAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) ListDeletedAuthors(context.Context, *emptypb.Empty) (*ListDeletedAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) PurgeAuthor(context.Context, *PurgeAuthorRequest) (*PurgeAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) PurgeExpiredAuthors(context.Context, *emptypb.Empty) (*PurgeExpiredAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeExpiredAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) ListAuthorsPage(context.Context, *ListAuthorsPageRequest) (*ListAuthorsPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthorsPage not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) SearchAuthors(context.Context, *SearchAuthorsRequest) (*SearchAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) GetAuthorHistory(context.Context, *GetAuthorHistoryRequest) (*GetAuthorHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorHistory not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) ListAuthorRevisions(context.Context, *ListAuthorRevisionsRequest) (*ListAuthorRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthorRevisions not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) GetAuthorRevision(context.Context, *GetAuthorRevisionRequest) (*Revision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorRevision not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) RevertAuthor(context.Context, *RevertAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertAuthor not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) WatchAuthors(*WatchAuthorsRequest, AuthorCatalogService_WatchAuthorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) BulkWriteAuthors(context.Context, *BulkWriteAuthorsRequest) (*BulkWriteAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkWriteAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) StreamWriteAuthors(AuthorCatalogService_StreamWriteAuthorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamWriteAuthors not implemented")
+}
+func (UnimplementedAuthorCatalogServiceServer) mustEmbedUnimplementedAuthorCatalogServiceServer() {}
+
+// UnsafeAuthorCatalogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthorCatalogServiceServer will
+// result in compilation errors.
+type UnsafeAuthorCatalogServiceServer interface {
+	mustEmbedUnimplementedAuthorCatalogServiceServer()
+}
+
+func RegisterAuthorCatalogServiceServer(s grpc.ServiceRegistrar, srv AuthorCatalogServiceServer) {
+	s.RegisterService(&AuthorCatalogService_ServiceDesc, srv)
+}
+
+func _AuthorCatalogService_GetAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).GetAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_GetAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).GetAuthor(ctx, req.(*GetAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_CreateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).CreateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_CreateAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).CreateAuthor(ctx, req.(*CreateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_UpdateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).UpdateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_UpdateAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).UpdateAuthor(ctx, req.(*UpdateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_PatchAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).PatchAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_PatchAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).PatchAuthor(ctx, req.(*PatchAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_DeleteAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).DeleteAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_DeleteAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).DeleteAuthor(ctx, req.(*DeleteAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_RestoreAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).RestoreAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_RestoreAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).RestoreAuthor(ctx, req.(*RestoreAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_ListDeletedAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).ListDeletedAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_ListDeletedAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).ListDeletedAuthors(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_PurgeAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).PurgeAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_PurgeAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).PurgeAuthor(ctx, req.(*PurgeAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_PurgeExpiredAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).PurgeExpiredAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_PurgeExpiredAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).PurgeExpiredAuthors(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_ListAuthorsPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorsPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).ListAuthorsPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_ListAuthorsPage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).ListAuthorsPage(ctx, req.(*ListAuthorsPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_SearchAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).SearchAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_SearchAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).SearchAuthors(ctx, req.(*SearchAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_GetAuthorHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).GetAuthorHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_GetAuthorHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).GetAuthorHistory(ctx, req.(*GetAuthorHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_ListAuthorRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).ListAuthorRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_ListAuthorRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).ListAuthorRevisions(ctx, req.(*ListAuthorRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_GetAuthorRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).GetAuthorRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_GetAuthorRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).GetAuthorRevision(ctx, req.(*GetAuthorRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_RevertAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).RevertAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_RevertAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).RevertAuthor(ctx, req.(*RevertAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_WatchAuthors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAuthorsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthorCatalogServiceServer).WatchAuthors(m, &authorCatalogServiceWatchAuthorsServer{stream})
+}
+
+type AuthorCatalogService_WatchAuthorsServer interface {
+	Send(*AuthorEvent) error
+	grpc.ServerStream
+}
+
+type authorCatalogServiceWatchAuthorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *authorCatalogServiceWatchAuthorsServer) Send(m *AuthorEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AuthorCatalogService_BulkWriteAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkWriteAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorCatalogServiceServer).BulkWriteAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorCatalogService_BulkWriteAuthors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorCatalogServiceServer).BulkWriteAuthors(ctx, req.(*BulkWriteAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorCatalogService_StreamWriteAuthors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthorCatalogServiceServer).StreamWriteAuthors(&authorCatalogServiceStreamWriteAuthorsServer{stream})
+}
+
+type AuthorCatalogService_StreamWriteAuthorsServer interface {
+	SendAndClose(*BulkWriteAuthorsResponse) error
+	Recv() (*BulkWriteAuthorsRequest, error)
+	grpc.ServerStream
+}
+
+type authorCatalogServiceStreamWriteAuthorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *authorCatalogServiceStreamWriteAuthorsServer) SendAndClose(m *BulkWriteAuthorsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *authorCatalogServiceStreamWriteAuthorsServer) Recv() (*BulkWriteAuthorsRequest, error) {
+	m := new(BulkWriteAuthorsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AuthorCatalogService_ServiceDesc is the grpc.ServiceDesc for AuthorCatalogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthorCatalogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "authorcatalog.AuthorCatalogService",
+	HandlerType: (*AuthorCatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAuthor",
+			Handler:    _AuthorCatalogService_GetAuthor_Handler,
+		},
+		{
+			MethodName: "CreateAuthor",
+			Handler:    _AuthorCatalogService_CreateAuthor_Handler,
+		},
+		{
+			MethodName: "UpdateAuthor",
+			Handler:    _AuthorCatalogService_UpdateAuthor_Handler,
+		},
+		{
+			MethodName: "PatchAuthor",
+			Handler:    _AuthorCatalogService_PatchAuthor_Handler,
+		},
+		{
+			MethodName: "DeleteAuthor",
+			Handler:    _AuthorCatalogService_DeleteAuthor_Handler,
+		},
+		{
+			MethodName: "RestoreAuthor",
+			Handler:    _AuthorCatalogService_RestoreAuthor_Handler,
+		},
+		{
+			MethodName: "ListDeletedAuthors",
+			Handler:    _AuthorCatalogService_ListDeletedAuthors_Handler,
+		},
+		{
+			MethodName: "PurgeAuthor",
+			Handler:    _AuthorCatalogService_PurgeAuthor_Handler,
+		},
+		{
+			MethodName: "PurgeExpiredAuthors",
+			Handler:    _AuthorCatalogService_PurgeExpiredAuthors_Handler,
+		},
+		{
+			MethodName: "ListAuthorsPage",
+			Handler:    _AuthorCatalogService_ListAuthorsPage_Handler,
+		},
+		{
+			MethodName: "SearchAuthors",
+			Handler:    _AuthorCatalogService_SearchAuthors_Handler,
+		},
+		{
+			MethodName: "GetAuthorHistory",
+			Handler:    _AuthorCatalogService_GetAuthorHistory_Handler,
+		},
+		{
+			MethodName: "ListAuthorRevisions",
+			Handler:    _AuthorCatalogService_ListAuthorRevisions_Handler,
+		},
+		{
+			MethodName: "GetAuthorRevision",
+			Handler:    _AuthorCatalogService_GetAuthorRevision_Handler,
+		},
+		{
+			MethodName: "RevertAuthor",
+			Handler:    _AuthorCatalogService_RevertAuthor_Handler,
+		},
+		{
+			MethodName: "BulkWriteAuthors",
+			Handler:    _AuthorCatalogService_BulkWriteAuthors_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAuthors",
+			Handler:       _AuthorCatalogService_WatchAuthors_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamWriteAuthors",
+			Handler:       _AuthorCatalogService_StreamWriteAuthors_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "authorcatalog/author_catalog.proto",
+}
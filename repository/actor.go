@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// WithActor returns a copy of the context carrying who makes the changes, such
+// as who deletes an author.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns who makes the changes, or an empty string when the
+// context does not carry one.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestActor(t *testing.T) {
+	Convey("When the context carries an actor", t, func() {
+		ctx := WithActor(context.Background(), "editor")
+		So(ActorFromContext(ctx), ShouldEqual, "editor")
+	})
+
+	Convey("When the context carries no actor", t, func() {
+		So(ActorFromContext(context.Background()), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditCreated  = "created"
+	AuditUpdated  = "updated"
+	AuditDeleted  = "deleted"
+	AuditRestored = "restored"
+	AuditReverted = "reverted"
+	AuditPurged   = "purged"
+)
+
+// AuditEntry records a change of an author.
+type AuditEntry struct {
+	// ID identifies the entry.
+	ID string `json:"id"`
+	// AuthorID is the changed author.
+	AuthorID string `json:"authorId"`
+	// Action is the change, such as AuditUpdated.
+	Action string `json:"action"`
+	// Actor is who made the change, when known.
+	Actor string `json:"actor,omitempty"`
+	// RequestID is the ID of the request that made the change.
+	RequestID string `json:"requestId,omitempty"`
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+	// Before is the author before the change, absent when it was created.
+	Before *data.Author `json:"before,omitempty"`
+	// After is the author after the change, absent when it was purged.
+	After *data.Author `json:"after,omitempty"`
+}
+
+// AuditStore stores the audit log of the authors.
+type AuditStore interface {
+	// Record adds an entry to the audit log.
+	Record(ctx context.Context, entry AuditEntry) error
+	// History returns the entries of an author, the oldest first.
+	History(ctx context.Context, authorID string) ([]AuditEntry, error)
+}
+
+// newAuditEntry creates the entry of a change made by the actor and request of
+// the context.
+func newAuditEntry(ctx context.Context, action string, authorID string, before *data.Author, after *data.Author, now time.Time) AuditEntry {
+	return AuditEntry{
+		ID:        uuid.New().String(),
+		AuthorID:  authorID,
+		Action:    action,
+		Actor:     ActorFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		Time:      now,
+		Before:    before,
+		After:     after,
+	}
+}
+
+// inMemoryAudit is a simple in-memory audit store, safe for concurrent use.
+type inMemoryAudit struct {
+	mu      sync.Mutex
+	entries map[string][]AuditEntry
+	// journal persists the entries before they are added, when set.
+	journal auditJournal
+}
+
+// auditJournal persists the entries of an in-memory audit store. An entry
+// failing to be persisted is not added.
+type auditJournal interface {
+	// recorded persists an entry.
+	recorded(entry AuditEntry) error
+}
+
+// NewInMemoryAudit creates a new in-memory audit store.
+func NewInMemoryAudit() AuditStore {
+	return &inMemoryAudit{
+		entries: make(map[string][]AuditEntry),
+	}
+}
+
+// Record adds an entry to the history of its author.
+func (a *inMemoryAudit) Record(_ context.Context, entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.journal != nil {
+		if err := a.journal.recorded(entry); err != nil {
+			return err
+		}
+	}
+	a.entries[entry.AuthorID] = append(a.entries[entry.AuthorID], entry)
+	return nil
+}
+
+// History returns the entries of an author, the oldest first.
+func (a *inMemoryAudit) History(_ context.Context, authorID string) ([]AuditEntry, error) {
+	a.mu.Lock()
+	entries := append([]AuditEntry{}, a.entries[authorID]...)
+	a.mu.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
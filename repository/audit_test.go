@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestInMemoryAudit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given an in-memory audit store", t, func() {
+		audit := NewInMemoryAudit()
+		author := data.Author{ID: "author", Name: "Ada Lovelace"}
+		later := newAuditEntry(ctx, AuditDeleted, "author", &author, &author, now.Add(time.Minute))
+		earlier := newAuditEntry(ctx, AuditCreated, "author", nil, &author, now)
+		So(audit.Record(ctx, later), ShouldBeNil)
+		So(audit.Record(ctx, earlier), ShouldBeNil)
+		So(audit.Record(ctx, newAuditEntry(ctx, AuditCreated, "other", nil, &author, now)), ShouldBeNil)
+
+		Convey("The history of an author should list its entries from the oldest", func() {
+			entries, err := audit.History(ctx, "author")
+			So(err, ShouldBeNil)
+			So(entries, ShouldResemble, []AuditEntry{earlier, later})
+		})
+
+		Convey("The history of an unknown author should be empty", func() {
+			entries, err := audit.History(ctx, "unknown")
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestRepositoryAudit(t *testing.T) {
+	Convey("Given a repository", t, func() {
+		ctx := WithRequestID(WithActor(context.Background(), "editor"), "request")
+		now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		repo := New(NewInMemoryDatabase(), NewFakeClientRepository(), WithRetention(0), WithClock(func() time.Time { return now }))
+		_, err := repo.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+		So(err, ShouldBeNil)
+
+		Convey("Adding an author should record its creation", func() {
+			entries, err := repo.AuthorHistory(ctx, "author")
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Action, ShouldEqual, AuditCreated)
+			So(entries[0].Actor, ShouldEqual, "editor")
+			So(entries[0].RequestID, ShouldEqual, "request")
+			So(entries[0].Time, ShouldEqual, now)
+			So(entries[0].Before, ShouldBeNil)
+			So(entries[0].After.Name, ShouldEqual, "Ada")
+			So(entries[0].After.Version, ShouldEqual, InitialVersion)
+		})
+
+		Convey("Changes should record the author before and after them", func() {
+			_, err := repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 0)
+			So(err, ShouldBeNil)
+			_, err = repo.PatchAuthor(ctx, "author", data.AuthorPatch{
+				Author: data.Author{Nationality: "British"},
+				Fields: []string{data.FieldNationality},
+			}, 0)
+			So(err, ShouldBeNil)
+			So(repo.DeleteAuthor(ctx, "author", 0), ShouldBeNil)
+			_, err = repo.RestoreAuthor(ctx, "author", 0)
+			So(err, ShouldBeNil)
+
+			entries, _ := repo.AuthorHistory(ctx, "author")
+			So(entries, ShouldHaveLength, 5)
+			So(entries[1].Action, ShouldEqual, AuditUpdated)
+			So(entries[1].Before.Name, ShouldEqual, "Ada")
+			So(entries[1].After.Name, ShouldEqual, "Ada Lovelace")
+			So(entries[2].Action, ShouldEqual, AuditUpdated)
+			So(entries[2].After.Nationality, ShouldEqual, "British")
+			So(entries[3].Action, ShouldEqual, AuditDeleted)
+			So(entries[3].Before.DeletedAt, ShouldBeNil)
+			So(entries[3].After.DeletedBy, ShouldEqual, "editor")
+			So(entries[4].Action, ShouldEqual, AuditRestored)
+			So(entries[4].After.DeletedAt, ShouldBeNil)
+		})
+
+		Convey("Failed changes should not be recorded", func() {
+			_, err := repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 5)
+			So(err, ShouldWrap, ErrConflict)
+			entries, _ := repo.AuthorHistory(ctx, "author")
+			So(entries, ShouldHaveLength, 1)
+		})
+
+		Convey("The history of a purged author should be kept", func() {
+			So(repo.DeleteAuthor(ctx, "author", 0), ShouldBeNil)
+			So(repo.PurgeAuthor(ctx, "author"), ShouldBeNil)
+
+			entries, err := repo.AuthorHistory(ctx, "author")
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 3)
+			So(entries[2].Action, ShouldEqual, AuditPurged)
+			So(entries[2].Before.ID, ShouldEqual, "author")
+			So(entries[2].After, ShouldBeNil)
+		})
+
+		Convey("The history of an unknown author should fail", func() {
+			_, err := repo.AuthorHistory(ctx, "unknown")
+			So(err, ShouldWrap, ErrNotFound)
+		})
+	})
+
+	Convey("Given a repository whose authors change while they are updated", t, func() {
+		ctx := context.Background()
+		db := &racingDatabase{Database: NewInMemoryDatabase(), races: 1}
+		repo := New(db, NewFakeClientRepository())
+		_, _ = repo.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+
+		Convey("An update without an expected version should record the author it replaced", func() {
+			updated, err := repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 0)
+			So(err, ShouldBeNil)
+			So(updated.Version, ShouldEqual, 3)
+			entries, _ := repo.AuthorHistory(ctx, "author")
+			So(entries, ShouldHaveLength, 2)
+			So(entries[1].Before.Name, ShouldEqual, "Ada Byron")
+			So(entries[1].Before.Version, ShouldEqual, 2)
+		})
+
+		Convey("An update that keeps racing should fail", func() {
+			db.races = maxChangeAttempts
+			_, err := repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 0)
+			So(err, ShouldWrap, ErrConflict)
+			entries, _ := repo.AuthorHistory(ctx, "author")
+			So(entries, ShouldHaveLength, 1)
+		})
+	})
+
+	Convey("Given a repository whose audit store has no entries", t, func() {
+		ctx := context.Background()
+		db := NewInMemoryDatabase()
+		_, _ = db.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+		repo := New(db, NewFakeClientRepository())
+
+		Convey("The history of an existing author should be empty", func() {
+			entries, err := repo.AuthorHistory(ctx, "author")
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+	})
+}
+
+// racingDatabase renames an author right before the next races updates, as a
+// concurrent request would.
+type racingDatabase struct {
+	Database
+	races int
+}
+
+func (db *racingDatabase) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	if db.races > 0 {
+		db.races--
+		_, _ = db.Database.UpdateAuthor(ctx, data.Author{ID: author.ID, Name: "Ada Byron"}, 0)
+	}
+	return db.Database.UpdateAuthor(ctx, author, expectedVersion)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+// ErrAborted is returned for the operations of an atomic batch that were not
+// applied because another operation of the batch failed.
+var ErrAborted = errors.New("aborted")
+
+// Kinds of the bulk operations.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkMode tells how a batch handles the operations that fail.
+type BulkMode string
+
+const (
+	// BulkAtomic applies all the operations of the batch or none of them.
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort applies the operations that succeed whatever the others.
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// ParseBulkMode returns the mode named by the string, BulkAtomic when it is empty.
+func ParseBulkMode(name string) (BulkMode, error) {
+	switch BulkMode(name) {
+	case "", BulkAtomic:
+		return BulkAtomic, nil
+	case BulkBestEffort:
+		return BulkBestEffort, nil
+	default:
+		return "", invalidArgumentError("unknown bulk mode %q", name)
+	}
+}
+
+// BulkOperation is a change of an author applied in a batch. A batch changes
+// each author at most once.
+type BulkOperation struct {
+	// Kind is the change, such as BulkUpdate.
+	Kind string `json:"kind"`
+	// Author is the created or updated author.
+	Author data.Author `json:"author"`
+	// ID is the deleted author.
+	ID string `json:"id,omitempty"`
+	// ExpectedVersion is the version the updated or deleted author must be at,
+	// or zero for any version.
+	ExpectedVersion int64 `json:"expectedVersion,omitempty"`
+	// DeletedBy and DeletedAt record the deletion of the author. They are set
+	// by the repository.
+	DeletedBy string    `json:"-"`
+	DeletedAt time.Time `json:"-"`
+}
+
+// AuthorID returns the ID of the author changed by the operation.
+func (o BulkOperation) AuthorID() string {
+	if o.Kind == BulkDelete {
+		return o.ID
+	}
+	return o.Author.ID
+}
+
+// BulkResult is the result of an operation of a batch.
+type BulkResult struct {
+	// ID is the author changed by the operation.
+	ID string
+	// Author is the author after the operation, absent when it failed.
+	Author *data.Author
+	// Before is the author before the operation, absent when it was created.
+	Before *data.Author
+	// Err is why the operation failed, nil when it was applied.
+	Err error
+}
+
+// planBulk returns the result each operation would have on the current
+// authors, by ID and whether deleted or not, without storing anything. In
+// atomic mode, the operations fail with ErrAborted when another one fails.
+func planBulk(ops []BulkOperation, current map[string]data.Author, mode BulkMode) []BulkResult {
+	results := make([]BulkResult, len(ops))
+	seen := make(map[string]bool, len(ops))
+	failed := false
+	for index, op := range ops {
+		id := op.AuthorID()
+		if seen[id] {
+			results[index] = BulkResult{ID: id, Err: invalidArgumentError("author %q is changed twice in the batch", id)}
+		} else {
+			results[index] = planOperation(op, current)
+		}
+		seen[id] = true
+		failed = failed || results[index].Err != nil
+	}
+	if failed && mode == BulkAtomic {
+		abortBulk(results)
+	}
+	return results
+}
+
+// abortBulk fails the operations of an atomic batch that would have been
+// applied with ErrAborted, since another operation failed.
+func abortBulk(results []BulkResult) {
+	for index, result := range results {
+		if result.Err == nil {
+			results[index] = BulkResult{ID: result.ID, Err: abortedError(result.ID)}
+		}
+	}
+}
+
+// planOperation returns the result of an operation on the current authors.
+func planOperation(op BulkOperation, current map[string]data.Author) BulkResult {
+	id := op.AuthorID()
+	result := BulkResult{ID: id}
+	stored, exists := current[id]
+	if exists {
+		before := stored
+		result.Before = &before
+	}
+	var after data.Author
+	switch op.Kind {
+	case BulkCreate:
+		if exists {
+			return BulkResult{ID: id, Err: alreadyExistsError(id)}
+		}
+		after = op.Author
+		after.Version = InitialVersion
+		after.DeletedAt = nil
+		after.DeletedBy = ""
+	case BulkUpdate, BulkDelete:
+		if !exists || stored.IsDeleted() {
+			return BulkResult{ID: id, Err: notFoundError(id)}
+		}
+		if err := CheckVersion(id, stored.Version, op.ExpectedVersion); err != nil {
+			return BulkResult{ID: id, Err: err}
+		}
+		after = op.Author
+		if op.Kind == BulkDelete {
+			deletedAt := op.DeletedAt
+			after = stored
+			after.DeletedAt = &deletedAt
+			after.DeletedBy = op.DeletedBy
+		}
+		after.Version = stored.Version + 1
+	default:
+		return BulkResult{ID: id, Err: invalidArgumentError("unknown bulk operation %q", op.Kind)}
+	}
+	result.Author = &after
+	return result
+}
+
+// abortedError returns the error of an operation of an atomic batch that was
+// not applied.
+func abortedError(id string) error {
+	return fmt.Errorf("author %q was not changed since another operation of the batch failed: %w", id, ErrAborted)
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestParseBulkMode(t *testing.T) {
+	Convey("Parsing a bulk mode", t, func() {
+		Convey("An empty mode should be atomic", func() {
+			mode, err := ParseBulkMode("")
+			So(err, ShouldBeNil)
+			So(mode, ShouldEqual, BulkAtomic)
+		})
+
+		Convey("The best effort mode should be parsed", func() {
+			mode, err := ParseBulkMode("best_effort")
+			So(err, ShouldBeNil)
+			So(mode, ShouldEqual, BulkBestEffort)
+		})
+
+		Convey("An unknown mode should be an invalid argument", func() {
+			_, err := ParseBulkMode("eventually")
+			So(err, ShouldWrap, ErrInvalidArgument)
+		})
+	})
+}
+
+func TestInMemoryBulkWrite(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory database with two authors", t, func() {
+		db := NewInMemoryDatabase()
+		_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+		_, _ = db.AddAuthor(ctx, data.Author{ID: "alan", Name: "Alan"})
+
+		Convey("An atomic batch of valid operations should apply all of them", func() {
+			results, err := db.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 1},
+				{Kind: BulkDelete, ID: "alan", DeletedBy: "editor"},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results, ShouldHaveLength, 3)
+			for _, result := range results {
+				So(result.Err, ShouldBeNil)
+			}
+			So(results[0].Author.Version, ShouldEqual, InitialVersion)
+			So(results[0].Before, ShouldBeNil)
+			So(results[1].Author.Version, ShouldEqual, 2)
+			So(results[1].Before.Name, ShouldEqual, "Ada")
+
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada Lovelace")
+			_, err = db.GetAuthor(ctx, "alan")
+			So(err, ShouldWrap, ErrNotFound)
+			deleted, err := db.GetDeletedAuthor(ctx, "alan")
+			So(err, ShouldBeNil)
+			So(deleted.DeletedBy, ShouldEqual, "editor")
+			revisions, _ := db.ListRevisions(ctx, "grace")
+			So(revisions, ShouldHaveLength, 1)
+		})
+
+		Convey("An atomic batch with a failed operation should apply none of them", func() {
+			results, err := db.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 3},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, ErrAborted)
+			So(results[1].Err, ShouldWrap, ErrConflict)
+
+			_, err = db.GetAuthor(ctx, "grace")
+			So(err, ShouldWrap, ErrNotFound)
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("A best effort batch should apply the operations that succeed", func() {
+			results, err := db.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "ada", Name: "Ada"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "alan", Name: "Alan Turing"}},
+			}, BulkBestEffort)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, ErrAlreadyExists)
+			So(results[1].Err, ShouldWrap, ErrNotFound)
+			So(results[2].Err, ShouldBeNil)
+
+			author, _ := db.GetAuthor(ctx, "alan")
+			So(author.Name, ShouldEqual, "Alan Turing")
+		})
+
+		Convey("An author changed twice in a batch should be an invalid argument", func() {
+			results, err := db.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}},
+				{Kind: BulkDelete, ID: "ada"},
+			}, BulkBestEffort)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldBeNil)
+			So(results[1].Err, ShouldWrap, ErrInvalidArgument)
+		})
+	})
+}
+
+func TestRepositoryBulkWrite(t *testing.T) {
+	Convey("Given a repository with an author", t, func() {
+		ctx := WithActor(context.Background(), "editor")
+		repo := New(NewInMemoryDatabase(), NewFakeClientRepository())
+		_, _ = repo.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+
+		Convey("A batch should record the deletions and audit the applied operations", func() {
+			results, err := repo.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkDelete, ID: "ada"},
+			}, BulkBestEffort)
+			So(err, ShouldBeNil)
+			So(results[1].Err, ShouldBeNil)
+			So(results[1].Author.DeletedBy, ShouldEqual, "editor")
+			So(results[1].Author.DeletedAt, ShouldNotBeNil)
+
+			entries, err := repo.AuthorHistory(ctx, "grace")
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Action, ShouldEqual, AuditCreated)
+			entries, _ = repo.AuthorHistory(ctx, "ada")
+			So(entries, ShouldHaveLength, 2)
+			So(entries[1].Action, ShouldEqual, AuditDeleted)
+			So(entries[1].Actor, ShouldEqual, "editor")
+		})
+
+		Convey("The failed operations should not be audited", func() {
+			results, err := repo.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkCreate, Author: data.Author{ID: "ada", Name: "Ada"}},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, ErrAborted)
+			entries, _ := repo.AuthorHistory(ctx, "grace")
+			So(entries, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling a service whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker fails fast after a number of consecutive failed calls. Once
+// open, it rejects the calls for the cooldown and then lets a single trial
+// call through: the breaker closes if it succeeds and opens again otherwise.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	trial     bool
+}
+
+// newCircuitBreaker creates a breaker opening after threshold consecutive
+// failures. A threshold of zero disables the breaker.
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow returns ErrCircuitOpen when the call must not be made.
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+	if b.trial || now.Before(b.openedAt.Add(b.cooldown)) {
+		return ErrCircuitOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// isOpen reports whether the calls are rejected at the time, without starting
+// a trial call.
+func (b *circuitBreaker) isOpen(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return false
+	}
+	return b.trial || now.Before(b.openedAt.Add(b.cooldown))
+}
+
+// record updates the breaker with the outcome of an allowed call, success being
+// false when the service failed rather than answered.
+func (b *circuitBreaker) record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = now
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a circuit breaker opening after two failures", t, func() {
+		breaker := newCircuitBreaker(2, time.Minute)
+
+		Convey("It should stay closed until the threshold", func() {
+			breaker.record(false, now)
+			So(breaker.allow(now), ShouldBeNil)
+			breaker.record(true, now)
+			breaker.record(false, now)
+			So(breaker.allow(now), ShouldBeNil)
+		})
+
+		Convey("When it opens", func() {
+			breaker.record(false, now)
+			breaker.record(false, now)
+
+			Convey("It should reject the calls during the cooldown", func() {
+				So(breaker.isOpen(now), ShouldBeTrue)
+				So(breaker.allow(now.Add(59*time.Second)), ShouldEqual, ErrCircuitOpen)
+			})
+
+			Convey("It should not be reported open after the cooldown", func() {
+				So(breaker.isOpen(now.Add(time.Minute)), ShouldBeFalse)
+				So(breaker.allow(now.Add(time.Minute)), ShouldBeNil)
+			})
+
+			Convey("It should let a single trial call through after the cooldown", func() {
+				later := now.Add(time.Minute)
+				So(breaker.allow(later), ShouldBeNil)
+				So(breaker.allow(later), ShouldEqual, ErrCircuitOpen)
+
+				Convey("A failed trial should open it again", func() {
+					breaker.record(false, later)
+					So(breaker.allow(later.Add(time.Second)), ShouldEqual, ErrCircuitOpen)
+				})
+
+				Convey("A successful trial should close it", func() {
+					breaker.record(true, later)
+					So(breaker.allow(later), ShouldBeNil)
+				})
+			})
+		})
+	})
+
+	Convey("Given a disabled circuit breaker", t, func() {
+		breaker := newCircuitBreaker(0, time.Minute)
+		for i := 0; i < 10; i++ {
+			breaker.record(false, now)
+		}
+		So(breaker.allow(now), ShouldBeNil)
+	})
+}
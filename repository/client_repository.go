@@ -2,32 +2,239 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	mgrpc "github.com/wcodesoft/mosha-service-common/grpc"
 	qpb "github.com/wcodesoft/mosha-service-common/protos/quoteservice"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math/rand"
+	"strings"
+	"time"
 )
 
+// tracerName names the tracer of the spans of the repository.
+const tracerName = "github.com/wcodesoft/mosha-author-service/repository"
+
 type ClientRepository interface {
-	DeleteAuthorQuotes(authorID string) (bool, error)
+	DeleteAuthorQuotes(ctx context.Context, authorID string) (bool, error)
+	// Ping checks that QuoteService can be called, without calling it.
+	Ping(ctx context.Context) error
+}
+
+// ClientConfig configures how the QuoteService is called.
+type ClientConfig struct {
+	// Timeout is the deadline of each attempt of a call.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts of a call failing with a transient error.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled before each
+	// following one. A random jitter of up to half the delay is subtracted.
+	InitialBackoff time.Duration
+	// MaxBackoff is the longest delay between two attempts.
+	MaxBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed calls opening the
+	// circuit breaker. Zero disables the breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long the open circuit breaker fails fast before
+	// letting a trial call through.
+	BreakerCooldown time.Duration
+}
+
+// DefaultClientConfig returns the configuration used when none is set.
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Timeout:          2 * time.Second,
+		MaxAttempts:      3,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
 }
 
 type clientRepository struct {
 	quoteClient qpb.QuoteServiceClient
+	config      ClientConfig
+	breaker     *circuitBreaker
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+	// state returns the state of the connection to QuoteService, if known.
+	state func() connectivity.State
+	// connect makes an idle connection reconnect.
+	connect func()
 }
 
-// DeleteAuthorQuotes deletes all quotes from an author.
-func (c *clientRepository) DeleteAuthorQuotes(authorID string) (bool, error) {
-	res, err := c.quoteClient.DeleteAllQuotesByAuthor(context.Background(), &qpb.DeleteQuotesByAuthorRequest{AuthorId: authorID})
-	return res.Success, err
+// DeleteAuthorQuotes deletes all quotes from an author, retrying on transient
+// errors. It fails fast with ErrCircuitOpen while QuoteService is down.
+func (c *clientRepository) DeleteAuthorQuotes(ctx context.Context, authorID string) (bool, error) {
+	if err := c.breaker.allow(c.now()); err != nil {
+		return false, fmt.Errorf("QuoteService: %w", err)
+	}
+	request := &qpb.DeleteQuotesByAuthorRequest{AuthorId: authorID}
+	var err error
+	for attempt := 1; ; attempt++ {
+		var res *qpb.DeleteQuoteResponse
+		res, err = c.deleteAllQuotesByAuthor(ctx, request)
+		if err == nil {
+			c.breaker.record(true, c.now())
+			return res.GetSuccess(), nil
+		}
+		if attempt >= c.config.MaxAttempts || !isTransient(err) || ctx.Err() != nil {
+			break
+		}
+		backoff := c.backoff(attempt)
+		Logger(ctx).Warn("retrying QuoteService call", "author_id", authorID, "attempt", attempt, "backoff", backoff, "err", err)
+		if err := c.sleep(ctx, backoff); err != nil {
+			break
+		}
+	}
+	// Only the transient errors count as failures of QuoteService, the others
+	// are answers to the request.
+	c.breaker.record(!isTransient(err), c.now())
+	Logger(ctx).Error("QuoteService call failed", "author_id", authorID, "err", err)
+	return false, err
+}
+
+// Ping fails when the circuit breaker is open or the connection to
+// QuoteService is failing. An idle connection is asked to reconnect.
+func (c *clientRepository) Ping(_ context.Context) error {
+	if c.breaker.isOpen(c.now()) {
+		return fmt.Errorf("QuoteService: %w", ErrCircuitOpen)
+	}
+	if c.state == nil {
+		return nil
+	}
+	switch state := c.state(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return fmt.Errorf("QuoteService connection is %s: %w", strings.ToLower(state.String()), ErrDependencyFailed)
+	case connectivity.Idle:
+		c.connect()
+	}
+	return nil
+}
+
+// deleteAllQuotesByAuthor makes one attempt of the call, bounded by the
+// timeout, in a span whose context is propagated to QuoteService along with
+// the request ID.
+func (c *clientRepository) deleteAllQuotesByAuthor(ctx context.Context, request *qpb.DeleteQuotesByAuthorRequest) (*qpb.DeleteQuoteResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "QuoteService/DeleteAllQuotesByAuthor",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService("quoteservice.QuoteService"),
+			semconv.RPCMethod("DeleteAllQuotesByAuthor"),
+		),
+	)
+	defer span.End()
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		md.Set(RequestIDMetadata, requestID)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	res, err := c.quoteClient.DeleteAllQuotesByAuthor(metadata.NewOutgoingContext(ctx, md), request)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+	return res, err
+}
+
+// metadataCarrier lets the trace context be injected into the gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	values := metadata.MD(m).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (m metadataCarrier) Set(key string, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// backoff returns the jittered delay before the retry following the attempt.
+func (c *clientRepository) backoff(attempt int) time.Duration {
+	backoff := c.config.InitialBackoff
+	for i := 1; i < attempt && backoff < c.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if c.config.MaxBackoff > 0 && backoff > c.config.MaxBackoff {
+		backoff = c.config.MaxBackoff
+	}
+	if backoff <= 1 {
+		return backoff
+	}
+	return backoff - time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// isTransient reports whether the call may succeed when retried.
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext waits for the duration, or returns the error of the context if
+// it is done before.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newClientRepository creates a client repository calling the QuoteService client.
+func newClientRepository(quoteClient qpb.QuoteServiceClient, config ClientConfig) *clientRepository {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &clientRepository{
+		quoteClient: quoteClient,
+		config:      config,
+		breaker:     newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		now:         time.Now,
+		sleep:       sleepContext,
+	}
 }
 
 // NewClientRepository creates a new client repository.
-func NewClientRepository(clientInfo mgrpc.ClientInfo) (ClientRepository, error) {
+func NewClientRepository(clientInfo mgrpc.ClientInfo, config ClientConfig) (ClientRepository, error) {
 	conn, err := clientInfo.NewClientConnection()
 	if err != nil {
 		return nil, err
 	}
-	client := qpb.NewQuoteServiceClient(conn)
-	return &clientRepository{
-		quoteClient: client,
-	}, nil
+	client := newClientRepository(qpb.NewQuoteServiceClient(conn), config)
+	client.state = conn.GetState
+	client.connect = conn.Connect
+	return client, nil
 }
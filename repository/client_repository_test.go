@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	qpb "github.com/wcodesoft/mosha-service-common/protos/quoteservice"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// scriptedQuoteClient answers the calls with the scripted responses in turn.
+type scriptedQuoteClient struct {
+	qpb.QuoteServiceClient
+	responses []*qpb.DeleteQuoteResponse
+	errors    []error
+	calls     int
+	deadlines []bool
+	metadata  []metadata.MD
+}
+
+func (s *scriptedQuoteClient) DeleteAllQuotesByAuthor(ctx context.Context, _ *qpb.DeleteQuotesByAuthorRequest, _ ...grpc.CallOption) (*qpb.DeleteQuoteResponse, error) {
+	_, hasDeadline := ctx.Deadline()
+	s.deadlines = append(s.deadlines, hasDeadline)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	s.metadata = append(s.metadata, md)
+	index := s.calls
+	s.calls++
+	if index >= len(s.errors) {
+		index = len(s.errors) - 1
+	}
+	return s.responses[index], s.errors[index]
+}
+
+func newTestClientRepository(quoteClient qpb.QuoteServiceClient, config ClientConfig) (*clientRepository, *[]time.Duration) {
+	client := newClientRepository(quoteClient, config)
+	var sleeps []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return client, &sleeps
+}
+
+func TestClientRepository(t *testing.T) {
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	config := ClientConfig{
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	}
+
+	Convey("When QuoteService succeeds", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{{Success: true}},
+			errors:    []error{nil},
+		}
+		client, _ := newTestClientRepository(quoteClient, config)
+		success, err := client.DeleteAuthorQuotes(ctx, "author")
+		So(err, ShouldBeNil)
+		So(success, ShouldBeTrue)
+		So(quoteClient.deadlines, ShouldResemble, []bool{true})
+	})
+
+	Convey("When QuoteService fails with a transient error", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil, nil, {Success: true}},
+			errors:    []error{unavailable, unavailable, nil},
+		}
+		client, sleeps := newTestClientRepository(quoteClient, config)
+		success, err := client.DeleteAuthorQuotes(ctx, "author")
+
+		Convey("The call should be retried with a jittered backoff", func() {
+			So(err, ShouldBeNil)
+			So(success, ShouldBeTrue)
+			So(quoteClient.calls, ShouldEqual, 3)
+			So(len(*sleeps), ShouldEqual, 2)
+			So((*sleeps)[0], ShouldBeBetweenOrEqual, 50*time.Millisecond, 100*time.Millisecond)
+			So((*sleeps)[1], ShouldBeBetweenOrEqual, 100*time.Millisecond, 200*time.Millisecond)
+		})
+	})
+
+	Convey("When QuoteService fails with a permanent error", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil},
+			errors:    []error{status.Error(codes.InvalidArgument, "invalid")},
+		}
+		client, _ := newTestClientRepository(quoteClient, config)
+		success, err := client.DeleteAuthorQuotes(ctx, "author")
+
+		Convey("The call should not be retried", func() {
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(success, ShouldBeFalse)
+			So(quoteClient.calls, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When QuoteService returns no response", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil},
+			errors:    []error{nil},
+		}
+		client, _ := newTestClientRepository(quoteClient, config)
+		success, err := client.DeleteAuthorQuotes(ctx, "author")
+		So(err, ShouldBeNil)
+		So(success, ShouldBeFalse)
+	})
+
+	Convey("When QuoteService keeps failing", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil, {Success: true}},
+			errors:    []error{unavailable, nil},
+		}
+		config := config
+		config.MaxAttempts = 1
+		client, _ := newTestClientRepository(quoteClient, config)
+		now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		client.now = func() time.Time { return now }
+		quoteClient.responses = []*qpb.DeleteQuoteResponse{nil, nil, {Success: true}}
+		quoteClient.errors = []error{unavailable, unavailable, nil}
+		_, _ = client.DeleteAuthorQuotes(ctx, "author")
+		_, _ = client.DeleteAuthorQuotes(ctx, "author")
+
+		Convey("The circuit breaker should fail fast", func() {
+			_, err := client.DeleteAuthorQuotes(ctx, "author")
+			So(err, ShouldWrap, ErrCircuitOpen)
+			So(quoteClient.calls, ShouldEqual, 2)
+		})
+
+		Convey("The circuit breaker should let a call through after the cooldown", func() {
+			now = now.Add(time.Minute)
+			success, err := client.DeleteAuthorQuotes(ctx, "author")
+			So(err, ShouldBeNil)
+			So(success, ShouldBeTrue)
+			So(quoteClient.calls, ShouldEqual, 3)
+		})
+	})
+
+	Convey("When QuoteService keeps rejecting the calls", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil},
+			errors:    []error{status.Error(codes.PermissionDenied, "denied")},
+		}
+		client, _ := newTestClientRepository(quoteClient, config)
+		for call := 0; call < config.BreakerThreshold+1; call++ {
+			_, _ = client.DeleteAuthorQuotes(ctx, "author")
+		}
+
+		Convey("The circuit breaker should stay closed", func() {
+			So(quoteClient.calls, ShouldEqual, config.BreakerThreshold+1)
+			So(client.Ping(ctx), ShouldBeNil)
+		})
+	})
+
+	Convey("When the context is canceled", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil},
+			errors:    []error{unavailable},
+		}
+		client := newClientRepository(quoteClient, config)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := client.DeleteAuthorQuotes(canceled, "author")
+
+		Convey("The call should not be retried", func() {
+			So(status.Code(err), ShouldEqual, codes.Unavailable)
+			So(quoteClient.calls, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When checking QuoteService", t, func() {
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil},
+			errors:    []error{unavailable},
+		}
+		client, _ := newTestClientRepository(quoteClient, ClientConfig{MaxAttempts: 1, BreakerThreshold: 1, BreakerCooldown: time.Minute})
+		state := connectivity.Ready
+		connected := false
+		client.state = func() connectivity.State { return state }
+		client.connect = func() { connected = true }
+
+		Convey("A ready connection should pass", func() {
+			So(client.Ping(ctx), ShouldBeNil)
+		})
+
+		Convey("An idle connection should pass and reconnect", func() {
+			state = connectivity.Idle
+			So(client.Ping(ctx), ShouldBeNil)
+			So(connected, ShouldBeTrue)
+		})
+
+		Convey("A failing connection should fail", func() {
+			state = connectivity.TransientFailure
+			err := client.Ping(ctx)
+			So(err, ShouldWrap, ErrDependencyFailed)
+			So(err.Error(), ShouldContainSubstring, "transient_failure")
+		})
+
+		Convey("An open circuit breaker should fail", func() {
+			_, _ = client.DeleteAuthorQuotes(ctx, "author")
+			So(client.Ping(ctx), ShouldWrap, ErrCircuitOpen)
+			So(quoteClient.calls, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When the call is traced", t, func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		quoteClient := &scriptedQuoteClient{
+			responses: []*qpb.DeleteQuoteResponse{nil, {Success: true}},
+			errors:    []error{unavailable, nil},
+		}
+		client, _ := newTestClientRepository(quoteClient, config)
+		callCtx := WithRequestID(metadata.AppendToOutgoingContext(ctx, "x-actor", "admin"), "request-1")
+		_, err := client.DeleteAuthorQuotes(callCtx, "author")
+		So(err, ShouldBeNil)
+
+		Convey("Each attempt should be a client span", func() {
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 2)
+			So(spans[0].Name(), ShouldEqual, "QuoteService/DeleteAllQuotesByAuthor")
+			So(spans[0].SpanKind(), ShouldEqual, trace.SpanKindClient)
+			So(spans[0].Status().Code, ShouldEqual, otelcodes.Error)
+			So(spans[1].Status().Code, ShouldEqual, otelcodes.Unset)
+		})
+
+		Convey("The trace context and request ID should be propagated with the other metadata", func() {
+			spans := recorder.Ended()
+			traceparent := quoteClient.metadata[1].Get("traceparent")
+			So(len(traceparent), ShouldEqual, 1)
+			So(traceparent[0], ShouldContainSubstring, spans[1].SpanContext().SpanID().String())
+			So(quoteClient.metadata[1].Get("x-actor"), ShouldResemble, []string{"admin"})
+			So(quoteClient.metadata[1].Get(RequestIDMetadata), ShouldResemble, []string{"request-1"})
+		})
+	})
+}
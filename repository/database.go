@@ -1,35 +1,153 @@
 package repository
 
 import (
+	"context"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
 )
 
+// InitialVersion is the version of a newly added author.
+const InitialVersion int64 = 1
+
+// Database represents the storage of authors. Changes taking an expected version
+// fail with ErrConflict when the stored author is at another version, unless
+// the expected version is zero.
+//
+// Every change of an author, from its addition to its restoration, stores a
+// revision numbered by the new version of the author. The revisions are kept
+// until the author is permanently deleted.
+//
+// BulkWrite applies a batch of operations and returns the result of each of
+// them, failing as a whole only when the batch cannot be run. In atomic mode,
+// either all the operations are applied or none is.
+//
+// Soft deleted authors are hidden from the listing, search, get and change
+// methods, which behave as if they did not exist. They are only seen by
+// GetDeletedAuthor, ListDeleted, RestoreAuthor and DeleteAuthor, which removes
+// an author permanently whether it is deleted or not.
 type Database interface {
-	AddAuthor(author data.Author) (string, error)
-	ListAll() []data.Author
-	UpdateAuthor(author data.Author) (data.Author, error)
-	DeleteAuthor(id string) error
-	GetAuthor(id string) (data.Author, error)
+	AddAuthor(ctx context.Context, author data.Author) (string, error)
+	ListAll(ctx context.Context) []data.Author
+	ListPage(ctx context.Context, page PageRequest) (AuthorPage, error)
+	UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error)
+	PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error)
+	SoftDeleteAuthor(ctx context.Context, id string, deletedBy string, deletedAt time.Time, expectedVersion int64) (data.Author, error)
+	RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error)
+	DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error
+	GetAuthor(ctx context.Context, id string) (data.Author, error)
+	GetDeletedAuthor(ctx context.Context, id string) (data.Author, error)
+	ListDeleted(ctx context.Context, deletedBefore time.Time) ([]data.Author, error)
+	SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error)
+	ListRevisions(ctx context.Context, id string) ([]Revision, error)
+	GetRevision(ctx context.Context, id string, version int64) (Revision, error)
+	GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error)
+	BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error)
+	Ping(ctx context.Context) error
 }
 
+// authorDB is the stored representation of an author. Documents stored before
+// the profile fields were added decode with those fields empty.
 type authorDB struct {
-	ID     string `bson:"_id" json:"id,omitempty"`
-	Name   string `bson:"name"`
-	PicURL string `bson:"picurl"`
+	ID             string         `bson:"_id" json:"id,omitempty"`
+	Name           string         `bson:"name"`
+	PicURL         string         `bson:"picurl"`
+	Biography      string         `bson:"biography"`
+	BirthDate      *partialDateDB `bson:"birthdate"`
+	DeathDate      *partialDateDB `bson:"deathdate"`
+	Nationality    string         `bson:"nationality"`
+	Occupations    []string       `bson:"occupations"`
+	AlternateNames []string       `bson:"alternatenames"`
+	Version        int64          `bson:"version,omitempty"`
+	DeletedAt      *time.Time     `bson:"deletedat,omitempty"`
+	DeletedBy      string         `bson:"deletedby,omitempty"`
+}
+
+// authorDBFields maps the author fields, as named in JSON, to their stored keys.
+var authorDBFields = map[string]string{
+	data.FieldName:           "name",
+	data.FieldPicURL:         "picurl",
+	data.FieldBiography:      "biography",
+	data.FieldBirthDate:      "birthdate",
+	data.FieldDeathDate:      "deathdate",
+	data.FieldNationality:    "nationality",
+	data.FieldOccupations:    "occupations",
+	data.FieldAlternateNames: "alternatenames",
+}
+
+// patchDocument returns the stored keys and values of the patched fields.
+func patchDocument(patch data.AuthorPatch) (bson.D, error) {
+	raw, err := bson.Marshal(fromAuthor(patch.Author))
+	if err != nil {
+		return nil, err
+	}
+	var values bson.M
+	if err := bson.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	set := bson.D{}
+	for _, field := range patch.Fields {
+		key, ok := authorDBFields[field]
+		if !ok {
+			return nil, invalidArgumentError("field %q cannot be patched", field)
+		}
+		set = append(set, bson.E{Key: key, Value: values[key]})
+	}
+	return set, nil
+}
+
+// partialDateDB is the stored representation of a partial date.
+type partialDateDB struct {
+	Year  int  `bson:"year"`
+	Month int  `bson:"month"`
+	Day   int  `bson:"day"`
+	Circa bool `bson:"circa"`
 }
 
 func fromAuthor(author data.Author) authorDB {
 	return authorDB{
-		ID:     author.ID,
-		Name:   author.Name,
-		PicURL: author.PicURL,
+		ID:             author.ID,
+		Name:           author.Name,
+		PicURL:         author.PicURL,
+		Biography:      author.Biography,
+		BirthDate:      fromPartialDate(author.BirthDate),
+		DeathDate:      fromPartialDate(author.DeathDate),
+		Nationality:    author.Nationality,
+		Occupations:    author.Occupations,
+		AlternateNames: author.AlternateNames,
+		Version:        author.Version,
+		DeletedAt:      author.DeletedAt,
+		DeletedBy:      author.DeletedBy,
 	}
 }
 
 func toAuthor(author authorDB) data.Author {
 	return data.Author{
-		ID:     author.ID,
-		Name:   author.Name,
-		PicURL: author.PicURL,
+		ID:             author.ID,
+		Name:           author.Name,
+		PicURL:         author.PicURL,
+		Biography:      author.Biography,
+		BirthDate:      toPartialDate(author.BirthDate),
+		DeathDate:      toPartialDate(author.DeathDate),
+		Nationality:    author.Nationality,
+		Occupations:    author.Occupations,
+		AlternateNames: author.AlternateNames,
+		Version:        author.Version,
+		DeletedAt:      author.DeletedAt,
+		DeletedBy:      author.DeletedBy,
+	}
+}
+
+func fromPartialDate(date *data.PartialDate) *partialDateDB {
+	if date == nil {
+		return nil
+	}
+	return &partialDateDB{Year: date.Year, Month: date.Month, Day: date.Day, Circa: date.Circa}
+}
+
+func toPartialDate(date *partialDateDB) *data.PartialDate {
+	if date == nil {
+		return nil
 	}
+	return &data.PartialDate{Year: date.Year, Month: date.Month, Day: date.Day, Circa: date.Circa}
 }
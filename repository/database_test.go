@@ -6,6 +6,7 @@ import (
 	faker "github.com/brianvoe/gofakeit/v6"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func TestDatabase(t *testing.T) {
@@ -17,6 +18,45 @@ func TestDatabase(t *testing.T) {
 		So(authorHttp.PicURL, ShouldEqual, author.PicURL)
 	})
 
+	Convey("When converting an author with a profile", t, func() {
+		author := data.NewAuthorBuilder().
+			WithName(faker.Name()).
+			WithBiography(faker.Sentence(10)).
+			WithBirthDate(data.PartialDate{Year: -400, Circa: true}).
+			WithDeathDate(data.PartialDate{Year: -348, Month: 5}).
+			WithNationality("Greek").
+			WithOccupations("philosopher").
+			WithAlternateNames("Aristocles").
+			Build()
+		So(toAuthor(fromAuthor(author)), ShouldResemble, author)
+	})
+
+	Convey("When decoding a document stored without profile", t, func() {
+		raw, _ := bson.Marshal(bson.D{{Key: "_id", Value: "ID"}, {Key: "name", Value: "Name"}, {Key: "picurl", Value: "URL"}})
+		var stored authorDB
+		So(bson.Unmarshal(raw, &stored), ShouldBeNil)
+		So(toAuthor(stored), ShouldResemble, data.Author{ID: "ID", Name: "Name", PicURL: "URL"})
+	})
+
+	Convey("When filtering an author by version", t, func() {
+		So(versionFilter("ID", 0), ShouldResemble, bson.D{{Key: "_id", Value: "ID"}})
+		So(versionFilter("ID", 3), ShouldResemble, bson.D{{Key: "_id", Value: "ID"}, {Key: "version", Value: int64(3)}})
+	})
+
+	Convey("When building the document of a patch", t, func() {
+		patch := data.AuthorPatch{
+			Author: data.Author{Name: "Name", BirthDate: &data.PartialDate{Year: 1900}},
+			Fields: []string{data.FieldName, data.FieldBirthDate, data.FieldPicURL},
+		}
+		set, err := patchDocument(patch)
+		So(err, ShouldBeNil)
+		So(set, ShouldResemble, bson.D{
+			{Key: "name", Value: "Name"},
+			{Key: "birthdate", Value: bson.M{"year": int32(1900), "month": int32(0), "day": int32(0), "circa": false}},
+			{Key: "picurl", Value: ""},
+		})
+	})
+
 	Convey("When converting author database to http model", t, func() {
 		author := data.Author{ID: "ID", Name: faker.Name(), PicURL: faker.ImageURL(100, 100)}
 		authorDb := fromAuthor(author)
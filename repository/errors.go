@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when the requested author does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when an author with the same ID already exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrInvalidArgument is returned when a request argument is not valid.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrConflict is returned when the author changed since the expected version.
+	ErrConflict = errors.New("conflict")
+	// ErrFailedPrecondition is returned when the author is not in the state
+	// required by the operation, such as purging an author that is not deleted.
+	ErrFailedPrecondition = errors.New("failed precondition")
+	// ErrDependencyFailed is returned when a service the repository relies on fails.
+	ErrDependencyFailed = errors.New("dependency failed")
+)
+
+// notFoundError returns the error of an author that does not exist.
+func notFoundError(id string) error {
+	return fmt.Errorf("author %q does not exist in database: %w", id, ErrNotFound)
+}
+
+// alreadyExistsError returns the error of an author that already exists.
+func alreadyExistsError(id string) error {
+	return fmt.Errorf("author %q already exists in database: %w", id, ErrAlreadyExists)
+}
+
+// conflictError returns the error of an author whose version is not the expected one.
+func conflictError(id string, version int64, expected int64) error {
+	return fmt.Errorf("author %q is at version %d instead of %d: %w", id, version, expected, ErrConflict)
+}
+
+// CheckVersion returns a conflict error when a version is expected and the
+// current version is another one. An expected version of zero matches any version.
+func CheckVersion(id string, version int64, expected int64) error {
+	if expected != 0 && version != expected {
+		return conflictError(id, version, expected)
+	}
+	return nil
+}
+
+// failedPreconditionError returns the error of an author that is not in the
+// state required by an operation.
+func failedPreconditionError(format string, a ...any) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), ErrFailedPrecondition)
+}
+
+// invalidArgumentError returns the error of an invalid argument.
+func invalidArgumentError(format string, a ...any) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), ErrInvalidArgument)
+}
+
+// InvalidArgument wraps err to report it as an invalid argument.
+func InvalidArgument(err error) error {
+	return fmt.Errorf("%w: %w", err, ErrInvalidArgument)
+}
@@ -1,21 +1,61 @@
 package repository
 
 import (
+	"context"
 	"github.com/wcodesoft/mosha-quote-service/data"
+	"sync"
 )
 
 type FakeClientRepository struct {
-	quotes   []data.Quote
-	retError error
-	retRes   bool
+	mu               sync.Mutex
+	quotes           []data.Quote
+	retError         error
+	retRes           bool
+	deletedAuthorIDs []string
+	pingError        error
+	requestIDs       []string
 	ClientRepository
 }
 
-func (f *FakeClientRepository) DeleteAuthorQuotes(_ string) (bool, error) {
+func (f *FakeClientRepository) DeleteAuthorQuotes(ctx context.Context, authorID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requestIDs = append(f.requestIDs, RequestIDFromContext(ctx))
+	if f.retRes && f.retError == nil {
+		f.deletedAuthorIDs = append(f.deletedAuthorIDs, authorID)
+	}
 	return f.retRes, f.retError
 }
 
+// DeletedAuthorQuotes returns the IDs of the authors whose quotes were deleted.
+func (f *FakeClientRepository) DeletedAuthorQuotes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.deletedAuthorIDs...)
+}
+
+// RequestIDs returns the request IDs of the calls deleting quotes.
+func (f *FakeClientRepository) RequestIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.requestIDs...)
+}
+
+func (f *FakeClientRepository) Ping(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pingError
+}
+
+func (f *FakeClientRepository) SetPingError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pingError = err
+}
+
 func (f *FakeClientRepository) SetDeleteAuthorQuotesReturn(ret bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.retError = err
 	f.retRes = ret
 }
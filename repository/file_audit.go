@@ -0,0 +1,87 @@
+package repository
+
+import "encoding/json"
+
+const (
+	// fileAuditLog is the file of the entries recorded since the snapshot.
+	fileAuditLog = "audit.log"
+	// fileAuditSnapshot is the file of the entries of the audit log.
+	fileAuditSnapshot = "audit.snapshot"
+)
+
+// FileAudit is an audit store persisted to the files of a directory, kept next
+// to a FileDatabase. The entries are held in memory and each one is appended to
+// a log before it is added, as with the FileDatabase. A FileAudit must be
+// closed once done.
+type FileAudit struct {
+	*inMemoryAudit
+	log *fileLog
+}
+
+// fileAuditRecord is an entry appended to the log.
+type fileAuditRecord struct {
+	Sequence int64      `json:"seq"`
+	Entry    AuditEntry `json:"entry"`
+}
+
+// fileAuditState is the state of the audit log after the entry of a sequence.
+type fileAuditState struct {
+	Sequence int64                   `json:"seq"`
+	Entries  map[string][]AuditEntry `json:"entries"`
+}
+
+// NewFileAudit opens the audit log persisted in the directory, creating the
+// directory when it does not exist. Its log is locked until it is closed.
+func NewFileAudit(dir string, opts ...FileOption) (*FileAudit, error) {
+	audit := &FileAudit{
+		inMemoryAudit: &inMemoryAudit{
+			entries: make(map[string][]AuditEntry),
+		},
+	}
+	log, err := openFileLog(dir, fileAuditLog, fileAuditSnapshot, audit, opts...)
+	if err != nil {
+		return nil, err
+	}
+	audit.log = log
+	audit.inMemoryAudit.journal = audit
+	return audit, nil
+}
+
+// Close closes the log of the audit store, which cannot be changed afterwards.
+func (a *FileAudit) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.log.close()
+}
+
+// restore restores the entries of a snapshot.
+func (a *FileAudit) restore(raw []byte) error {
+	var snapshot fileAuditState
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return err
+	}
+	for authorID, entries := range snapshot.Entries {
+		a.entries[authorID] = entries
+	}
+	return nil
+}
+
+// replay adds an entry of the log.
+func (a *FileAudit) replay(raw []byte) error {
+	var record fileAuditRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+	a.entries[record.Entry.AuthorID] = append(a.entries[record.Entry.AuthorID], record.Entry)
+	return nil
+}
+
+// snapshot returns the entries. The audit store holds its lock.
+func (a *FileAudit) snapshot(sequence int64) any {
+	return fileAuditState{Sequence: sequence, Entries: a.entries}
+}
+
+// recorded appends an entry to the log.
+func (a *FileAudit) recorded(entry AuditEntry) error {
+	return a.log.append(fileAuditRecord{Sequence: a.log.next(), Entry: entry})
+}
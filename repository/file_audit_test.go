@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+// reopenAudit closes the file audit store and opens its directory again.
+func reopenAudit(audit *FileAudit, opts ...FileOption) *FileAudit {
+	So(audit.Close(), ShouldBeNil)
+	reopened, err := NewFileAudit(filepath.Dir(audit.log.path), opts...)
+	So(err, ShouldBeNil)
+	return reopened
+}
+
+func TestFileAudit(t *testing.T) {
+	ctx := WithActor(context.Background(), "editor")
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a file audit store writing a snapshot every few entries", t, func() {
+		dir := t.TempDir()
+		audit, err := NewFileAudit(dir, WithSnapshotThreshold(2))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = audit.Close() })
+
+		Convey("The entries should be kept across reopening", func() {
+			author := data.Author{ID: "ada", Name: "Ada", Version: InitialVersion}
+			updated := data.Author{ID: "ada", Name: "Ada Lovelace", Version: 2}
+			created := newAuditEntry(ctx, AuditCreated, "ada", nil, &author, now)
+			changed := newAuditEntry(ctx, AuditUpdated, "ada", &author, &updated, now.Add(time.Minute))
+			So(audit.Record(ctx, created), ShouldBeNil)
+			So(audit.Record(ctx, newAuditEntry(ctx, AuditCreated, "grace", nil, &author, now)), ShouldBeNil)
+			So(audit.Record(ctx, changed), ShouldBeNil)
+
+			audit = reopenAudit(audit, WithSnapshotThreshold(2))
+			entries, err := audit.History(ctx, "ada")
+			So(err, ShouldBeNil)
+			So(entries, ShouldResemble, []AuditEntry{created, changed})
+			entries, _ = audit.History(ctx, "grace")
+			So(entries, ShouldHaveLength, 1)
+		})
+
+		Convey("A closed audit store should not record entries", func() {
+			So(audit.Close(), ShouldBeNil)
+			err := audit.Record(ctx, newAuditEntry(ctx, AuditCreated, "ada", nil, nil, now))
+			So(err, ShouldWrap, ErrFailedPrecondition)
+			entries, _ := audit.History(ctx, "ada")
+			So(entries, ShouldBeEmpty)
+		})
+	})
+}
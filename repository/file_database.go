@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+const (
+	// fileDatabaseLog is the file of the changes made since the snapshot.
+	fileDatabaseLog = "authors.log"
+	// fileDatabaseSnapshot is the file of the state of the database.
+	fileDatabaseSnapshot = "authors.snapshot"
+)
+
+// FileDatabase is a database persisted to the files of a directory, to run the
+// service without any external service. The authors are held in memory, and
+// each change is appended to a log before it is applied. On opening, the log is
+// replayed over the last snapshot, which is rewritten once the log holds enough
+// changes. A FileDatabase must be closed once done.
+type FileDatabase struct {
+	*inMemoryDatabase
+	log *fileLog
+}
+
+// fileRecord is a change appended to the log, numbered by its sequence. It
+// either stores revisions or permanently deletes an author.
+type fileRecord struct {
+	Sequence  int64      `json:"seq"`
+	Revisions []Revision `json:"revisions,omitempty"`
+	Deleted   string     `json:"deleted,omitempty"`
+}
+
+// fileSnapshot is the state of the database after the change of a sequence.
+type fileSnapshot struct {
+	Sequence  int64                 `json:"seq"`
+	Revisions map[string][]Revision `json:"revisions"`
+}
+
+// NewFileDatabase opens the database persisted in the directory, creating the
+// directory when it does not exist. The log is locked until the database is
+// closed, and opening a directory already opened by another process fails with
+// ErrFailedPrecondition.
+func NewFileDatabase(dir string, opts ...FileOption) (*FileDatabase, error) {
+	db := &FileDatabase{
+		inMemoryDatabase: &inMemoryDatabase{
+			storage:   make(map[string]data.Author),
+			revisions: make(map[string][]Revision),
+			now:       time.Now,
+		},
+	}
+	log, err := openFileLog(dir, fileDatabaseLog, fileDatabaseSnapshot, db, opts...)
+	if err != nil {
+		return nil, err
+	}
+	db.log = log
+	db.inMemoryDatabase.journal = db
+	return db, nil
+}
+
+// Close closes the log of the database, which cannot be changed afterwards.
+func (db *FileDatabase) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.log.close()
+}
+
+// restore restores the authors of a snapshot.
+func (db *FileDatabase) restore(raw []byte) error {
+	var snapshot fileSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return err
+	}
+	for _, revisions := range snapshot.Revisions {
+		for _, revision := range revisions {
+			db.apply(revision)
+		}
+	}
+	return nil
+}
+
+// replay applies a change of the log.
+func (db *FileDatabase) replay(raw []byte) error {
+	var record fileRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+	if record.Deleted != "" {
+		delete(db.storage, record.Deleted)
+		delete(db.revisions, record.Deleted)
+		return nil
+	}
+	for _, revision := range record.Revisions {
+		db.apply(revision)
+	}
+	return nil
+}
+
+// snapshot returns the revisions of the authors. The database holds its write lock.
+func (db *FileDatabase) snapshot(sequence int64) any {
+	return fileSnapshot{Sequence: sequence, Revisions: db.revisions}
+}
+
+// stored appends the stored revisions to the log.
+func (db *FileDatabase) stored(revisions []Revision) error {
+	return db.log.append(fileRecord{Sequence: db.log.next(), Revisions: revisions})
+}
+
+// deleted appends the permanent deletion of an author to the log.
+func (db *FileDatabase) deleted(id string) error {
+	return db.log.append(fileRecord{Sequence: db.log.next(), Deleted: id})
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+// reopen closes the file database and opens its directory again.
+func reopen(db *FileDatabase, opts ...FileOption) *FileDatabase {
+	So(db.Close(), ShouldBeNil)
+	reopened, err := NewFileDatabase(filepath.Dir(db.log.path), opts...)
+	So(err, ShouldBeNil)
+	return reopened
+}
+
+func TestFileDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a file database in an empty directory", t, func() {
+		dir := filepath.Join(t.TempDir(), "data")
+		db, err := NewFileDatabase(dir)
+		So(err, ShouldBeNil)
+		Reset(func() { _ = db.Close() })
+
+		Convey("The authors should be kept across reopening", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada", BirthDate: &data.PartialDate{Year: 1815}})
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "grace", Name: "Grace"})
+			_, _ = db.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada Lovelace", Occupations: []string{"mathematician"}}, 0)
+			_, _ = db.SoftDeleteAuthor(ctx, "grace", "editor", time.Now(), 0)
+
+			db = reopen(db)
+			author, err := db.GetAuthor(ctx, "ada")
+			So(err, ShouldBeNil)
+			So(author.Name, ShouldEqual, "Ada Lovelace")
+			So(author.Occupations, ShouldResemble, []string{"mathematician"})
+			So(author.Version, ShouldEqual, 2)
+			revision, err := db.GetRevision(ctx, "ada", 1)
+			So(err, ShouldBeNil)
+			So(revision.Author.BirthDate, ShouldResemble, &data.PartialDate{Year: 1815})
+			deleted, err := db.GetDeletedAuthor(ctx, "grace")
+			So(err, ShouldBeNil)
+			So(deleted.DeletedBy, ShouldEqual, "editor")
+
+			Convey("Changes made after reopening should be kept as well", func() {
+				_, err := db.RestoreAuthor(ctx, "grace", 0)
+				So(err, ShouldBeNil)
+				db = reopen(db)
+				So(db.ListAll(ctx), ShouldHaveLength, 2)
+				revisions, _ := db.ListRevisions(ctx, "grace")
+				So(revisions, ShouldHaveLength, 3)
+			})
+		})
+
+		Convey("A purged author should stay purged after reopening", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			So(db.DeleteAuthor(ctx, "ada", 0), ShouldBeNil)
+			db = reopen(db)
+			_, err := db.GetAuthor(ctx, "ada")
+			So(err, ShouldWrap, ErrNotFound)
+			_, err = db.ListRevisions(ctx, "ada")
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("A failed change should not be logged", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			_, err := db.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada"}, 5)
+			So(err, ShouldWrap, ErrConflict)
+			db = reopen(db)
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Version, ShouldEqual, InitialVersion)
+		})
+
+		Convey("An atomic batch should be logged as a whole", func() {
+			results, err := db.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "ada", Name: "Ada"}},
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[1].Err, ShouldBeNil)
+			raw, _ := os.ReadFile(filepath.Join(dir, fileDatabaseLog))
+			So(strings.Count(string(raw), "\n"), ShouldEqual, 1)
+			db = reopen(db)
+			So(db.ListAll(ctx), ShouldHaveLength, 2)
+		})
+
+		Convey("A closed database should not be changed", func() {
+			So(db.Close(), ShouldBeNil)
+			_, err := db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			So(err, ShouldWrap, ErrFailedPrecondition)
+			_, err = db.GetAuthor(ctx, "ada")
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("A directory opened by another database should not be opened", func() {
+			_, err := NewFileDatabase(dir)
+			So(err, ShouldWrap, ErrFailedPrecondition)
+			db = reopen(db)
+			So(db.Ping(ctx), ShouldBeNil)
+		})
+
+		Convey("A change partly written to the log should be dropped", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			So(db.Close(), ShouldBeNil)
+			log, _ := os.OpenFile(filepath.Join(dir, fileDatabaseLog), os.O_APPEND|os.O_WRONLY, 0o644)
+			_, _ = log.WriteString(`{"seq":2,"revisions":[{"vers`)
+			_ = log.Close()
+
+			db, err = NewFileDatabase(dir)
+			So(err, ShouldBeNil)
+			So(db.ListAll(ctx), ShouldHaveLength, 1)
+			_, err = db.AddAuthor(ctx, data.Author{ID: "grace", Name: "Grace"})
+			So(err, ShouldBeNil)
+			db = reopen(db)
+			So(db.ListAll(ctx), ShouldHaveLength, 2)
+		})
+
+		Convey("A corrupted change in the log should fail the opening", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			So(db.Close(), ShouldBeNil)
+			raw, _ := os.ReadFile(filepath.Join(dir, fileDatabaseLog))
+			_ = os.WriteFile(filepath.Join(dir, fileDatabaseLog), append([]byte("garbage\n"), raw...), 0o644)
+			_, err := NewFileDatabase(dir)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a file database writing a snapshot every few changes", t, func() {
+		dir := t.TempDir()
+		db, err := NewFileDatabase(dir, WithSnapshotThreshold(3))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = db.Close() })
+
+		Convey("The log should be emptied into the snapshot", func() {
+			for _, id := range []string{"a", "b", "c", "d"} {
+				_, err := db.AddAuthor(ctx, data.Author{ID: id, Name: strings.ToUpper(id)})
+				So(err, ShouldBeNil)
+			}
+			_, _ = db.UpdateAuthor(ctx, data.Author{ID: "a", Name: "Ada"}, 0)
+			So(db.DeleteAuthor(ctx, "b", 0), ShouldBeNil)
+
+			_, err := os.Stat(filepath.Join(dir, fileDatabaseSnapshot))
+			So(err, ShouldBeNil)
+			raw, _ := os.ReadFile(filepath.Join(dir, fileDatabaseLog))
+			So(strings.Count(string(raw), "\n"), ShouldEqual, 3)
+
+			db = reopen(db, WithSnapshotThreshold(3))
+			So(db.ListAll(ctx), ShouldHaveLength, 3)
+			author, _ := db.GetAuthor(ctx, "a")
+			So(author.Name, ShouldEqual, "Ada")
+			revisions, _ := db.ListRevisions(ctx, "a")
+			So(revisions, ShouldHaveLength, 2)
+		})
+
+		Convey("Changes left in the log before the snapshot should be skipped", func() {
+			for _, id := range []string{"a", "b", "c"} {
+				_, _ = db.AddAuthor(ctx, data.Author{ID: id, Name: id})
+			}
+			raw, _ := os.ReadFile(filepath.Join(dir, fileDatabaseLog))
+			_, _ = db.UpdateAuthor(ctx, data.Author{ID: "a", Name: "Ada"}, 0)
+			So(db.Close(), ShouldBeNil)
+			logged, _ := os.ReadFile(filepath.Join(dir, fileDatabaseLog))
+			_ = os.WriteFile(filepath.Join(dir, fileDatabaseLog), append(raw, logged...), 0o644)
+
+			db, err = NewFileDatabase(dir, WithSnapshotThreshold(3))
+			So(err, ShouldBeNil)
+			revisions, _ := db.ListRevisions(ctx, "a")
+			So(revisions, ShouldHaveLength, 2)
+			So(db.ListAll(ctx), ShouldHaveLength, 3)
+		})
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package repository
+
+import "os"
+
+// lockFile does not lock the file on the systems without flock, where a
+// directory must not be opened by several processes.
+func lockFile(_ *os.File) error {
+	return nil
+}
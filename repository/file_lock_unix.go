@@ -0,0 +1,19 @@
+//go:build unix
+
+package repository
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock on the file, held until it is closed, and
+// fails with ErrFailedPrecondition when another process holds it.
+func lockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return failedPreconditionError("%s is used by another process", file.Name())
+	}
+	return err
+}
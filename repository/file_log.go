@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultSnapshotThreshold is the number of changes a file store logs before
+// it writes a snapshot and empties its log.
+const DefaultSnapshotThreshold = 1000
+
+// FileOption configures a file store.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	threshold int
+}
+
+// WithSnapshotThreshold sets the number of logged changes after which a
+// snapshot is written.
+func WithSnapshotThreshold(threshold int) FileOption {
+	return func(opts *fileOptions) {
+		opts.threshold = threshold
+	}
+}
+
+// fileState is the state of a file store, restored from its snapshot and the
+// changes of its log.
+type fileState interface {
+	// restore restores the state of a snapshot.
+	restore(snapshot []byte) error
+	// replay applies a change of the log.
+	replay(change []byte) error
+	// snapshot returns the state to write as the snapshot of the sequence.
+	snapshot(sequence int64) any
+}
+
+// fileSequence is the sequence numbering a change of a log or a snapshot, the
+// snapshot holding the changes up to its sequence.
+type fileSequence struct {
+	Sequence int64 `json:"seq"`
+}
+
+// fileLog persists the state of a file store to a log of its changes, one JSON
+// object per line, and a snapshot of the state, which is rewritten once the log
+// holds enough changes. The log is locked while it is open. Its store
+// serializes the calls.
+type fileLog struct {
+	state     fileState
+	path      string
+	snapshots string
+	file      *os.File
+	size      int64
+	logged    int
+	sequence  int64
+	threshold int
+}
+
+// openFileLog opens the log and the snapshot files of the directory, creating
+// the directory when it does not exist, and restores the state from them.
+func openFileLog(dir string, log string, snapshot string, state fileState, opts ...FileOption) (*fileLog, error) {
+	options := fileOptions{threshold: DefaultSnapshotThreshold}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	l := &fileLog{
+		state:     state,
+		path:      filepath.Join(dir, log),
+		snapshots: filepath.Join(dir, snapshot),
+		threshold: options.threshold,
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := l.loadSnapshot(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := l.replay(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	l.file = file
+	return l, nil
+}
+
+// close closes the log, which cannot be appended to afterwards.
+func (l *fileLog) close() error {
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// loadSnapshot restores the state of the snapshot, if any.
+func (l *fileLog) loadSnapshot() error {
+	raw, err := os.ReadFile(l.snapshots)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snapshot fileSequence
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", l.snapshots, err)
+	}
+	if err := l.state.restore(raw); err != nil {
+		return fmt.Errorf("invalid snapshot %s: %w", l.snapshots, err)
+	}
+	l.sequence = snapshot.Sequence
+	return nil
+}
+
+// replay applies the changes of the log made after the snapshot. A last
+// change that was only partly written, such as when the service crashed, is
+// dropped from the log.
+func (l *fileLog) replay(file *os.File) error {
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				if err := file.Truncate(offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var change fileSequence
+		if err := json.Unmarshal(line, &change); err != nil {
+			return fmt.Errorf("invalid change at offset %d of %s: %w", offset, l.path, err)
+		}
+		offset += int64(len(line))
+		l.logged++
+		if change.Sequence <= l.sequence {
+			continue
+		}
+		if err := l.state.replay(line); err != nil {
+			return fmt.Errorf("invalid change at offset %d of %s: %w", offset, l.path, err)
+		}
+		l.sequence = change.Sequence
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	l.size = offset
+	return nil
+}
+
+// next returns the sequence of the next change.
+func (l *fileLog) next() int64 {
+	return l.sequence + 1
+}
+
+// append writes a change numbered by next at the end of the log, first
+// writing a snapshot when the log holds enough changes.
+func (l *fileLog) append(change any) error {
+	if l.file == nil {
+		return failedPreconditionError("%s is closed", l.path)
+	}
+	if l.threshold > 0 && l.logged >= l.threshold {
+		if err := l.snapshot(); err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	_, err = l.file.Write(line)
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if err != nil {
+		// Drop what may have been written, so that a failed change is not
+		// replayed and the next change is not appended to a partial one.
+		_ = l.file.Truncate(l.size)
+		_, _ = l.file.Seek(l.size, io.SeekStart)
+		return err
+	}
+	l.size += int64(len(line))
+	l.logged++
+	l.sequence++
+	return nil
+}
+
+// snapshot writes the state and empties the log. The snapshot replaces the
+// previous one only once it is fully written, and the changes left in the log
+// if emptying it fails are skipped when replayed.
+func (l *fileLog) snapshot() error {
+	raw, err := json.Marshal(l.state.snapshot(l.sequence))
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(l.snapshots + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(raw); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.snapshots+".tmp", l.snapshots); err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	l.size = 0
+	l.logged = 0
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import "encoding/json"
+
+const (
+	// fileOutboxLog is the file of the changes of the outbox made since the snapshot.
+	fileOutboxLog = "outbox.log"
+	// fileOutboxSnapshot is the file of the pending tasks of the outbox.
+	fileOutboxSnapshot = "outbox.snapshot"
+)
+
+// FileOutbox is an outbox store persisted to the files of a directory, kept
+// next to a FileDatabase. The tasks are held in memory and each change is
+// appended to a log before it is applied, as with the FileDatabase. A
+// FileOutbox must be closed once done.
+type FileOutbox struct {
+	*inMemoryOutbox
+	log *fileLog
+}
+
+// fileOutboxRecord is a change of the outbox appended to the log. It either
+// saves a task or removes a completed one.
+type fileOutboxRecord struct {
+	Sequence  int64       `json:"seq"`
+	Task      *OutboxTask `json:"task,omitempty"`
+	Completed string      `json:"completed,omitempty"`
+}
+
+// fileOutboxState is the state of the outbox after the change of a sequence.
+type fileOutboxState struct {
+	Sequence int64                 `json:"seq"`
+	Tasks    map[string]OutboxTask `json:"tasks"`
+}
+
+// NewFileOutbox opens the outbox persisted in the directory, creating the
+// directory when it does not exist. Its log is locked until it is closed.
+func NewFileOutbox(dir string, opts ...FileOption) (*FileOutbox, error) {
+	outbox := &FileOutbox{
+		inMemoryOutbox: &inMemoryOutbox{
+			tasks: make(map[string]OutboxTask),
+		},
+	}
+	log, err := openFileLog(dir, fileOutboxLog, fileOutboxSnapshot, outbox, opts...)
+	if err != nil {
+		return nil, err
+	}
+	outbox.log = log
+	outbox.inMemoryOutbox.journal = outbox
+	return outbox, nil
+}
+
+// Close closes the log of the outbox, which cannot be changed afterwards.
+func (o *FileOutbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.log.close()
+}
+
+// restore restores the tasks of a snapshot.
+func (o *FileOutbox) restore(raw []byte) error {
+	var snapshot fileOutboxState
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return err
+	}
+	for id, task := range snapshot.Tasks {
+		o.tasks[id] = task
+	}
+	return nil
+}
+
+// replay applies a change of the log.
+func (o *FileOutbox) replay(raw []byte) error {
+	var record fileOutboxRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+	if record.Task != nil {
+		o.tasks[record.Task.ID] = *record.Task
+	} else {
+		delete(o.tasks, record.Completed)
+	}
+	return nil
+}
+
+// snapshot returns the pending tasks. The outbox holds its lock.
+func (o *FileOutbox) snapshot(sequence int64) any {
+	return fileOutboxState{Sequence: sequence, Tasks: o.tasks}
+}
+
+// saved appends a saved task to the log.
+func (o *FileOutbox) saved(task OutboxTask) error {
+	return o.log.append(fileOutboxRecord{Sequence: o.log.next(), Task: &task})
+}
+
+// completed appends the removal of a completed task to the log.
+func (o *FileOutbox) completed(id string) error {
+	return o.log.append(fileOutboxRecord{Sequence: o.log.next(), Completed: id})
+}
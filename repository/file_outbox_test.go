@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// reopenOutbox closes the file outbox and opens its directory again.
+func reopenOutbox(outbox *FileOutbox, opts ...FileOption) *FileOutbox {
+	So(outbox.Close(), ShouldBeNil)
+	reopened, err := NewFileOutbox(filepath.Dir(outbox.log.path), opts...)
+	So(err, ShouldBeNil)
+	return reopened
+}
+
+func TestFileOutbox(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a file outbox writing a snapshot every few changes", t, func() {
+		dir := t.TempDir()
+		outbox, err := NewFileOutbox(dir, WithSnapshotThreshold(3))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = outbox.Close() })
+
+		Convey("The pending tasks should be kept across reopening", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "request", now)), ShouldBeNil)
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("grace", "", now.Add(time.Second))), ShouldBeNil)
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("alan", "", now.Add(2*time.Second))), ShouldBeNil)
+			task, ok, err := outbox.Claim(ctx, now, time.Minute)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(outbox.Retry(ctx, task.ID, now.Add(time.Hour), "unavailable"), ShouldBeNil)
+			So(outbox.Complete(ctx, TaskDeleteAuthorQuotes+":grace"), ShouldBeNil)
+
+			_, err = os.Stat(filepath.Join(dir, fileOutboxSnapshot))
+			So(err, ShouldBeNil)
+			outbox = reopenOutbox(outbox, WithSnapshotThreshold(3))
+			tasks, err := outbox.List(ctx)
+			So(err, ShouldBeNil)
+			So(tasks, ShouldHaveLength, 2)
+			So(tasks[0].AuthorID, ShouldEqual, "ada")
+			So(tasks[0].RequestID, ShouldEqual, "request")
+			So(tasks[0].Attempts, ShouldEqual, 1)
+			So(tasks[0].LastError, ShouldEqual, "unavailable")
+			So(tasks[0].NextAttemptAt.Equal(now.Add(time.Hour)), ShouldBeTrue)
+			So(tasks[1].AuthorID, ShouldEqual, "alan")
+		})
+
+		Convey("A claimed task should stay hidden for its lease after reopening", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "", now)), ShouldBeNil)
+			_, ok, _ := outbox.Claim(ctx, now, time.Minute)
+			So(ok, ShouldBeTrue)
+			outbox = reopenOutbox(outbox)
+			_, ok, _ = outbox.Claim(ctx, now, time.Minute)
+			So(ok, ShouldBeFalse)
+			_, ok, _ = outbox.Claim(ctx, now.Add(time.Minute), time.Minute)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("A directory opened by another outbox should not be opened", func() {
+			_, err := NewFileOutbox(dir)
+			So(err, ShouldWrap, ErrFailedPrecondition)
+		})
+
+		Convey("A closed outbox should not be changed", func() {
+			So(outbox.Close(), ShouldBeNil)
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "", now)), ShouldWrap, ErrFailedPrecondition)
+			tasks, _ := outbox.List(ctx)
+			So(tasks, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,30 @@
+package repository
+
+import "context"
+
+const (
+	// DependencyDatabase names the database storing the authors.
+	DependencyDatabase = "database"
+	// DependencyQuoteService names the QuoteService deleting the quotes of purged authors.
+	DependencyQuoteService = "quoteService"
+)
+
+// Dependency is a service the repository relies on, whose health can be checked.
+type Dependency struct {
+	// Name identifies the dependency in the health reports.
+	Name string
+	// Critical is true when the repository cannot serve requests without the dependency.
+	Critical bool
+	// Ping checks that the dependency is available.
+	Ping func(ctx context.Context) error
+}
+
+// Dependencies returns the dependencies of the repository. QuoteService is not
+// critical: the quotes of purged authors are deleted through the outbox, which
+// retries until QuoteService is available again.
+func (s *repository) Dependencies() []Dependency {
+	return []Dependency{
+		{Name: DependencyDatabase, Critical: true, Ping: s.db.Ping},
+		{Name: DependencyQuoteService, Critical: false, Ping: s.clientRepository.Ping},
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDependencies(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a repository", t, func() {
+		clientRepo := NewFakeClientRepository()
+		repo := New(NewInMemoryDatabase(), clientRepo)
+		dependencies := repo.Dependencies()
+
+		Convey("The database should be a critical dependency", func() {
+			So(dependencies[0].Name, ShouldEqual, DependencyDatabase)
+			So(dependencies[0].Critical, ShouldBeTrue)
+			So(dependencies[0].Ping(ctx), ShouldBeNil)
+		})
+
+		Convey("QuoteService should not be a critical dependency", func() {
+			So(dependencies[1].Name, ShouldEqual, DependencyQuoteService)
+			So(dependencies[1].Critical, ShouldBeFalse)
+			So(dependencies[1].Ping(ctx), ShouldBeNil)
+
+			failure := errors.New("connection refused")
+			clientRepo.SetPingError(failure)
+			So(dependencies[1].Ping(ctx), ShouldEqual, failure)
+		})
+	})
+}
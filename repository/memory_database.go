@@ -1,62 +1,402 @@
 package repository
 
 import (
-	"fmt"
+	"context"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"sort"
+	"sync"
+	"time"
 )
 
-// inMemoryDatabase is a simple in-memory database.
+// inMemoryDatabase is a simple in-memory database, safe for concurrent use.
+// The authors are copied in and out so that the stored ones are never shared
+// with the callers.
 type inMemoryDatabase struct {
-	storage map[string]data.Author
+	mu        sync.RWMutex
+	storage   map[string]data.Author
+	revisions map[string][]Revision
+	now       func() time.Time
+	// journal persists the changes before they are applied, when set.
+	journal journal
+}
+
+// journal persists the changes of an in-memory database. A change failing to
+// be persisted is not applied.
+type journal interface {
+	// stored persists the revisions of the stored authors as a whole.
+	stored(revisions []Revision) error
+	// deleted persists the permanent deletion of an author.
+	deleted(id string) error
 }
 
 // NewInMemoryDatabase creates a new InMemoryDatabase.
 func NewInMemoryDatabase() Database {
 	return &inMemoryDatabase{
-		storage: make(map[string]data.Author),
+		storage:   make(map[string]data.Author),
+		revisions: make(map[string][]Revision),
+		now:       time.Now,
 	}
 }
 
 // AddAuthor adds a new author to the database.
-func (db *inMemoryDatabase) AddAuthor(author data.Author) (string, error) {
+func (db *inMemoryDatabase) AddAuthor(_ context.Context, author data.Author) (string, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
 	if _, ok := db.storage[author.ID]; ok {
-		return "", fmt.Errorf("author %q already exist in database", author.ID)
+		return "", alreadyExistsError(author.ID)
+	}
+	author.Version = InitialVersion
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	if err := db.store(author); err != nil {
+		return "", err
 	}
-	db.storage[author.ID] = author
 	return author.ID, nil
 }
 
-// ListAll returns all authors in the database.
-func (db *inMemoryDatabase) ListAll() []data.Author {
-	var authors []data.Author
-	for _, v := range db.storage {
+// ListAll returns all authors in the database that are not deleted, sorted by
+// name and then by ID.
+func (db *inMemoryDatabase) ListAll(_ context.Context) []data.Author {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return copyAuthors(sortedAuthors(db.storage))
+}
+
+// ListPage returns a page of authors sorted by name and then by ID.
+func (db *inMemoryDatabase) ListPage(_ context.Context, page PageRequest) (AuthorPage, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	cursor, err := page.cursor()
+	if err != nil {
+		return AuthorPage{}, err
+	}
+	size := page.pageSize()
+	authors := make([]data.Author, 0, size+1)
+	for _, v := range sortedAuthors(db.storage) {
+		if !cursor.after(v) {
+			continue
+		}
 		authors = append(authors, v)
+		if len(authors) > size {
+			break
+		}
 	}
-	return authors
+	return newAuthorPage(copyAuthors(authors), size), nil
 }
 
 // UpdateAuthor updates an existing author in the database.
-func (db *inMemoryDatabase) UpdateAuthor(author data.Author) (data.Author, error) {
-	if _, ok := db.storage[author.ID]; !ok {
-		return data.Author{}, fmt.Errorf("author %q do not exist in database", author.ID)
+func (db *inMemoryDatabase) UpdateAuthor(_ context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	current, err := db.activeAuthor(author.ID, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	author.Version = current.Version + 1
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	if err := db.store(author); err != nil {
+		return data.Author{}, err
+	}
+	return copyAuthor(db.storage[author.ID]), nil
+}
+
+// PatchAuthor changes only the patched fields of an existing author in the database.
+func (db *inMemoryDatabase) PatchAuthor(_ context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	author, err := db.activeAuthor(id, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	author = patch.Apply(author)
+	author.Version++
+	if err := db.store(author); err != nil {
+		return data.Author{}, err
+	}
+	return copyAuthor(db.storage[id]), nil
+}
+
+// SoftDeleteAuthor marks an existing author as deleted.
+func (db *inMemoryDatabase) SoftDeleteAuthor(_ context.Context, id string, deletedBy string, deletedAt time.Time, expectedVersion int64) (data.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	author, err := db.activeAuthor(id, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	author.DeletedAt = &deletedAt
+	author.DeletedBy = deletedBy
+	author.Version++
+	if err := db.store(author); err != nil {
+		return data.Author{}, err
+	}
+	return copyAuthor(db.storage[id]), nil
+}
+
+// RestoreAuthor clears the deletion of a deleted author.
+func (db *inMemoryDatabase) RestoreAuthor(_ context.Context, id string, expectedVersion int64) (data.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	author, ok := db.storage[id]
+	if !ok || !author.IsDeleted() {
+		return data.Author{}, notFoundError(id)
 	}
-	db.storage[author.ID] = author
-	return db.storage[author.ID], nil
+	if err := CheckVersion(id, author.Version, expectedVersion); err != nil {
+		return data.Author{}, err
+	}
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	author.Version++
+	if err := db.store(author); err != nil {
+		return data.Author{}, err
+	}
+	return copyAuthor(db.storage[id]), nil
 }
 
-// DeleteAuthor deletes an existing author from the database.
-func (db *inMemoryDatabase) DeleteAuthor(id string) error {
-	if _, ok := db.storage[id]; !ok {
-		return fmt.Errorf("author %q do not exist in database", id)
+// DeleteAuthor permanently deletes an author and its revisions from the database.
+func (db *inMemoryDatabase) DeleteAuthor(_ context.Context, id string, expectedVersion int64) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	author, ok := db.storage[id]
+	if !ok {
+		return notFoundError(id)
+	}
+	if err := CheckVersion(id, author.Version, expectedVersion); err != nil {
+		return err
+	}
+	if db.journal != nil {
+		if err := db.journal.deleted(id); err != nil {
+			return err
+		}
 	}
 	delete(db.storage, id)
+	delete(db.revisions, id)
+	return nil
+}
+
+// store saves the changed authors along with their revisions, once persisted
+// by the journal. The caller holds the write lock.
+func (db *inMemoryDatabase) store(authors ...data.Author) error {
+	if len(authors) == 0 {
+		return nil
+	}
+	now := db.now().UTC()
+	revisions := make([]Revision, len(authors))
+	for index, author := range authors {
+		revisions[index] = newRevision(copyAuthor(author), now)
+	}
+	if db.journal != nil {
+		if err := db.journal.stored(revisions); err != nil {
+			return err
+		}
+	}
+	for _, revision := range revisions {
+		db.apply(revision)
+	}
 	return nil
 }
 
-// GetAuthor returns an author from the database.
-func (db *inMemoryDatabase) GetAuthor(id string) (data.Author, error) {
-	if _, ok := db.storage[id]; !ok {
-		return data.Author{}, fmt.Errorf("author %q do not exist in database", id)
+// apply saves a revision and makes its author the stored one.
+func (db *inMemoryDatabase) apply(revision Revision) {
+	db.storage[revision.Author.ID] = copyAuthor(revision.Author)
+	db.revisions[revision.Author.ID] = append(db.revisions[revision.Author.ID], revision)
+}
+
+// BulkWrite applies the operations of a batch. An atomic batch is checked as a
+// whole before any author is stored, and the applied operations are persisted
+// together.
+func (db *inMemoryDatabase) BulkWrite(_ context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	results := planBulk(ops, db.storage, mode)
+	var authors []data.Author
+	for _, result := range results {
+		if result.Err == nil {
+			authors = append(authors, *result.Author)
+		}
+	}
+	if err := db.store(authors...); err != nil {
+		return nil, err
+	}
+	for index, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		after := copyAuthor(*result.Author)
+		results[index].Author = &after
+		if result.Before != nil {
+			before := copyAuthor(*result.Before)
+			results[index].Before = &before
+		}
+	}
+	return results, nil
+}
+
+// ListRevisions returns the revisions of an author, the oldest first.
+func (db *inMemoryDatabase) ListRevisions(_ context.Context, id string) ([]Revision, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	revisions, ok := db.revisions[id]
+	if !ok {
+		return nil, notFoundError(id)
+	}
+	copied := make([]Revision, len(revisions))
+	for index, revision := range revisions {
+		copied[index] = copyRevision(revision)
+	}
+	return copied, nil
+}
+
+// GetRevision returns the revision of an author at the version.
+func (db *inMemoryDatabase) GetRevision(_ context.Context, id string, version int64) (Revision, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for _, revision := range db.revisions[id] {
+		if revision.Version == version {
+			return copyRevision(revision), nil
+		}
+	}
+	return Revision{}, revisionNotFoundError(id, version)
+}
+
+// GetRevisionAt returns the last revision of an author made at or before the time.
+func (db *inMemoryDatabase) GetRevisionAt(_ context.Context, id string, at time.Time) (Revision, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	revisions := db.revisions[id]
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if !revisions[i].Time.After(at) {
+			return copyRevision(revisions[i]), nil
+		}
+	}
+	return Revision{}, revisionAtNotFoundError(id, at)
+}
+
+// GetAuthor returns an author that is not deleted from the database.
+func (db *inMemoryDatabase) GetAuthor(_ context.Context, id string) (data.Author, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	author, err := db.activeAuthor(id, 0)
+	return copyAuthor(author), err
+}
+
+// GetDeletedAuthor returns a deleted author from the database.
+func (db *inMemoryDatabase) GetDeletedAuthor(_ context.Context, id string) (data.Author, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	author, ok := db.storage[id]
+	if !ok || !author.IsDeleted() {
+		return data.Author{}, notFoundError(id)
+	}
+	return copyAuthor(author), nil
+}
+
+// ListDeleted returns the authors deleted before the time, or all deleted
+// authors when the time is zero, from the oldest deletion to the newest.
+func (db *inMemoryDatabase) ListDeleted(_ context.Context, deletedBefore time.Time) ([]data.Author, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	authors := make([]data.Author, 0)
+	for _, v := range db.storage {
+		if v.IsDeleted() && (deletedBefore.IsZero() || !v.DeletedAt.After(deletedBefore)) {
+			authors = append(authors, v)
+		}
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if !authors[i].DeletedAt.Equal(*authors[j].DeletedAt) {
+			return authors[i].DeletedAt.Before(*authors[j].DeletedAt)
+		}
+		return authors[i].ID < authors[j].ID
+	})
+	return copyAuthors(authors), nil
+}
+
+// SearchAuthors returns the authors whose name starts with or contains the query,
+// ignoring case and diacritics.
+func (db *inMemoryDatabase) SearchAuthors(_ context.Context, query string, limit int) ([]data.Author, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	query, err := searchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return copyAuthors(rankAuthors(sortedAuthors(db.storage), query, searchLimit(limit))), nil
+}
+
+// activeAuthor returns the author if it exists, is not deleted and is at the
+// expected version. The caller holds the lock.
+func (db *inMemoryDatabase) activeAuthor(id string, expectedVersion int64) (data.Author, error) {
+	author, ok := db.storage[id]
+	if !ok || author.IsDeleted() {
+		return data.Author{}, notFoundError(id)
+	}
+	if err := CheckVersion(id, author.Version, expectedVersion); err != nil {
+		return data.Author{}, err
+	}
+	return author, nil
+}
+
+// sortedAuthors returns the authors in storage that are not deleted, sorted by
+// name and then by ID.
+func sortedAuthors(storage map[string]data.Author) []data.Author {
+	authors := make([]data.Author, 0, len(storage))
+	for _, v := range storage {
+		if !v.IsDeleted() {
+			authors = append(authors, v)
+		}
 	}
-	return db.storage[id], nil
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Name != authors[j].Name {
+			return authors[i].Name < authors[j].Name
+		}
+		return authors[i].ID < authors[j].ID
+	})
+	return authors
+}
+
+// copyAuthor returns a copy of the author sharing none of its lists and dates.
+func copyAuthor(author data.Author) data.Author {
+	author.BirthDate = copyDate(author.BirthDate)
+	author.DeathDate = copyDate(author.DeathDate)
+	author.Occupations = copyStrings(author.Occupations)
+	author.AlternateNames = copyStrings(author.AlternateNames)
+	if author.DeletedAt != nil {
+		deletedAt := *author.DeletedAt
+		author.DeletedAt = &deletedAt
+	}
+	return author
+}
+
+func copyAuthors(authors []data.Author) []data.Author {
+	copied := make([]data.Author, len(authors))
+	for index, author := range authors {
+		copied[index] = copyAuthor(author)
+	}
+	return copied
+}
+
+func copyRevision(revision Revision) Revision {
+	revision.Author = copyAuthor(revision.Author)
+	return revision
+}
+
+func copyDate(date *data.PartialDate) *data.PartialDate {
+	if date == nil {
+		return nil
+	}
+	copied := *date
+	return &copied
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+// Ping always succeeds, the in-memory database being always available.
+func (db *inMemoryDatabase) Ping(_ context.Context) error {
+	return nil
 }
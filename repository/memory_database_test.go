@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+// stressWorkers is the number of goroutines of the stress tests, which are
+// meant to be run with the race detector.
+const stressWorkers = 16
+
+// runConcurrently runs the work of each worker in its own goroutine and
+// returns the errors they reported.
+func runConcurrently(workers int, work func(worker int) error) []error {
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			if err := work(worker); err != nil {
+				errs <- fmt.Errorf("worker %d: %w", worker, err)
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+	var reported []error
+	for err := range errs {
+		reported = append(reported, err)
+	}
+	return reported
+}
+
+func TestInMemoryListAll(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory database with authors added out of order", t, func() {
+		db := NewInMemoryDatabase()
+		for _, author := range []data.Author{
+			{ID: "3", Name: "Plato"},
+			{ID: "2", Name: "Aristotle"},
+			{ID: "1", Name: "Plato"},
+			{ID: "4", Name: "Deleted"},
+		} {
+			_, _ = db.AddAuthor(ctx, author)
+		}
+		_, _ = db.SoftDeleteAuthor(ctx, "4", "editor", time.Now(), 0)
+
+		Convey("ListAll should return the authors that are not deleted sorted by name and then by ID", func() {
+			authors := db.ListAll(ctx)
+			ids := make([]string, len(authors))
+			for index, author := range authors {
+				ids[index] = author.ID
+			}
+			So(ids, ShouldResemble, []string{"2", "1", "3"})
+		})
+
+		Convey("Changing a returned author should not change the stored one", func() {
+			_, _ = db.UpdateAuthor(ctx, data.Author{ID: "2", Name: "Aristotle", Occupations: []string{"philosopher"}}, 0)
+			author, _ := db.GetAuthor(ctx, "2")
+			author.Occupations[0] = "changed"
+			db.ListAll(ctx)[0].Occupations[0] = "changed"
+			stored, _ := db.GetAuthor(ctx, "2")
+			So(stored.Occupations, ShouldResemble, []string{"philosopher"})
+			revision, _ := db.GetRevision(ctx, "2", 2)
+			So(revision.Author.Occupations, ShouldResemble, []string{"philosopher"})
+		})
+	})
+}
+
+func TestInMemoryDatabaseConcurrency(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory database used by concurrent workers", t, func() {
+		db := NewInMemoryDatabase()
+		const changes = 25
+
+		Convey("Each worker changing its own author should keep every change", func() {
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				id := fmt.Sprintf("author-%02d", worker)
+				if _, err := db.AddAuthor(ctx, data.Author{ID: id, Name: "Author"}); err != nil {
+					return err
+				}
+				for change := 0; change < changes; change++ {
+					patch := data.AuthorPatch{Author: data.Author{Occupations: []string{fmt.Sprint(change)}}, Fields: []string{data.FieldOccupations}}
+					if _, err := db.PatchAuthor(ctx, id, patch, int64(change+1)); err != nil {
+						return err
+					}
+					db.ListAll(ctx)
+					if _, err := db.ListPage(ctx, PageRequest{Size: 5}); err != nil {
+						return err
+					}
+					if _, err := db.SearchAuthors(ctx, "Auth", 10); err != nil {
+						return err
+					}
+					if _, err := db.ListRevisions(ctx, id); err != nil {
+						return err
+					}
+				}
+				if _, err := db.SoftDeleteAuthor(ctx, id, "editor", time.Now(), 0); err != nil {
+					return err
+				}
+				_, err := db.RestoreAuthor(ctx, id, 0)
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+
+			authors := db.ListAll(ctx)
+			So(authors, ShouldHaveLength, stressWorkers)
+			So(sort.SliceIsSorted(authors, func(i, j int) bool { return authors[i].ID < authors[j].ID }), ShouldBeTrue)
+			for _, author := range authors {
+				So(author.Version, ShouldEqual, changes+3)
+				revisions, err := db.ListRevisions(ctx, author.ID)
+				So(err, ShouldBeNil)
+				So(revisions, ShouldHaveLength, changes+3)
+			}
+		})
+
+		Convey("Workers updating the same author at the same version should conflict but one", func() {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: "author", Name: "Author"})
+			var mu sync.Mutex
+			applied := 0
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				_, err := db.UpdateAuthor(ctx, data.Author{ID: "author", Name: fmt.Sprint(worker)}, InitialVersion)
+				if errors.Is(err, ErrConflict) {
+					return nil
+				}
+				if err == nil {
+					mu.Lock()
+					applied++
+					mu.Unlock()
+				}
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			So(applied, ShouldEqual, 1)
+			author, _ := db.GetAuthor(ctx, "author")
+			So(author.Version, ShouldEqual, 2)
+		})
+
+		Convey("Workers adding the same author should fail but one", func() {
+			var mu sync.Mutex
+			added := 0
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				_, err := db.AddAuthor(ctx, data.Author{ID: "author", Name: fmt.Sprint(worker)})
+				if errors.Is(err, ErrAlreadyExists) {
+					return nil
+				}
+				if err == nil {
+					mu.Lock()
+					added++
+					mu.Unlock()
+				}
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			So(added, ShouldEqual, 1)
+		})
+
+		Convey("Concurrent batches should each be applied as a whole", func() {
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				ops := make([]BulkOperation, 10)
+				for index := range ops {
+					ops[index] = BulkOperation{Kind: BulkCreate, Author: data.Author{ID: fmt.Sprintf("author-%02d-%02d", worker, index), Name: "Author"}}
+				}
+				results, err := db.BulkWrite(ctx, ops, BulkAtomic)
+				if err != nil {
+					return err
+				}
+				for _, result := range results {
+					if result.Err != nil {
+						return result.Err
+					}
+				}
+				_, err = db.BulkWrite(ctx, []BulkOperation{{Kind: BulkDelete, ID: ops[0].Author.ID, DeletedAt: time.Now()}}, BulkAtomic)
+				if err != nil {
+					return err
+				}
+				_, err = db.ListDeleted(ctx, time.Time{})
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			So(db.ListAll(ctx), ShouldHaveLength, stressWorkers*9)
+			deleted, _ := db.ListDeleted(ctx, time.Time{})
+			So(deleted, ShouldHaveLength, stressWorkers)
+		})
+	})
+}
+
+func TestInMemoryRepositoryConcurrency(t *testing.T) {
+	Convey("Given a repository whose stores are all in memory", t, func() {
+		ctx := WithActor(context.Background(), "editor")
+		clientRepo := NewFakeClientRepository()
+		repo := New(NewInMemoryDatabase(), clientRepo, WithRetention(0))
+
+		Convey("Concurrent changes, purges and outbox runs should all be recorded", func() {
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				id := fmt.Sprintf("author-%02d", worker)
+				if _, err := repo.AddAuthor(ctx, data.Author{ID: id, Name: "Author"}); err != nil {
+					return err
+				}
+				if _, err := repo.UpdateAuthor(ctx, data.Author{ID: id, Name: "Renamed"}, 0); err != nil {
+					return err
+				}
+				if _, err := repo.AuthorHistory(ctx, id); err != nil {
+					return err
+				}
+				if err := repo.DeleteAuthor(ctx, id, 0); err != nil {
+					return err
+				}
+				if err := repo.PurgeAuthor(ctx, id); err != nil {
+					return err
+				}
+				if _, err := repo.ListOutbox(ctx); err != nil {
+					return err
+				}
+				_, err := repo.ProcessOutbox(ctx)
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			_, err := repo.ProcessOutbox(ctx)
+			So(err, ShouldBeNil)
+
+			So(repo.ListAll(ctx), ShouldBeEmpty)
+			tasks, _ := repo.ListOutbox(ctx)
+			So(tasks, ShouldBeEmpty)
+			So(clientRepo.DeletedAuthorQuotes(), ShouldHaveLength, stressWorkers)
+			for worker := 0; worker < stressWorkers; worker++ {
+				entries, _ := repo.AuthorHistory(ctx, fmt.Sprintf("author-%02d", worker))
+				So(entries, ShouldHaveLength, 4)
+			}
+		})
+	})
+}
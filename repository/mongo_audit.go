@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// auditEntryDB is the stored representation of an audit entry.
+type auditEntryDB struct {
+	ID        string    `bson:"_id"`
+	AuthorID  string    `bson:"authorid"`
+	Action    string    `bson:"action"`
+	Actor     string    `bson:"actor,omitempty"`
+	RequestID string    `bson:"requestid,omitempty"`
+	Time      time.Time `bson:"time"`
+	Before    *authorDB `bson:"before,omitempty"`
+	After     *authorDB `bson:"after,omitempty"`
+}
+
+func fromAuditEntry(entry AuditEntry) auditEntryDB {
+	stored := auditEntryDB{
+		ID:        entry.ID,
+		AuthorID:  entry.AuthorID,
+		Action:    entry.Action,
+		Actor:     entry.Actor,
+		RequestID: entry.RequestID,
+		Time:      entry.Time,
+	}
+	if entry.Before != nil {
+		before := fromAuthor(*entry.Before)
+		stored.Before = &before
+	}
+	if entry.After != nil {
+		after := fromAuthor(*entry.After)
+		stored.After = &after
+	}
+	return stored
+}
+
+func toAuditEntry(stored auditEntryDB) AuditEntry {
+	entry := AuditEntry{
+		ID:        stored.ID,
+		AuthorID:  stored.AuthorID,
+		Action:    stored.Action,
+		Actor:     stored.Actor,
+		RequestID: stored.RequestID,
+		Time:      stored.Time,
+	}
+	if stored.Before != nil {
+		before := toAuthor(*stored.Before)
+		entry.Before = &before
+	}
+	if stored.After != nil {
+		after := toAuthor(*stored.After)
+		entry.After = &after
+	}
+	return entry
+}
+
+type mongoAudit struct {
+	coll *mongo.Collection
+}
+
+// Record adds an entry to the mongo audit log.
+func (m *mongoAudit) Record(ctx context.Context, entry AuditEntry) error {
+	_, err := m.coll.InsertOne(ctx, fromAuditEntry(entry))
+	return err
+}
+
+// History returns the entries of an author from the mongo audit log, the oldest first.
+func (m *mongoAudit) History(ctx context.Context, authorID string) ([]AuditEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := m.coll.Find(ctx, bson.D{{Key: "authorid", Value: authorID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []auditEntryDB
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	entries := make([]AuditEntry, len(results))
+	for index, v := range results {
+		entries[index] = toAuditEntry(v)
+	}
+	return entries, nil
+}
+
+// CreateMongoAuditIndexes creates the index used to return the history of an author.
+func CreateMongoAuditIndexes(ctx context.Context, connection *mdb.MongoConnection) error {
+	_, err := connection.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "authorid", Value: 1}, {Key: "time", Value: 1}},
+		Options: options.Index().SetName("authorid_time"),
+	})
+	return err
+}
+
+// NewMongoAudit creates a new audit store in the mongo collection of the connection.
+func NewMongoAudit(connection *mdb.MongoConnection) AuditStore {
+	return &mongoAudit{
+		coll: connection.Collection,
+	}
+}
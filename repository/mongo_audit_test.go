@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func createMockedAuditEntry(id string, action string, now time.Time) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "authorid", Value: "author"},
+		{Key: "action", Value: action},
+		{Key: "actor", Value: "editor"},
+		{Key: "requestid", Value: "request"},
+		{Key: "time", Value: now},
+		{Key: "after", Value: bson.D{{Key: "_id", Value: "author"}, {Key: "name", Value: "Ada"}, {Key: "version", Value: 1}}},
+	}
+}
+
+func TestMongoAudit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("When using a mongo audit store", t, func() {
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run("Test Record", func(mt *mtest.T) {
+			audit := NewMongoAudit(mdb.NewMongoConnection(mt.Client, databaseName, "author_audit"))
+			author := data.Author{ID: "author", Name: "Ada"}
+			Convey("Test Record correctly", mt, func() {
+				mt.AddMockResponses(mtest.CreateSuccessResponse())
+				So(audit.Record(ctx, newAuditEntry(ctx, AuditUpdated, "author", &author, &author, now)), ShouldBeNil)
+			})
+
+			Convey("Test Record with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				So(audit.Record(ctx, newAuditEntry(ctx, AuditCreated, "author", nil, &author, now)), ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test History", func(mt *mtest.T) {
+			audit := NewMongoAudit(mdb.NewMongoConnection(mt.Client, databaseName, "author_audit"))
+			Convey("Test History correctly", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "mosha.author_audit", mtest.FirstBatch,
+						createMockedAuditEntry("first", AuditCreated, now),
+						createMockedAuditEntry("second", AuditDeleted, now.Add(time.Minute)),
+					),
+					mtest.CreateCursorResponse(0, "mosha.author_audit", mtest.NextBatch),
+				)
+				entries, err := audit.History(ctx, "author")
+				So(err, ShouldBeNil)
+				So(len(entries), ShouldEqual, 2)
+				So(entries[0].Action, ShouldEqual, AuditCreated)
+				So(entries[0].Actor, ShouldEqual, "editor")
+				So(entries[0].RequestID, ShouldEqual, "request")
+				So(entries[0].Before, ShouldBeNil)
+				So(entries[0].After.Name, ShouldEqual, "Ada")
+				So(entries[1].Action, ShouldEqual, AuditDeleted)
+			})
+
+			Convey("Test History with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, err := audit.History(ctx, "author")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
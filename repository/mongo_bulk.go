@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKeyCode is the error code of the writes of a duplicate key.
+const duplicateKeyCode = 11000
+
+// illegalOperationCode is the error code of the mongo servers refusing
+// transactions because they are not part of a replica set.
+const illegalOperationCode = 20
+
+// errBatchFailed aborts the transaction of an atomic batch with a failed operation.
+var errBatchFailed = errors.New("batch failed")
+
+// BulkWrite applies the operations of a batch to the mongo database with a
+// single bulk write. An atomic batch runs in a transaction, which requires the
+// mongo deployment to be a replica set.
+func (m *mongoDatabase) BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	var results []BulkResult
+	var err error
+	if mode == BulkAtomic {
+		results, err = m.atomicBulkWrite(ctx, ops)
+	} else {
+		results, err = m.bulkWrite(ctx, ops, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			m.storeRevision(ctx, fromAuthor(*result.Author))
+		}
+	}
+	return results, nil
+}
+
+// atomicBulkWrite applies the operations in a transaction, aborted when one of
+// them fails.
+func (m *mongoDatabase) atomicBulkWrite(ctx context.Context, ops []BulkOperation) ([]BulkResult, error) {
+	session, err := m.coll.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+	var results []BulkResult
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		var err error
+		results, err = m.bulkWrite(sc, ops, BulkAtomic)
+		if err == nil && bulkFailed(results) {
+			return nil, errBatchFailed
+		}
+		return nil, err
+	})
+	var serverErr mongo.ServerError
+	switch {
+	case errors.Is(err, errBatchFailed):
+		abortBulk(results)
+		return results, nil
+	case errors.As(err, &serverErr) && serverErr.HasErrorCode(illegalOperationCode):
+		return nil, failedPreconditionError("atomic batches require a mongo replica set: %v", err)
+	case err != nil:
+		return nil, err
+	}
+	return results, nil
+}
+
+// bulkWrite plans the operations on the stored authors and writes the
+// successful ones. The changes of the stored authors are conditioned on the
+// version that was read, so that an author changed in between is not
+// overwritten and its operation fails with ErrConflict.
+func (m *mongoDatabase) bulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	current, err := m.currentAuthors(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	results := planBulk(ops, current, mode)
+	var models []mongo.WriteModel
+	var modelResults []int
+	for index, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		after := fromAuthor(*result.Author)
+		if result.Before == nil {
+			models = append(models, mongo.NewInsertOneModel().SetDocument(after))
+		} else {
+			filter := storedVersionFilter(result.ID, result.Before.Version)
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(after))
+		}
+		modelResults = append(modelResults, index)
+	}
+	if len(models) == 0 {
+		return results, nil
+	}
+	opts := options.BulkWrite().SetOrdered(mode == BulkAtomic)
+	written, err := m.coll.BulkWrite(ctx, models, opts)
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && mode != BulkAtomic && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			index := modelResults[writeErr.Index]
+			results[index] = BulkResult{ID: results[index].ID, Err: writeError(results[index].ID, writeErr.WriteError)}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	replaced := 0
+	for _, index := range modelResults {
+		if results[index].Err == nil && results[index].Before != nil {
+			replaced++
+		}
+	}
+	if written != nil && written.MatchedCount < int64(replaced) {
+		return m.checkReplaced(ctx, results, modelResults)
+	}
+	return results, nil
+}
+
+// storedVersionFilter matches the author with the ID if it is still stored at
+// the version that was read. The authors stored before they were versioned have
+// no version field, and are read at version 0.
+func storedVersionFilter(id string, version int64) bson.D {
+	if version == 0 {
+		return bson.D{{Key: "_id", Value: id}, {Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{int64(0), nil}}}}}
+	}
+	return bson.D{{Key: "_id", Value: id}, {Key: "version", Value: version}}
+}
+
+// currentAuthors returns the stored authors changed by the operations, by ID.
+func (m *mongoDatabase) currentAuthors(ctx context.Context, ops []BulkOperation) (map[string]data.Author, error) {
+	ids := make(bson.A, len(ops))
+	for index, op := range ops {
+		ids[index] = op.AuthorID()
+	}
+	authors, err := m.findAuthors(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}, options.Find())
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]data.Author, len(authors))
+	for _, author := range authors {
+		current[author.ID] = author
+	}
+	return current, nil
+}
+
+// checkReplaced reads back the authors whose replacement may not have matched
+// because they changed after they were read, and fails the operations of the
+// authors that are not stored as they were written with ErrConflict.
+func (m *mongoDatabase) checkReplaced(ctx context.Context, results []BulkResult, modelResults []int) ([]BulkResult, error) {
+	var ops []BulkOperation
+	for _, index := range modelResults {
+		if results[index].Err == nil && results[index].Before != nil {
+			ops = append(ops, BulkOperation{Kind: BulkUpdate, Author: data.Author{ID: results[index].ID}})
+		}
+	}
+	stored, err := m.currentAuthors(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range modelResults {
+		result := results[index]
+		if result.Err != nil || result.Before == nil {
+			continue
+		}
+		author, ok := stored[result.ID]
+		switch {
+		case !ok:
+			results[index] = BulkResult{ID: result.ID, Err: notFoundError(result.ID)}
+		case !sameAuthor(author, *result.Author):
+			results[index] = BulkResult{ID: result.ID, Err: conflictError(result.ID, author.Version, result.Before.Version)}
+		}
+	}
+	return results, nil
+}
+
+// sameAuthor returns whether the authors are stored alike, comparing their
+// times at the precision of the database.
+func sameAuthor(a data.Author, b data.Author) bool {
+	first, err := bson.Marshal(fromAuthor(a))
+	if err != nil {
+		return false
+	}
+	second, err := bson.Marshal(fromAuthor(b))
+	return err == nil && bytes.Equal(first, second)
+}
+
+// writeError returns the error of an operation whose write failed, such as the
+// duplicate key error of an author created in between.
+func writeError(id string, err mongo.WriteError) error {
+	if err.Code == duplicateKeyCode {
+		return alreadyExistsError(id)
+	}
+	return err
+}
+
+// bulkFailed returns whether an operation of the batch failed.
+func bulkFailed(results []BulkResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoBulkWrite(t *testing.T) {
+	ctx := context.Background()
+	stored := append(createMockedAuthor("ada", "Ada", ""), bson.E{Key: "version", Value: int64(1)})
+
+	Convey("When using a mongo database", t, func() {
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run("Test BulkWrite in best effort mode", func(mt *mtest.T) {
+			db := NewMongoDatabase(mdb.NewMongoConnection(mt.Client, databaseName, "authors"))
+			ops := []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 1},
+				{Kind: BulkUpdate, Author: data.Author{ID: "alan", Name: "Alan"}},
+			}
+
+			Convey("Test BulkWrite correctly", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, stored),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+				)
+				results, err := db.BulkWrite(ctx, ops, BulkBestEffort)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldBeNil)
+				So(results[0].Author.Version, ShouldEqual, InitialVersion)
+				So(results[1].Err, ShouldBeNil)
+				So(results[1].Author.Version, ShouldEqual, 2)
+				So(results[1].Before.Name, ShouldEqual, "Ada")
+				So(results[2].Err, ShouldWrap, ErrNotFound)
+
+				So(mt.GetStartedEvent().CommandName, ShouldEqual, "find")
+				So(mt.GetStartedEvent().CommandName, ShouldEqual, "insert")
+				update := mt.GetStartedEvent()
+				So(update.CommandName, ShouldEqual, "update")
+				filter := update.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+				So(filter.Lookup("version").Int64(), ShouldEqual, 1)
+			})
+
+			Convey("Test BulkWrite when an author is created in between", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, stored),
+					mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+				)
+				results, err := db.BulkWrite(ctx, ops, BulkBestEffort)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldWrap, ErrAlreadyExists)
+				So(results[1].Err, ShouldBeNil)
+			})
+
+			Convey("Test BulkWrite when an author is changed in between", mt, func() {
+				changed := append(createMockedAuthor("ada", "Ada", ""), bson.E{Key: "version", Value: int64(2)})
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, stored),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, changed),
+				)
+				results, err := db.BulkWrite(ctx, ops, BulkBestEffort)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldBeNil)
+				So(results[1].Err, ShouldWrap, ErrConflict)
+			})
+
+			Convey("Test BulkWrite on an author stored without a version", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, createMockedAuthor("ada", "Ada", "")),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+				)
+				mt.ClearEvents()
+				results, err := db.BulkWrite(ctx, []BulkOperation{
+					{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}},
+				}, BulkBestEffort)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldBeNil)
+				So(results[0].Author.Version, ShouldEqual, 1)
+
+				So(mt.GetStartedEvent().CommandName, ShouldEqual, "find")
+				update := mt.GetStartedEvent()
+				So(update.CommandName, ShouldEqual, "update")
+				filter := update.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+				versions := filter.Lookup("version", "$in").Array()
+				So(versions.Index(0).Value().Int64(), ShouldEqual, 0)
+				So(versions.Index(1).Value().Type, ShouldEqual, bsontype.Null)
+			})
+
+			Convey("Test BulkWrite when the authors cannot be read", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, err := db.BulkWrite(ctx, ops, BulkBestEffort)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test BulkWrite in atomic mode", func(mt *mtest.T) {
+			db := NewMongoDatabase(mdb.NewMongoConnection(mt.Client, databaseName, "authors"))
+
+			Convey("Test BulkWrite on a standalone server", mt, func() {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    illegalOperationCode,
+					Message: "Transaction numbers are only allowed on a replica set member or mongos",
+				}))
+				_, err := db.BulkWrite(ctx, []BulkOperation{
+					{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				}, BulkAtomic)
+				So(err, ShouldWrap, ErrFailedPrecondition)
+			})
+
+			Convey("Test BulkWrite on an author stored without a version", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, createMockedAuthor("ada", "Ada", "")),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+					mtest.CreateSuccessResponse(),
+				)
+				results, err := db.BulkWrite(ctx, []BulkOperation{
+					{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+					{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}},
+				}, BulkAtomic)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldBeNil)
+				So(results[1].Err, ShouldBeNil)
+				So(results[1].Before.Version, ShouldEqual, 0)
+			})
+
+			Convey("Test BulkWrite when an author is changed in between", mt, func() {
+				changed := append(createMockedAuthor("ada", "Ada", ""), bson.E{Key: "version", Value: int64(2)})
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, stored),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch, changed),
+					mtest.CreateSuccessResponse(),
+				)
+				results, err := db.BulkWrite(ctx, []BulkOperation{
+					{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+					{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 1},
+				}, BulkAtomic)
+				So(err, ShouldBeNil)
+				So(results[0].Err, ShouldWrap, ErrAborted)
+				So(results[0].Author, ShouldBeNil)
+				So(results[1].Err, ShouldWrap, ErrConflict)
+			})
+		})
+	})
+}
@@ -2,39 +2,64 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/wcodesoft/mosha-author-service/data"
 	mdb "github.com/wcodesoft/mosha-service-common/database"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"regexp"
+	"time"
 )
 
+// authorSort is the stable order in which authors are listed.
+var authorSort = bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}
+
+// notDeleted matches the authors that are not deleted, including the ones
+// stored before authors could be deleted.
+var notDeleted = bson.E{Key: "deletedat", Value: nil}
+
+// isDeleted matches the deleted authors.
+var isDeleted = bson.E{Key: "deletedat", Value: bson.D{{Key: "$ne", Value: nil}}}
+
 type mongoDatabase struct {
 	connection *mdb.MongoConnection
 	coll       *mongo.Collection
+	revisions  *mongo.Collection
+	now        func() time.Time
 }
 
 // AddAuthor adds an author to the mongo database.
-func (m *mongoDatabase) AddAuthor(author data.Author) (string, error) {
-	result, err := m.coll.InsertOne(context.Background(), fromAuthor(author))
+func (m *mongoDatabase) AddAuthor(ctx context.Context, author data.Author) (string, error) {
+	author.Version = InitialVersion
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	stored := fromAuthor(author)
+	result, err := m.coll.InsertOne(ctx, stored)
+	if mongo.IsDuplicateKeyError(err) {
+		return "", alreadyExistsError(author.ID)
+	}
 	if err != nil {
 		return "", err
 	}
+	m.storeRevision(ctx, stored)
 	newId := result.InsertedID
 	return fmt.Sprintf("%v", newId), nil
 }
 
-// ListAll returns all authors in the mongo database.
-func (m *mongoDatabase) ListAll() []data.Author {
-	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
-	cursor, err := m.coll.Find(context.Background(), bson.D{}, opts)
+// ListAll returns all authors in the mongo database that are not deleted.
+func (m *mongoDatabase) ListAll(ctx context.Context) []data.Author {
+	opts := options.Find().SetSort(authorSort)
+	cursor, err := m.coll.Find(ctx, bson.D{notDeleted}, opts)
 	if err != nil {
 		return []data.Author{}
 	}
 	var results []authorDB
-	if err = cursor.All(context.TODO(), &results); err != nil {
-		panic(err)
+	if err = cursor.All(ctx, &results); err != nil {
+		return []data.Author{}
 	}
 	authors := make([]data.Author, len(results))
 	for index, v := range results {
@@ -43,48 +68,245 @@ func (m *mongoDatabase) ListAll() []data.Author {
 	return authors
 }
 
+// ListPage returns a page of authors in the mongo database sorted by name and then by ID.
+func (m *mongoDatabase) ListPage(ctx context.Context, page PageRequest) (AuthorPage, error) {
+	cursor, err := page.cursor()
+	if err != nil {
+		return AuthorPage{}, err
+	}
+	size := page.pageSize()
+	filter := bson.D{notDeleted}
+	if cursor != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name", Value: bson.D{{Key: "$gt", Value: cursor.Name}}}},
+			bson.D{{Key: "name", Value: cursor.Name}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: cursor.ID}}}},
+		}})
+	}
+	opts := options.Find().SetSort(authorSort).SetLimit(int64(size + 1))
+	authors, err := m.findAuthors(ctx, filter, opts)
+	if err != nil {
+		return AuthorPage{}, err
+	}
+	return newAuthorPage(authors, size), nil
+}
+
 // UpdateAuthor updates an author in the mongo database.
-func (m *mongoDatabase) UpdateAuthor(author data.Author) (data.Author, error) {
-	filter := bson.D{{Key: "_id", Value: author.ID}}
-	opts := options.Update().SetHint(bson.D{{Key: "_id", Value: 1}})
+func (m *mongoDatabase) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	// The version and the deletion are left out of $set, they are only changed
+	// by the operations meant for them.
+	author.Version = 0
+	author.DeletedAt = nil
+	author.DeletedBy = ""
 	update := bson.D{{Key: "$set", Value: fromAuthor(author)}}
-	_, err := m.coll.UpdateOne(context.Background(), filter, update, opts)
+	return m.updateAuthor(ctx, author.ID, notDeleted, update, expectedVersion)
+}
+
+// PatchAuthor changes only the patched fields of an author in the mongo database.
+func (m *mongoDatabase) PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	set, err := patchDocument(patch)
 	if err != nil {
 		return data.Author{}, err
 	}
-	return author, nil
+	update := bson.D{{Key: "$set", Value: set}}
+	return m.updateAuthor(ctx, id, notDeleted, update, expectedVersion)
 }
 
-// DeleteAuthor deletes an author from the mongo database.
-func (m *mongoDatabase) DeleteAuthor(id string) error {
-	filter := bson.D{{Key: "_id", Value: id}}
-	opts := options.Delete().SetHint(bson.D{{Key: "_id", Value: 1}})
-	result, err := m.coll.DeleteOne(context.Background(), filter, opts)
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("author with id %s not found", id)
+// SoftDeleteAuthor marks an author of the mongo database as deleted.
+func (m *mongoDatabase) SoftDeleteAuthor(ctx context.Context, id string, deletedBy string, deletedAt time.Time, expectedVersion int64) (data.Author, error) {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "deletedat", Value: deletedAt},
+		{Key: "deletedby", Value: deletedBy},
+	}}}
+	return m.updateAuthor(ctx, id, notDeleted, update, expectedVersion)
+}
+
+// RestoreAuthor clears the deletion of a deleted author of the mongo database.
+func (m *mongoDatabase) RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error) {
+	update := bson.D{{Key: "$unset", Value: bson.D{
+		{Key: "deletedat", Value: ""},
+		{Key: "deletedby", Value: ""},
+	}}}
+	return m.updateAuthor(ctx, id, isDeleted, update, expectedVersion)
+}
+
+// updateAuthor applies the update to the author in the state matched by the
+// state filter and increments its version, if the author is at the expected version.
+func (m *mongoDatabase) updateAuthor(ctx context.Context, id string, state bson.E, update bson.D, expectedVersion int64) (data.Author, error) {
+	filter := append(versionFilter(id, expectedVersion), state)
+	update = append(update, bson.E{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var result authorDB
+	err := m.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return data.Author{}, m.missingError(ctx, id, state, expectedVersion)
 	}
+	if err != nil {
+		return data.Author{}, err
+	}
+	m.storeRevision(ctx, result)
+	return toAuthor(result), nil
+}
+
+// DeleteAuthor permanently deletes an author and its revisions from the mongo database.
+func (m *mongoDatabase) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	result, err := m.coll.DeleteOne(ctx, versionFilter(id, expectedVersion))
 	if err != nil {
 		return err
 	}
+	if result.DeletedCount == 0 {
+		return m.missingError(ctx, id, bson.E{}, expectedVersion)
+	}
+	m.deleteRevisions(ctx, id)
 	return nil
 }
 
-// GetAuthor returns an author from the mongo database.
-func (m *mongoDatabase) GetAuthor(id string) (data.Author, error) {
+// missingError tells why no author matched the ID, the state filter and the
+// expected version: either the author does not exist in that state or it is
+// at another version. An empty state filter matches any state.
+func (m *mongoDatabase) missingError(ctx context.Context, id string, state bson.E, expectedVersion int64) error {
+	if expectedVersion == 0 {
+		return notFoundError(id)
+	}
+	author, err := m.findAuthor(ctx, id, state)
+	if err != nil {
+		return err
+	}
+	return conflictError(id, author.Version, expectedVersion)
+}
+
+// GetAuthor returns an author that is not deleted from the mongo database.
+func (m *mongoDatabase) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	return m.findAuthor(ctx, id, notDeleted)
+}
+
+// GetDeletedAuthor returns a deleted author from the mongo database.
+func (m *mongoDatabase) GetDeletedAuthor(ctx context.Context, id string) (data.Author, error) {
+	return m.findAuthor(ctx, id, isDeleted)
+}
+
+// findAuthor returns the author with the ID in the state matched by the state
+// filter. An empty state filter matches any state.
+func (m *mongoDatabase) findAuthor(ctx context.Context, id string, state bson.E) (data.Author, error) {
 	filter := bson.D{{Key: "_id", Value: id}}
+	if state.Key != "" {
+		filter = append(filter, state)
+	}
 	opts := options.FindOne().SetHint(bson.D{{Key: "_id", Value: 1}})
 	var result authorDB
-	err := m.coll.FindOne(context.Background(), filter, opts).Decode(&result)
+	err := m.coll.FindOne(ctx, filter, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return data.Author{}, notFoundError(id)
+	}
 	if err != nil {
 		return data.Author{}, err
 	}
 	return toAuthor(result), nil
 }
 
+// ListDeleted returns the authors deleted before the time, or all deleted
+// authors when the time is zero, from the oldest deletion to the newest.
+func (m *mongoDatabase) ListDeleted(ctx context.Context, deletedBefore time.Time) ([]data.Author, error) {
+	filter := bson.D{isDeleted}
+	if !deletedBefore.IsZero() {
+		filter = bson.D{{Key: "deletedat", Value: bson.D{{Key: "$lte", Value: deletedBefore}}}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "deletedat", Value: 1}, {Key: "_id", Value: 1}})
+	return m.findAuthors(ctx, filter, opts)
+}
+
+// SearchAuthors returns the authors whose name matches the query using the
+// text index on the name, completed by the names starting with the query.
+func (m *mongoDatabase) SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error) {
+	query, err := searchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	limit = searchLimit(limit)
+
+	textFilter := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}}, notDeleted}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	textOpts := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
+	authors, err := m.findAuthors(ctx, textFilter, textOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(authors) >= limit {
+		return authors, nil
+	}
+
+	found := make(bson.A, len(authors))
+	for index, author := range authors {
+		found[index] = author.ID
+	}
+	prefixFilter := bson.D{
+		{Key: "name", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query), Options: "i"}},
+		{Key: "_id", Value: bson.D{{Key: "$nin", Value: found}}},
+		notDeleted,
+	}
+	prefixOpts := options.Find().SetSort(authorSort).SetLimit(int64(limit - len(authors)))
+	prefixed, err := m.findAuthors(ctx, prefixFilter, prefixOpts)
+	if err != nil {
+		return nil, err
+	}
+	return append(authors, prefixed...), nil
+}
+
+// findAuthors returns the authors matching the filter.
+func (m *mongoDatabase) findAuthors(ctx context.Context, filter bson.D, opts *options.FindOptions) ([]data.Author, error) {
+	cursor, err := m.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []authorDB
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	authors := make([]data.Author, len(results))
+	for index, v := range results {
+		authors[index] = toAuthor(v)
+	}
+	return authors, nil
+}
+
+// CreateMongoIndexes creates the indexes used to sort and search authors.
+func CreateMongoIndexes(ctx context.Context, connection *mdb.MongoConnection) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    authorSort,
+			Options: options.Index().SetName("name_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}},
+			Options: options.Index().SetName("name_text"),
+		},
+	}
+	_, err := connection.Collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// Ping checks that the primary of the mongo deployment answers.
+func (m *mongoDatabase) Ping(ctx context.Context) error {
+	return m.coll.Database().Client().Ping(ctx, readpref.Primary())
+}
+
 // NewMongoDatabase creates a new mongo database.
-func NewMongoDatabase(connection *mdb.MongoConnection) Database {
-	return &mongoDatabase{
+func NewMongoDatabase(connection *mdb.MongoConnection, opts ...MongoOption) Database {
+	m := &mongoDatabase{
 		connection: connection,
 		coll:       connection.Collection,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// versionFilter matches the author with the ID, and with the version if one is expected.
+func versionFilter(id string, expectedVersion int64) bson.D {
+	filter := bson.D{{Key: "_id", Value: id}}
+	if expectedVersion != 0 {
+		filter = append(filter, bson.E{Key: "version", Value: expectedVersion})
 	}
+	return filter
 }
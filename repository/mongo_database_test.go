@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	faker "github.com/brianvoe/gofakeit/v6"
 	. "github.com/smartystreets/goconvey/convey"
@@ -22,6 +24,7 @@ func createMockedAuthor(id string, name string, picUrl string) bson.D {
 }
 
 func TestMongoDB(t *testing.T) {
+	ctx := context.Background()
 
 	Convey("When using a database instance", t, func() {
 		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
@@ -36,7 +39,7 @@ func TestMongoDB(t *testing.T) {
 			mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "_id", Value: id}})
 			Convey("Test AddAuthor correctly", mt, func() {
 				author := data.Author{ID: id, Name: name, PicURL: picUrl}
-				id, err := db.AddAuthor(author)
+				id, err := db.AddAuthor(ctx, author)
 				So(err, ShouldBeNil)
 				So(id, ShouldEqual, author.ID)
 			})
@@ -44,10 +47,21 @@ func TestMongoDB(t *testing.T) {
 			mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}, {Key: "_id", Value: id}})
 			Convey("Test AddAuthor with error", mt, func() {
 				author := data.Author{ID: id, Name: name, PicURL: picUrl}
-				id, err := db.AddAuthor(author)
+				id, err := db.AddAuthor(ctx, author)
 				So(err, ShouldNotBeNil)
 				So(id, ShouldEqual, "")
 			})
+
+			Convey("Test AddAuthor with duplicated ID", mt, func() {
+				mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+					Index:   0,
+					Code:    11000,
+					Message: "duplicate key error",
+				}))
+				author := data.Author{ID: id, Name: name, PicURL: picUrl}
+				_, err := db.AddAuthor(ctx, author)
+				So(err, ShouldWrap, ErrAlreadyExists)
+			})
 		})
 
 		mt.Run("Test GetAuthor", func(mt *mtest.T) {
@@ -62,15 +76,28 @@ func TestMongoDB(t *testing.T) {
 			killCursors := mtest.CreateCursorResponse(0, "mosha.authors", mtest.NextBatch)
 			mt.AddMockResponses(mockFind, killCursors)
 			Convey("Test GetAuthor correctly", mt, func() {
-				author, err := db.GetAuthor(id)
+				author, err := db.GetAuthor(ctx, id)
 				So(err, ShouldBeNil)
 				So(author.ID, ShouldEqual, id)
 				So(author.Name, ShouldEqual, name)
 				So(author.PicURL, ShouldEqual, picUrl)
 			})
 
+			Convey("Test GetAuthor with canceled context", mt, func() {
+				canceled, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err := db.GetAuthor(canceled, id)
+				So(err, ShouldWrap, context.Canceled)
+			})
+
+			Convey("Test GetAuthor not found", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch))
+				_, err := db.GetAuthor(ctx, id)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
 			Convey("Test GetAuthor with error", mt, func() {
-				author, err := db.GetAuthor(id)
+				author, err := db.GetAuthor(ctx, id)
 				So(err, ShouldNotBeNil)
 				So(author.ID, ShouldEqual, "")
 				So(author.Name, ShouldEqual, "")
@@ -83,39 +110,69 @@ func TestMongoDB(t *testing.T) {
 			db := NewMongoDatabase(conn)
 			Convey("Test DeleteAuthor correctly", mt, func() {
 				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 1}})
-				err := db.DeleteAuthor(id)
+				err := db.DeleteAuthor(ctx, id, 0)
 				So(err, ShouldBeNil)
 			})
 
 			Convey("Test DeleteAuthor with error", mt, func() {
 				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 0}})
-				err := db.DeleteAuthor("InvalidID")
-				So(err, ShouldNotBeNil)
+				err := db.DeleteAuthor(ctx, "InvalidID", 0)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Test DeleteAuthor at another version", mt, func() {
+				mt.AddMockResponses(
+					bson.D{{Key: "ok", Value: 1}, {Key: "acknowledged", Value: true}, {Key: "n", Value: 0}},
+					mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, createMockedAuthor(id, name, picUrl)),
+				)
+				err := db.DeleteAuthor(ctx, id, 3)
+				So(err, ShouldWrap, ErrConflict)
 			})
 		})
 
 		mt.Run("Test UpdateAuthor", func(mt *mtest.T) {
 			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
 			db := NewMongoDatabase(conn)
-			mt.AddMockResponses(bson.D{
-				{Key: "ok", Value: 1},
-				{Key: "value", Value: createMockedAuthor(id, name, picUrl)}})
 
 			Convey("Test UpdateAuthor correctly", mt, func() {
 				newName := faker.Name()
+				updated := createMockedAuthor(id, newName, picUrl)
+				updated = append(updated, bson.E{Key: "version", Value: int64(2)})
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: updated}})
 				author := data.Author{ID: id, Name: newName, PicURL: picUrl}
-				newAuthor, err := db.UpdateAuthor(author)
+				newAuthor, err := db.UpdateAuthor(ctx, author, 1)
 				So(err, ShouldBeNil)
 				So(newAuthor.ID, ShouldEqual, author.ID)
 				So(newAuthor.Name, ShouldEqual, author.Name)
 				So(newAuthor.PicURL, ShouldEqual, author.PicURL)
 				So(newAuthor.Name, ShouldNotEqual, name)
+				So(newAuthor.Version, ShouldEqual, 2)
+			})
+
+			Convey("Test UpdateAuthor not found", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+				author := data.Author{ID: "InvalidID", Name: name, PicURL: picUrl}
+				_, err := db.UpdateAuthor(ctx, author, 0)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Test UpdateAuthor at another version", mt, func() {
+				current := createMockedAuthor(id, name, picUrl)
+				current = append(current, bson.E{Key: "version", Value: int64(4)})
+				mt.AddMockResponses(
+					bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+					mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, current),
+				)
+				author := data.Author{ID: id, Name: name, PicURL: picUrl}
+				_, err := db.UpdateAuthor(ctx, author, 3)
+				So(err, ShouldWrap, ErrConflict)
 			})
 
 			Convey("Test UpdateAuthor with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
 				newName := faker.Name()
 				author := data.Author{ID: "InvallidID", Name: newName, PicURL: picUrl}
-				newAuthor, err := db.UpdateAuthor(author)
+				newAuthor, err := db.UpdateAuthor(ctx, author, 0)
 				So(err, ShouldNotBeNil)
 				So(newAuthor.ID, ShouldEqual, "")
 				So(newAuthor.Name, ShouldEqual, "")
@@ -123,6 +180,110 @@ func TestMongoDB(t *testing.T) {
 			})
 		})
 
+		mt.Run("Test PatchAuthor", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+			patch := data.AuthorPatch{Author: data.Author{Name: "New name"}, Fields: []string{data.FieldName}}
+
+			Convey("Test PatchAuthor correctly", mt, func() {
+				mt.AddMockResponses(bson.D{
+					{Key: "ok", Value: 1},
+					{Key: "value", Value: createMockedAuthor(id, "New name", picUrl)},
+				})
+				author, err := db.PatchAuthor(ctx, id, patch, 0)
+				So(err, ShouldBeNil)
+				So(author.Name, ShouldEqual, "New name")
+				So(author.PicURL, ShouldEqual, picUrl)
+			})
+
+			Convey("Test PatchAuthor not found", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+				_, err := db.PatchAuthor(ctx, id, patch, 0)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Test PatchAuthor with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, err := db.PatchAuthor(ctx, id, patch, 0)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Test PatchAuthor with unknown field", mt, func() {
+				_, err := db.PatchAuthor(ctx, id, data.AuthorPatch{Fields: []string{"id"}}, 0)
+				So(err, ShouldWrap, ErrInvalidArgument)
+			})
+		})
+
+		mt.Run("Test SoftDeleteAuthor", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+			deletedAt := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+			Convey("Test SoftDeleteAuthor correctly", mt, func() {
+				deleted := append(createMockedAuthor(id, name, picUrl),
+					bson.E{Key: "version", Value: int64(2)},
+					bson.E{Key: "deletedat", Value: deletedAt},
+					bson.E{Key: "deletedby", Value: "editor"},
+				)
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: deleted}})
+				author, err := db.SoftDeleteAuthor(ctx, id, "editor", deletedAt, 1)
+				So(err, ShouldBeNil)
+				So(author.IsDeleted(), ShouldBeTrue)
+				So(*author.DeletedAt, ShouldEqual, deletedAt)
+				So(author.DeletedBy, ShouldEqual, "editor")
+				So(author.Version, ShouldEqual, 2)
+			})
+
+			Convey("Test SoftDeleteAuthor not found", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+				_, err := db.SoftDeleteAuthor(ctx, id, "editor", deletedAt, 0)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Test GetDeletedAuthor correctly", mt, func() {
+				deleted := append(createMockedAuthor(id, name, picUrl), bson.E{Key: "deletedat", Value: deletedAt})
+				mt.AddMockResponses(mtest.CreateCursorResponse(1, "mosha.authors", mtest.FirstBatch, deleted))
+				author, err := db.GetDeletedAuthor(ctx, id)
+				So(err, ShouldBeNil)
+				So(author.IsDeleted(), ShouldBeTrue)
+			})
+
+			Convey("Test ListDeleted correctly", mt, func() {
+				deleted := append(createMockedAuthor(id, name, picUrl), bson.E{Key: "deletedat", Value: deletedAt})
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "mosha.authors", mtest.FirstBatch, deleted),
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.NextBatch),
+				)
+				authors, err := db.ListDeleted(ctx, deletedAt)
+				So(err, ShouldBeNil)
+				So(len(authors), ShouldEqual, 1)
+				So(authors[0].ID, ShouldEqual, id)
+			})
+		})
+
+		mt.Run("Test RestoreAuthor", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+
+			Convey("Test RestoreAuthor correctly", mt, func() {
+				restored := append(createMockedAuthor(id, name, picUrl), bson.E{Key: "version", Value: int64(3)})
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: restored}})
+				author, err := db.RestoreAuthor(ctx, id, 2)
+				So(err, ShouldBeNil)
+				So(author.IsDeleted(), ShouldBeFalse)
+				So(author.Version, ShouldEqual, 3)
+			})
+
+			Convey("Test RestoreAuthor of an author that is not deleted", mt, func() {
+				mt.AddMockResponses(
+					bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+					mtest.CreateCursorResponse(0, "mosha.authors", mtest.FirstBatch),
+				)
+				_, err := db.RestoreAuthor(ctx, id, 2)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+		})
+
 		mt.Run("Test ListAuthors", func(mt *mtest.T) {
 			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
 			db := NewMongoDatabase(conn)
@@ -144,15 +305,140 @@ func TestMongoDB(t *testing.T) {
 				killCursors := mtest.CreateCursorResponse(0, "mosha.authors", mtest.NextBatch)
 				mt.AddMockResponses(first, second, killCursors)
 
-				authors := db.ListAll()
+				authors := db.ListAll(ctx)
 				So(len(authors), ShouldEqual, 2)
 			})
 
 			Convey("Test ListAuthors with error", mt, func() {
 				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
-				authors := db.ListAll()
+				authors := db.ListAll(ctx)
 				So(len(authors), ShouldNotEqual, 3)
 			})
 		})
+
+		mt.Run("Test SearchAuthors", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+			Convey("Test SearchAuthors with enough text matches", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(
+					0,
+					"mosha.authors",
+					mtest.FirstBatch,
+					createMockedAuthor(id, name, picUrl),
+				))
+				authors, err := db.SearchAuthors(ctx, name, 1)
+				So(err, ShouldBeNil)
+				So(len(authors), ShouldEqual, 1)
+				So(authors[0].ID, ShouldEqual, id)
+			})
+
+			Convey("Test SearchAuthors completed by prefix matches", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(
+						0,
+						"mosha.authors",
+						mtest.FirstBatch,
+						createMockedAuthor(id, name, picUrl),
+					),
+					mtest.CreateCursorResponse(
+						0,
+						"mosha.authors",
+						mtest.FirstBatch,
+						createMockedAuthor("other", name, picUrl),
+					),
+				)
+				authors, err := db.SearchAuthors(ctx, name, 10)
+				So(err, ShouldBeNil)
+				So(len(authors), ShouldEqual, 2)
+				So(authors[1].ID, ShouldEqual, "other")
+			})
+
+			Convey("Test SearchAuthors with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, err := db.SearchAuthors(ctx, name, 10)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Test SearchAuthors with empty query", mt, func() {
+				_, err := db.SearchAuthors(ctx, " ", 10)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test CreateMongoIndexes", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			Convey("Test CreateMongoIndexes correctly", mt, func() {
+				mt.AddMockResponses(mtest.CreateSuccessResponse())
+				So(CreateMongoIndexes(ctx, conn), ShouldBeNil)
+			})
+
+			Convey("Test CreateMongoIndexes with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				So(CreateMongoIndexes(ctx, conn), ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test ListPage", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+			Convey("Test ListPage with a next page", mt, func() {
+				first := mtest.CreateCursorResponse(
+					0,
+					"mosha.authors",
+					mtest.FirstBatch,
+					createMockedAuthor("1", "Ada Lovelace", picUrl),
+					createMockedAuthor("2", "Blaise Pascal", picUrl),
+				)
+				mt.AddMockResponses(first)
+
+				page, err := db.ListPage(ctx, PageRequest{Size: 1})
+				So(err, ShouldBeNil)
+				So(len(page.Authors), ShouldEqual, 1)
+				So(page.Authors[0].ID, ShouldEqual, "1")
+				So(page.NextPageToken, ShouldEqual, encodePageToken(page.Authors[0]))
+			})
+
+			Convey("Test ListPage on the last page", mt, func() {
+				first := mtest.CreateCursorResponse(
+					0,
+					"mosha.authors",
+					mtest.FirstBatch,
+					createMockedAuthor("2", "Blaise Pascal", picUrl),
+				)
+				mt.AddMockResponses(first)
+
+				token := encodePageToken(data.Author{ID: "1", Name: "Ada Lovelace"})
+				page, err := db.ListPage(ctx, PageRequest{Size: 1, Token: token})
+				So(err, ShouldBeNil)
+				So(len(page.Authors), ShouldEqual, 1)
+				So(page.NextPageToken, ShouldBeEmpty)
+			})
+
+			Convey("Test ListPage with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, err := db.ListPage(ctx, PageRequest{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Test ListPage with invalid token", mt, func() {
+				_, err := db.ListPage(ctx, PageRequest{Token: "invalid"})
+				So(err, ShouldWrap, ErrInvalidArgument)
+			})
+		})
+
+		mt.Run("Test Ping", func(mt *mtest.T) {
+			conn := mdb.NewMongoConnection(mt.Client, databaseName, "author")
+			db := NewMongoDatabase(conn)
+
+			Convey("Test Ping correctly", mt, func() {
+				mt.AddMockResponses(mtest.CreateSuccessResponse())
+				So(db.Ping(ctx), ShouldBeNil)
+			})
+
+			Convey("Test Ping with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				So(db.Ping(ctx), ShouldNotBeNil)
+			})
+		})
 	})
 }
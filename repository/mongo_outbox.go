@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// outboxTaskDB is the stored representation of an outbox task.
+type outboxTaskDB struct {
+	ID            string    `bson:"_id"`
+	Kind          string    `bson:"kind"`
+	AuthorID      string    `bson:"authorid"`
+	Attempts      int       `bson:"attempts"`
+	CreatedAt     time.Time `bson:"createdat"`
+	NextAttemptAt time.Time `bson:"nextattemptat"`
+	LastError     string    `bson:"lasterror,omitempty"`
+	RequestID     string    `bson:"requestid,omitempty"`
+}
+
+func toOutboxTask(task outboxTaskDB) OutboxTask {
+	return OutboxTask{
+		ID:            task.ID,
+		Kind:          task.Kind,
+		AuthorID:      task.AuthorID,
+		Attempts:      task.Attempts,
+		CreatedAt:     task.CreatedAt,
+		NextAttemptAt: task.NextAttemptAt,
+		LastError:     task.LastError,
+		RequestID:     task.RequestID,
+	}
+}
+
+type mongoOutbox struct {
+	coll *mongo.Collection
+}
+
+// Enqueue adds a task to the mongo outbox, or makes the pending task with the same ID due.
+func (m *mongoOutbox) Enqueue(ctx context.Context, task OutboxTask) error {
+	filter := bson.D{{Key: "_id", Value: task.ID}}
+	update := bson.D{
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "kind", Value: task.Kind},
+			{Key: "authorid", Value: task.AuthorID},
+			{Key: "attempts", Value: task.Attempts},
+			{Key: "createdat", Value: task.CreatedAt},
+			{Key: "requestid", Value: task.RequestID},
+		}},
+		{Key: "$set", Value: bson.D{{Key: "nextattemptat", Value: task.NextAttemptAt}}},
+	}
+	_, err := m.coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Claim returns the task of the mongo outbox that is due the longest and hides
+// it for the lease. The claim is atomic, so concurrent workers never claim the same task.
+func (m *mongoOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration) (OutboxTask, bool, error) {
+	filter := bson.D{{Key: "nextattemptat", Value: bson.D{{Key: "$lte", Value: now}}}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "nextattemptat", Value: now.Add(lease)}}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextattemptat", Value: 1}}).
+		SetReturnDocument(options.Before)
+	var result outboxTaskDB
+	err := m.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return OutboxTask{}, false, nil
+	}
+	if err != nil {
+		return OutboxTask{}, false, err
+	}
+	return toOutboxTask(result), true, nil
+}
+
+// Complete removes a task from the mongo outbox.
+func (m *mongoOutbox) Complete(ctx context.Context, id string) error {
+	_, err := m.coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+// Retry records the failure of a task of the mongo outbox.
+func (m *mongoOutbox) Retry(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "nextattemptat", Value: nextAttemptAt},
+			{Key: "lasterror", Value: lastError},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+	}
+	_, err := m.coll.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+// List returns the pending tasks of the mongo outbox, the oldest first.
+func (m *mongoOutbox) List(ctx context.Context) ([]OutboxTask, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := m.coll.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []outboxTaskDB
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	tasks := make([]OutboxTask, len(results))
+	for index, v := range results {
+		tasks[index] = toOutboxTask(v)
+	}
+	return tasks, nil
+}
+
+// CreateMongoOutboxIndexes creates the index used to claim the due tasks.
+func CreateMongoOutboxIndexes(ctx context.Context, connection *mdb.MongoConnection) error {
+	_, err := connection.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "nextattemptat", Value: 1}},
+		Options: options.Index().SetName("nextattemptat"),
+	})
+	return err
+}
+
+// NewMongoOutbox creates a new outbox store in the mongo collection of the connection.
+func NewMongoOutbox(connection *mdb.MongoConnection) OutboxStore {
+	return &mongoOutbox{
+		coll: connection.Collection,
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func createMockedTask(authorID string, nextAttemptAt time.Time) bson.D {
+	return bson.D{
+		{Key: "_id", Value: TaskDeleteAuthorQuotes + ":" + authorID},
+		{Key: "kind", Value: TaskDeleteAuthorQuotes},
+		{Key: "authorid", Value: authorID},
+		{Key: "attempts", Value: 2},
+		{Key: "createdat", Value: nextAttemptAt},
+		{Key: "nextattemptat", Value: nextAttemptAt},
+		{Key: "lasterror", Value: "unavailable"},
+	}
+}
+
+func TestMongoOutbox(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("When using a mongo outbox", t, func() {
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run("Test Enqueue", func(mt *mtest.T) {
+			outbox := NewMongoOutbox(mdb.NewMongoConnection(mt.Client, databaseName, "author_outbox"))
+			Convey("Test Enqueue correctly", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "upserted", Value: bson.A{}}})
+				So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("author", "", now)), ShouldBeNil)
+			})
+
+			Convey("Test Enqueue with a request ID", mt, func() {
+				mt.ClearEvents()
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "upserted", Value: bson.A{}}})
+				So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("author", "request", now)), ShouldBeNil)
+				update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+				So(update.Lookup("$setOnInsert", "requestid").StringValue(), ShouldEqual, "request")
+			})
+
+			Convey("Test Enqueue with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("author", "", now)), ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test Claim", func(mt *mtest.T) {
+			outbox := NewMongoOutbox(mdb.NewMongoConnection(mt.Client, databaseName, "author_outbox"))
+			Convey("Test Claim correctly", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: createMockedTask("author", now)}})
+				task, ok, err := outbox.Claim(ctx, now, time.Minute)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(task.AuthorID, ShouldEqual, "author")
+				So(task.Kind, ShouldEqual, TaskDeleteAuthorQuotes)
+				So(task.Attempts, ShouldEqual, 2)
+				So(task.LastError, ShouldEqual, "unavailable")
+			})
+
+			Convey("Test Claim without due task", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+				_, ok, err := outbox.Claim(ctx, now, time.Minute)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Test Claim with error", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+				_, _, err := outbox.Claim(ctx, now, time.Minute)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		mt.Run("Test Complete and Retry", func(mt *mtest.T) {
+			outbox := NewMongoOutbox(mdb.NewMongoConnection(mt.Client, databaseName, "author_outbox"))
+			Convey("Test Complete correctly", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+				So(outbox.Complete(ctx, "id"), ShouldBeNil)
+			})
+
+			Convey("Test Retry correctly", mt, func() {
+				mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+				So(outbox.Retry(ctx, "id", now, "unavailable"), ShouldBeNil)
+			})
+		})
+
+		mt.Run("Test List", func(mt *mtest.T) {
+			outbox := NewMongoOutbox(mdb.NewMongoConnection(mt.Client, databaseName, "author_outbox"))
+			Convey("Test List correctly", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "mosha.author_outbox", mtest.FirstBatch,
+						createMockedTask("first", now),
+						createMockedTask("second", now),
+					),
+					mtest.CreateCursorResponse(0, "mosha.author_outbox", mtest.NextBatch),
+				)
+				tasks, err := outbox.List(ctx)
+				So(err, ShouldBeNil)
+				So(len(tasks), ShouldEqual, 2)
+				So(tasks[1].AuthorID, ShouldEqual, "second")
+			})
+		})
+	})
+}
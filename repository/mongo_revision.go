@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// revisionDB is the stored representation of a revision.
+type revisionDB struct {
+	ID       string    `bson:"_id"`
+	AuthorID string    `bson:"authorid"`
+	Version  int64     `bson:"version"`
+	Time     time.Time `bson:"time"`
+	Author   authorDB  `bson:"author"`
+}
+
+func fromRevision(revision Revision) revisionDB {
+	return revisionDB{
+		ID:       fmt.Sprintf("%s:%d", revision.Author.ID, revision.Version),
+		AuthorID: revision.Author.ID,
+		Version:  revision.Version,
+		Time:     revision.Time,
+		Author:   fromAuthor(revision.Author),
+	}
+}
+
+func toRevision(revision revisionDB) Revision {
+	return Revision{
+		Version: revision.Version,
+		Time:    revision.Time,
+		Author:  toAuthor(revision.Author),
+	}
+}
+
+// MongoOption configures a mongo database.
+type MongoOption func(*mongoDatabase)
+
+// WithRevisions stores the revisions of the authors in the mongo collection of
+// the connection. Without it, the revisions are neither stored nor returned.
+func WithRevisions(connection *mdb.MongoConnection) MongoOption {
+	return func(m *mongoDatabase) {
+		m.revisions = connection.Collection
+	}
+}
+
+// errRevisionsNotStored is returned when reading the revisions of a mongo
+// database that does not store them.
+var errRevisionsNotStored = fmt.Errorf("revisions are not stored: %w", ErrFailedPrecondition)
+
+// storeRevision stores the revision of a changed author. The change is already
+// stored, so a failure to store its revision is logged instead of failing the change.
+func (m *mongoDatabase) storeRevision(ctx context.Context, author authorDB) {
+	if m.revisions == nil {
+		return
+	}
+	revision := newRevision(toAuthor(author), m.now().UTC())
+	if _, err := m.revisions.InsertOne(ctx, fromRevision(revision)); err != nil {
+		Logger(ctx).Error("unable to store the author revision", "author_id", author.ID, "version", author.Version, "err", err)
+	}
+}
+
+// deleteRevisions deletes the revisions of a permanently deleted author.
+func (m *mongoDatabase) deleteRevisions(ctx context.Context, id string) {
+	if m.revisions == nil {
+		return
+	}
+	if _, err := m.revisions.DeleteMany(ctx, bson.D{{Key: "authorid", Value: id}}); err != nil {
+		Logger(ctx).Error("unable to delete the author revisions", "author_id", id, "err", err)
+	}
+}
+
+// ListRevisions returns the revisions of an author from the mongo database, the oldest first.
+func (m *mongoDatabase) ListRevisions(ctx context.Context, id string) ([]Revision, error) {
+	if m.revisions == nil {
+		return nil, errRevisionsNotStored
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := m.revisions.Find(ctx, bson.D{{Key: "authorid", Value: id}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []revisionDB
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, notFoundError(id)
+	}
+	revisions := make([]Revision, len(results))
+	for index, v := range results {
+		revisions[index] = toRevision(v)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns the revision of an author at the version from the mongo database.
+func (m *mongoDatabase) GetRevision(ctx context.Context, id string, version int64) (Revision, error) {
+	filter := bson.D{{Key: "authorid", Value: id}, {Key: "version", Value: version}}
+	revision, err := m.findRevision(ctx, filter, options.FindOne())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Revision{}, revisionNotFoundError(id, version)
+	}
+	return revision, err
+}
+
+// GetRevisionAt returns the last revision of an author made at or before the
+// time from the mongo database.
+func (m *mongoDatabase) GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error) {
+	filter := bson.D{{Key: "authorid", Value: id}, {Key: "time", Value: bson.D{{Key: "$lte", Value: at}}}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "version", Value: -1}})
+	revision, err := m.findRevision(ctx, filter, opts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Revision{}, revisionAtNotFoundError(id, at)
+	}
+	return revision, err
+}
+
+// findRevision returns the revision matching the filter.
+func (m *mongoDatabase) findRevision(ctx context.Context, filter bson.D, opts *options.FindOneOptions) (Revision, error) {
+	if m.revisions == nil {
+		return Revision{}, errRevisionsNotStored
+	}
+	var result revisionDB
+	if err := m.revisions.FindOne(ctx, filter, opts).Decode(&result); err != nil {
+		return Revision{}, err
+	}
+	return toRevision(result), nil
+}
+
+// CreateMongoRevisionIndexes creates the index used to find the revision of
+// an author at a time.
+func CreateMongoRevisionIndexes(ctx context.Context, connection *mdb.MongoConnection) error {
+	_, err := connection.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "authorid", Value: 1}, {Key: "time", Value: 1}},
+		Options: options.Index().SetName("authorid_time"),
+	})
+	return err
+}
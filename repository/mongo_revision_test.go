@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func createMockedRevision(version int64, name string, now time.Time) bson.D {
+	return bson.D{
+		{Key: "_id", Value: fmt.Sprintf("author:%d", version)},
+		{Key: "authorid", Value: "author"},
+		{Key: "version", Value: version},
+		{Key: "time", Value: now},
+		{Key: "author", Value: bson.D{{Key: "_id", Value: "author"}, {Key: "name", Value: name}, {Key: "version", Value: version}}},
+	}
+}
+
+func TestMongoRevisions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("When using a mongo database storing the revisions", t, func() {
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run("Test AddAuthor stores a revision", func(mt *mtest.T) {
+			db := NewMongoDatabase(
+				mdb.NewMongoConnection(mt.Client, databaseName, "authors"),
+				WithRevisions(mdb.NewMongoConnection(mt.Client, databaseName, "author_revisions")),
+			)
+			Convey("Test AddAuthor correctly", mt, func() {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+				_, err := db.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+				So(err, ShouldBeNil)
+				So(mt.GetStartedEvent().Command.Lookup("insert").StringValue(), ShouldEqual, "authors")
+				started := mt.GetStartedEvent()
+				So(started.CommandName, ShouldEqual, "insert")
+				So(started.Command.Lookup("insert").StringValue(), ShouldEqual, "author_revisions")
+			})
+
+			Convey("Test AddAuthor when the revision cannot be stored", mt, func() {
+				mt.AddMockResponses(mtest.CreateSuccessResponse(), bson.D{{Key: "ok", Value: 0}})
+				_, err := db.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+				So(err, ShouldBeNil)
+			})
+		})
+
+		mt.Run("Test ListRevisions", func(mt *mtest.T) {
+			db := NewMongoDatabase(
+				mdb.NewMongoConnection(mt.Client, databaseName, "authors"),
+				WithRevisions(mdb.NewMongoConnection(mt.Client, databaseName, "author_revisions")),
+			)
+			Convey("Test ListRevisions correctly", mt, func() {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, "mosha.author_revisions", mtest.FirstBatch,
+						createMockedRevision(1, "Ada", now),
+						createMockedRevision(2, "Ada Lovelace", now.Add(time.Minute)),
+					),
+					mtest.CreateCursorResponse(0, "mosha.author_revisions", mtest.NextBatch),
+				)
+				revisions, err := db.ListRevisions(ctx, "author")
+				So(err, ShouldBeNil)
+				So(revisions, ShouldHaveLength, 2)
+				So(revisions[0].Version, ShouldEqual, 1)
+				So(revisions[1].Author.Name, ShouldEqual, "Ada Lovelace")
+				So(revisions[1].Time, ShouldEqual, now.Add(time.Minute))
+			})
+
+			Convey("Test ListRevisions of an unknown author", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "mosha.author_revisions", mtest.FirstBatch))
+				_, err := db.ListRevisions(ctx, "author")
+				So(err, ShouldWrap, ErrNotFound)
+			})
+		})
+
+		mt.Run("Test GetRevision", func(mt *mtest.T) {
+			db := NewMongoDatabase(
+				mdb.NewMongoConnection(mt.Client, databaseName, "authors"),
+				WithRevisions(mdb.NewMongoConnection(mt.Client, databaseName, "author_revisions")),
+			)
+			Convey("Test GetRevision correctly", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(1, "mosha.author_revisions", mtest.FirstBatch,
+					createMockedRevision(2, "Ada Lovelace", now),
+				))
+				revision, err := db.GetRevision(ctx, "author", 2)
+				So(err, ShouldBeNil)
+				So(revision.Version, ShouldEqual, 2)
+				So(revision.Author.Name, ShouldEqual, "Ada Lovelace")
+			})
+
+			Convey("Test GetRevision of an unknown version", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "mosha.author_revisions", mtest.FirstBatch))
+				_, err := db.GetRevision(ctx, "author", 3)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Test GetRevisionAt of a time before the author", mt, func() {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, "mosha.author_revisions", mtest.FirstBatch))
+				_, err := db.GetRevisionAt(ctx, "author", now)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+		})
+
+		mt.Run("Test revisions not stored", func(mt *mtest.T) {
+			db := NewMongoDatabase(mdb.NewMongoConnection(mt.Client, databaseName, "authors"))
+			Convey("Test reading the revisions fails", mt, func() {
+				_, err := db.ListRevisions(ctx, "author")
+				So(err, ShouldWrap, ErrFailedPrecondition)
+				_, err = db.GetRevisionAt(ctx, "author", now)
+				So(err, ShouldWrap, ErrFailedPrecondition)
+			})
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+// Observer observes the operations of a database, for instance to record
+// metrics or traces.
+type Observer interface {
+	// Observe is called when an operation starts. It returns the context to run
+	// the operation with and the function to call with its error once it is done.
+	Observe(ctx context.Context, operation string) (context.Context, func(err error))
+}
+
+// observedDatabase reports the operations of a database to an observer.
+type observedDatabase struct {
+	db       Database
+	observer Observer
+}
+
+// ObserveDatabase returns a database reporting each operation of db to the observer.
+func ObserveDatabase(db Database, observer Observer) Database {
+	return &observedDatabase{db: db, observer: observer}
+}
+
+func (d *observedDatabase) AddAuthor(ctx context.Context, author data.Author) (string, error) {
+	ctx, done := d.observer.Observe(ctx, "AddAuthor")
+	id, err := d.db.AddAuthor(ctx, author)
+	done(err)
+	return id, err
+}
+
+func (d *observedDatabase) ListAll(ctx context.Context) []data.Author {
+	ctx, done := d.observer.Observe(ctx, "ListAll")
+	authors := d.db.ListAll(ctx)
+	done(nil)
+	return authors
+}
+
+func (d *observedDatabase) ListPage(ctx context.Context, page PageRequest) (AuthorPage, error) {
+	ctx, done := d.observer.Observe(ctx, "ListPage")
+	authorPage, err := d.db.ListPage(ctx, page)
+	done(err)
+	return authorPage, err
+}
+
+func (d *observedDatabase) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "UpdateAuthor")
+	updated, err := d.db.UpdateAuthor(ctx, author, expectedVersion)
+	done(err)
+	return updated, err
+}
+
+func (d *observedDatabase) PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "PatchAuthor")
+	patched, err := d.db.PatchAuthor(ctx, id, patch, expectedVersion)
+	done(err)
+	return patched, err
+}
+
+func (d *observedDatabase) SoftDeleteAuthor(ctx context.Context, id string, deletedBy string, deletedAt time.Time, expectedVersion int64) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "SoftDeleteAuthor")
+	deleted, err := d.db.SoftDeleteAuthor(ctx, id, deletedBy, deletedAt, expectedVersion)
+	done(err)
+	return deleted, err
+}
+
+func (d *observedDatabase) RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "RestoreAuthor")
+	restored, err := d.db.RestoreAuthor(ctx, id, expectedVersion)
+	done(err)
+	return restored, err
+}
+
+func (d *observedDatabase) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	ctx, done := d.observer.Observe(ctx, "DeleteAuthor")
+	err := d.db.DeleteAuthor(ctx, id, expectedVersion)
+	done(err)
+	return err
+}
+
+func (d *observedDatabase) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "GetAuthor")
+	author, err := d.db.GetAuthor(ctx, id)
+	done(err)
+	return author, err
+}
+
+func (d *observedDatabase) GetDeletedAuthor(ctx context.Context, id string) (data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "GetDeletedAuthor")
+	author, err := d.db.GetDeletedAuthor(ctx, id)
+	done(err)
+	return author, err
+}
+
+func (d *observedDatabase) ListDeleted(ctx context.Context, deletedBefore time.Time) ([]data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "ListDeleted")
+	authors, err := d.db.ListDeleted(ctx, deletedBefore)
+	done(err)
+	return authors, err
+}
+
+func (d *observedDatabase) SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error) {
+	ctx, done := d.observer.Observe(ctx, "SearchAuthors")
+	authors, err := d.db.SearchAuthors(ctx, query, limit)
+	done(err)
+	return authors, err
+}
+
+func (d *observedDatabase) ListRevisions(ctx context.Context, id string) ([]Revision, error) {
+	ctx, done := d.observer.Observe(ctx, "ListRevisions")
+	revisions, err := d.db.ListRevisions(ctx, id)
+	done(err)
+	return revisions, err
+}
+
+func (d *observedDatabase) GetRevision(ctx context.Context, id string, version int64) (Revision, error) {
+	ctx, done := d.observer.Observe(ctx, "GetRevision")
+	revision, err := d.db.GetRevision(ctx, id, version)
+	done(err)
+	return revision, err
+}
+
+func (d *observedDatabase) GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error) {
+	ctx, done := d.observer.Observe(ctx, "GetRevisionAt")
+	revision, err := d.db.GetRevisionAt(ctx, id, at)
+	done(err)
+	return revision, err
+}
+
+func (d *observedDatabase) BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	ctx, done := d.observer.Observe(ctx, "BulkWrite")
+	results, err := d.db.BulkWrite(ctx, ops, mode)
+	done(err)
+	return results, err
+}
+
+func (d *observedDatabase) Ping(ctx context.Context) error {
+	ctx, done := d.observer.Observe(ctx, "Ping")
+	err := d.db.Ping(ctx)
+	done(err)
+	return err
+}
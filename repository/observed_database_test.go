@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+type observedOperation struct {
+	operation string
+	err       error
+}
+
+type contextKey string
+
+// recordingObserver records the operations and tags their context.
+type recordingObserver struct {
+	operations []observedOperation
+}
+
+func (o *recordingObserver) Observe(ctx context.Context, operation string) (context.Context, func(err error)) {
+	return context.WithValue(ctx, contextKey("operation"), operation), func(err error) {
+		o.operations = append(o.operations, observedOperation{operation: operation, err: err})
+	}
+}
+
+// contextRecordingDatabase records the operation tag of the context of each call.
+type contextRecordingDatabase struct {
+	Database
+	tags []any
+}
+
+func (d *contextRecordingDatabase) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	d.tags = append(d.tags, ctx.Value(contextKey("operation")))
+	return d.Database.GetAuthor(ctx, id)
+}
+
+func TestObserveDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an observed database", t, func() {
+		observer := &recordingObserver{}
+		inner := &contextRecordingDatabase{Database: NewInMemoryDatabase()}
+		db := ObserveDatabase(inner, observer)
+
+		Convey("Each operation should be reported with its error", func() {
+			_, err := db.AddAuthor(ctx, data.Author{ID: "1", Name: "Ada Lovelace"})
+			So(err, ShouldBeNil)
+			_, err = db.GetAuthor(ctx, "missing")
+			So(err, ShouldWrap, ErrNotFound)
+			So(db.Ping(ctx), ShouldBeNil)
+
+			So(len(observer.operations), ShouldEqual, 3)
+			So(observer.operations[0], ShouldResemble, observedOperation{operation: "AddAuthor"})
+			So(observer.operations[1].operation, ShouldEqual, "GetAuthor")
+			So(observer.operations[1].err, ShouldWrap, ErrNotFound)
+			So(observer.operations[2], ShouldResemble, observedOperation{operation: "Ping"})
+		})
+
+		Convey("The operation should run with the context of the observer", func() {
+			_, _ = db.GetAuthor(ctx, "1")
+			So(inner.tags, ShouldResemble, []any{"GetAuthor"})
+		})
+	})
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaskDeleteAuthorQuotes is the kind of the task deleting the quotes of a purged author.
+const TaskDeleteAuthorQuotes = "deleteAuthorQuotes"
+
+const (
+	// DefaultOutboxLease is how long a claimed task is hidden from the other
+	// workers before it can be claimed again, if it was neither completed nor retried.
+	DefaultOutboxLease = time.Minute
+	// minOutboxBackoff is the delay before the first retry of a failed task.
+	minOutboxBackoff = time.Second
+	// maxOutboxBackoff is the longest delay between two attempts of a task.
+	maxOutboxBackoff = 15 * time.Minute
+)
+
+// OutboxTask represents a step of a change that must run after the change was
+// stored, retried until it succeeds.
+type OutboxTask struct {
+	// ID identifies the task. Enqueuing a task with the ID of a pending one
+	// leaves a single task.
+	ID string `json:"id"`
+	// Kind is the step to run, such as TaskDeleteAuthorQuotes.
+	Kind string `json:"kind"`
+	// AuthorID is the author the step applies to.
+	AuthorID string `json:"authorId"`
+	// Attempts is the number of failed attempts.
+	Attempts int `json:"attempts"`
+	// CreatedAt is when the task was enqueued.
+	CreatedAt time.Time `json:"createdAt"`
+	// NextAttemptAt is when the task can be claimed.
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	// LastError is the error of the last failed attempt.
+	LastError string `json:"lastError,omitempty"`
+	// RequestID is the ID of the request that enqueued the task, which the
+	// task runs with.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// OutboxResult counts the tasks run by one pass over the outbox.
+type OutboxResult struct {
+	// Completed is the number of tasks that succeeded.
+	Completed int
+	// Failed is the number of tasks that failed and will be retried.
+	Failed int
+}
+
+// OutboxStore stores the pending outbox tasks alongside the authors.
+type OutboxStore interface {
+	// Enqueue adds a task that can be claimed from its NextAttemptAt.
+	Enqueue(ctx context.Context, task OutboxTask) error
+	// Claim returns a task that is due at the time and hides it from the other
+	// claims for the lease. It returns false when no task is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (OutboxTask, bool, error)
+	// Complete removes a task that succeeded.
+	Complete(ctx context.Context, id string) error
+	// Retry records the failure of a task and when it can be claimed again.
+	Retry(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error
+	// List returns the pending tasks, the oldest first.
+	List(ctx context.Context) ([]OutboxTask, error)
+}
+
+// newDeleteAuthorQuotesTask creates the task deleting the quotes of an author.
+func newDeleteAuthorQuotesTask(authorID string, requestID string, now time.Time) OutboxTask {
+	return OutboxTask{
+		ID:            TaskDeleteAuthorQuotes + ":" + authorID,
+		Kind:          TaskDeleteAuthorQuotes,
+		AuthorID:      authorID,
+		RequestID:     requestID,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+// outboxBackoff returns the delay before the next attempt of a task that
+// already failed the number of attempts, doubling after each failure.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := minOutboxBackoff
+	for i := 0; i < attempts && backoff < maxOutboxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxOutboxBackoff {
+		return maxOutboxBackoff
+	}
+	return backoff
+}
+
+// inMemoryOutbox is a simple in-memory outbox store, safe for concurrent use.
+type inMemoryOutbox struct {
+	mu    sync.Mutex
+	tasks map[string]OutboxTask
+	// journal persists the changes before they are applied, when set.
+	journal outboxJournal
+}
+
+// outboxJournal persists the changes of an in-memory outbox. A change failing
+// to be persisted is not applied.
+type outboxJournal interface {
+	// saved persists a task as a whole.
+	saved(task OutboxTask) error
+	// completed persists the removal of a task.
+	completed(id string) error
+}
+
+// NewInMemoryOutbox creates a new in-memory outbox store.
+func NewInMemoryOutbox() OutboxStore {
+	return &inMemoryOutbox{
+		tasks: make(map[string]OutboxTask),
+	}
+}
+
+// Enqueue adds a task, or makes the pending task with the same ID due.
+func (o *inMemoryOutbox) Enqueue(_ context.Context, task OutboxTask) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if pending, ok := o.tasks[task.ID]; ok {
+		pending.NextAttemptAt = task.NextAttemptAt
+		return o.save(pending)
+	}
+	return o.save(task)
+}
+
+// Claim returns the task that is due the longest and hides it for the lease.
+func (o *inMemoryOutbox) Claim(_ context.Context, now time.Time, lease time.Duration) (OutboxTask, bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var due *OutboxTask
+	for _, task := range o.tasks {
+		if task.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || task.NextAttemptAt.Before(due.NextAttemptAt) {
+			task := task
+			due = &task
+		}
+	}
+	if due == nil {
+		return OutboxTask{}, false, nil
+	}
+	claimed := *due
+	due.NextAttemptAt = now.Add(lease)
+	if err := o.save(*due); err != nil {
+		return OutboxTask{}, false, err
+	}
+	return claimed, true, nil
+}
+
+// Complete removes a task.
+func (o *inMemoryOutbox) Complete(_ context.Context, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.tasks[id]; !ok {
+		return nil
+	}
+	if o.journal != nil {
+		if err := o.journal.completed(id); err != nil {
+			return err
+		}
+	}
+	delete(o.tasks, id)
+	return nil
+}
+
+// Retry records the failure of a task.
+func (o *inMemoryOutbox) Retry(_ context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	task, ok := o.tasks[id]
+	if !ok {
+		return nil
+	}
+	task.Attempts++
+	task.NextAttemptAt = nextAttemptAt
+	task.LastError = lastError
+	return o.save(task)
+}
+
+// save stores a task once persisted by the journal. The caller holds the lock.
+func (o *inMemoryOutbox) save(task OutboxTask) error {
+	if o.journal != nil {
+		if err := o.journal.saved(task); err != nil {
+			return err
+		}
+	}
+	o.tasks[task.ID] = task
+	return nil
+}
+
+// List returns the pending tasks, the oldest first.
+func (o *inMemoryOutbox) List(_ context.Context) ([]OutboxTask, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	tasks := make([]OutboxTask, 0, len(o.tasks))
+	for _, task := range o.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given an in-memory outbox", t, func() {
+		outbox := NewInMemoryOutbox()
+		first := newDeleteAuthorQuotesTask("first", "", now)
+		second := newDeleteAuthorQuotesTask("second", "", now.Add(time.Second))
+		So(outbox.Enqueue(ctx, second), ShouldBeNil)
+		So(outbox.Enqueue(ctx, first), ShouldBeNil)
+
+		Convey("The tasks should be listed from the oldest", func() {
+			tasks, err := outbox.List(ctx)
+			So(err, ShouldBeNil)
+			So(tasks, ShouldResemble, []OutboxTask{first, second})
+		})
+
+		Convey("Enqueuing a pending task again should keep a single task", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("first", "", now)), ShouldBeNil)
+			tasks, _ := outbox.List(ctx)
+			So(len(tasks), ShouldEqual, 2)
+		})
+
+		Convey("Claiming should return the due tasks once per lease", func() {
+			task, ok, err := outbox.Claim(ctx, now.Add(time.Second), time.Minute)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(task.ID, ShouldEqual, first.ID)
+			task, ok, _ = outbox.Claim(ctx, now.Add(time.Second), time.Minute)
+			So(ok, ShouldBeTrue)
+			So(task.ID, ShouldEqual, second.ID)
+			_, ok, _ = outbox.Claim(ctx, now.Add(time.Second), time.Minute)
+			So(ok, ShouldBeFalse)
+
+			_, ok, _ = outbox.Claim(ctx, now.Add(time.Minute+time.Second), time.Minute)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Claiming before the tasks are due should return nothing", func() {
+			_, ok, err := outbox.Claim(ctx, now.Add(-time.Second), time.Minute)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Retrying a task should record the failure", func() {
+			So(outbox.Retry(ctx, first.ID, now.Add(time.Hour), "unavailable"), ShouldBeNil)
+			tasks, _ := outbox.List(ctx)
+			So(tasks[0].Attempts, ShouldEqual, 1)
+			So(tasks[0].LastError, ShouldEqual, "unavailable")
+			So(tasks[0].NextAttemptAt, ShouldEqual, now.Add(time.Hour))
+		})
+
+		Convey("Completing a task should remove it", func() {
+			So(outbox.Complete(ctx, first.ID), ShouldBeNil)
+			tasks, _ := outbox.List(ctx)
+			So(tasks, ShouldResemble, []OutboxTask{second})
+		})
+	})
+
+	Convey("When computing the backoff of a task", t, func() {
+		So(outboxBackoff(0), ShouldEqual, time.Second)
+		So(outboxBackoff(1), ShouldEqual, 2*time.Second)
+		So(outboxBackoff(5), ShouldEqual, 32*time.Second)
+		So(outboxBackoff(100), ShouldEqual, maxOutboxBackoff)
+	})
+
+	Convey("When a worker runs", t, func() {
+		db := NewInMemoryDatabase()
+		clientRepository := NewFakeClientRepository()
+		outbox := NewInMemoryOutbox()
+		repo := New(db, clientRepository, WithOutbox(outbox), WithRetention(0))
+		authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName("Name").Build())
+		_ = repo.DeleteAuthor(ctx, authorID, 0)
+		_ = repo.PurgeAuthor(ctx, authorID)
+
+		workerCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			NewOutboxWorker(repo, time.Millisecond).Run(workerCtx)
+			close(done)
+		}()
+		cancel()
+		<-done
+
+		Convey("The due tasks should be processed until the context is done", func() {
+			So(clientRepository.DeletedAuthorQuotes(), ShouldResemble, []string{authorID})
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"github.com/charmbracelet/log"
+	"time"
+)
+
+// DefaultOutboxInterval is how often the outbox worker looks for due tasks.
+const DefaultOutboxInterval = 5 * time.Second
+
+// OutboxWorker runs the due outbox tasks of a repository in the background.
+type OutboxWorker struct {
+	repo     Repository
+	interval time.Duration
+}
+
+// NewOutboxWorker creates a worker looking for due tasks at each interval.
+func NewOutboxWorker(repo Repository, interval time.Duration) *OutboxWorker {
+	return &OutboxWorker{
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+// Run processes the outbox at each interval until the context is done.
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.process(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process runs the due tasks once and logs the outcome.
+func (w *OutboxWorker) process(ctx context.Context) {
+	result, err := w.repo.ProcessOutbox(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Error("unable to process the outbox: ", err)
+	}
+	if result.Completed > 0 || result.Failed > 0 {
+		log.Infof("outbox processed: %d completed, %d failed", result.Completed, result.Failed)
+	}
+}
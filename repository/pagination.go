@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+const (
+	// DefaultPageSize is the page size used when none is requested.
+	DefaultPageSize = 50
+	// MaxPageSize is the largest page size that can be requested.
+	MaxPageSize = 500
+)
+
+// PageRequest represents a request for a page of authors.
+type PageRequest struct {
+	// Size is the maximum number of authors in the page.
+	Size int
+	// Token is the opaque cursor returned with the previous page.
+	Token string
+}
+
+// AuthorPage represents a page of authors sorted by name and then by ID.
+type AuthorPage struct {
+	// Authors are the authors in the page.
+	Authors []data.Author `json:"authors"`
+	// NextPageToken is the cursor of the next page, empty on the last page.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// pageCursor is the position after which the next page starts.
+type pageCursor struct {
+	Name string `json:"n"`
+	ID   string `json:"i"`
+}
+
+// pageSize returns the requested page size bounded to the accepted range.
+func (p PageRequest) pageSize() int {
+	if p.Size <= 0 {
+		return DefaultPageSize
+	}
+	if p.Size > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Size
+}
+
+// cursor decodes the page token, returning nil for the first page.
+func (p PageRequest) cursor() (*pageCursor, error) {
+	if p.Token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(p.Token)
+	if err != nil {
+		return nil, invalidArgumentError("invalid page token %q", p.Token)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, invalidArgumentError("invalid page token %q", p.Token)
+	}
+	return &c, nil
+}
+
+// after reports whether the author is sorted after the cursor.
+func (c *pageCursor) after(author data.Author) bool {
+	if c == nil {
+		return true
+	}
+	if author.Name != c.Name {
+		return author.Name > c.Name
+	}
+	return author.ID > c.ID
+}
+
+// encodePageToken creates the token of the page that starts after the author.
+func encodePageToken(author data.Author) string {
+	raw, _ := json.Marshal(pageCursor{Name: author.Name, ID: author.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// newAuthorPage builds a page from authors fetched with one extra element,
+// used to know whether there is a next page.
+func newAuthorPage(authors []data.Author, size int) AuthorPage {
+	if len(authors) <= size {
+		return AuthorPage{Authors: authors}
+	}
+	authors = authors[:size]
+	return AuthorPage{
+		Authors:       authors,
+		NextPageToken: encodePageToken(authors[size-1]),
+	}
+}
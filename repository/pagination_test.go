@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+
+	faker "github.com/brianvoe/gofakeit/v6"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestPagination(t *testing.T) {
+	Convey("When requesting a page size", t, func() {
+		Convey("A missing size should use the default", func() {
+			So(PageRequest{}.pageSize(), ShouldEqual, DefaultPageSize)
+		})
+
+		Convey("A size bigger than the maximum should be bounded", func() {
+			So(PageRequest{Size: MaxPageSize + 1}.pageSize(), ShouldEqual, MaxPageSize)
+		})
+
+		Convey("A valid size should be kept", func() {
+			So(PageRequest{Size: 10}.pageSize(), ShouldEqual, 10)
+		})
+	})
+
+	Convey("When decoding a page token", t, func() {
+		author := data.NewAuthorBuilder().WithName(faker.Name()).Build()
+
+		Convey("An empty token should return no cursor", func() {
+			cursor, err := PageRequest{}.cursor()
+			So(err, ShouldBeNil)
+			So(cursor, ShouldBeNil)
+		})
+
+		Convey("An encoded token should return the author position", func() {
+			cursor, err := PageRequest{Token: encodePageToken(author)}.cursor()
+			So(err, ShouldBeNil)
+			So(cursor.Name, ShouldEqual, author.Name)
+			So(cursor.ID, ShouldEqual, author.ID)
+		})
+
+		Convey("An invalid token should return an error", func() {
+			_, err := PageRequest{Token: "invalid token"}.cursor()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When comparing authors with a cursor", t, func() {
+		cursor := &pageCursor{Name: "B", ID: "2"}
+
+		Convey("Authors with a greater name should be after", func() {
+			So(cursor.after(data.Author{ID: "1", Name: "C"}), ShouldBeTrue)
+		})
+
+		Convey("Authors with the same name and greater ID should be after", func() {
+			So(cursor.after(data.Author{ID: "3", Name: "B"}), ShouldBeTrue)
+		})
+
+		Convey("Authors with a smaller name or the same position should not be after", func() {
+			So(cursor.after(data.Author{ID: "3", Name: "A"}), ShouldBeFalse)
+			So(cursor.after(data.Author{ID: "2", Name: "B"}), ShouldBeFalse)
+		})
+	})
+}
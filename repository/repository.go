@@ -1,68 +1,455 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"io"
+	"time"
 )
 
 // Repository represents the repository interface.
 type Repository interface {
-	AddAuthor(author data.Author) (string, error)
-	ListAll() []data.Author
-	UpdateAuthor(author data.Author) (data.Author, error)
-	DeleteAuthor(id string) error
-	GetAuthor(id string) (data.Author, error)
+	AddAuthor(ctx context.Context, author data.Author) (string, error)
+	ListAll(ctx context.Context) []data.Author
+	ListPage(ctx context.Context, page PageRequest) (AuthorPage, error)
+	UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error)
+	PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error)
+	DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error
+	RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error)
+	ListDeleted(ctx context.Context) ([]data.Author, error)
+	PurgeAuthor(ctx context.Context, id string) error
+	PurgeExpired(ctx context.Context) ([]string, error)
+	ProcessOutbox(ctx context.Context) (OutboxResult, error)
+	ListOutbox(ctx context.Context) ([]OutboxTask, error)
+	GetAuthor(ctx context.Context, id string) (data.Author, error)
+	SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error)
+	AuthorHistory(ctx context.Context, id string) ([]AuditEntry, error)
+	ListRevisions(ctx context.Context, id string) ([]Revision, error)
+	GetRevision(ctx context.Context, id string, version int64) (Revision, error)
+	GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error)
+	RevertAuthor(ctx context.Context, id string, version int64, expectedVersion int64) (data.Author, error)
+	BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error)
+	ExportAuthors(ctx context.Context, w io.Writer, format Format) (int, error)
+	ImportAuthors(ctx context.Context, r io.Reader, format Format, opts ImportOptions) (ImportReport, error)
+	Dependencies() []Dependency
 }
 
+// DefaultRetention is how long deleted authors are kept before they can be purged.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// maxChangeAttempts is the number of times a change without an expected version
+// is attempted when the author keeps changing in between.
+const maxChangeAttempts = 5
+
 type repository struct {
 	db               Database
 	clientRepository ClientRepository
+	outbox           OutboxStore
+	audit            AuditStore
+	retention        time.Duration
+	now              func() time.Time
+}
+
+// Option configures a repository.
+type Option func(*repository)
+
+// WithRetention sets how long deleted authors are kept before they can be purged.
+func WithRetention(retention time.Duration) Option {
+	return func(r *repository) {
+		r.retention = retention
+	}
+}
+
+// WithOutbox sets the store of the outbox tasks, which should be durable and
+// stored alongside the authors.
+func WithOutbox(outbox OutboxStore) Option {
+	return func(r *repository) {
+		r.outbox = outbox
+	}
+}
+
+// WithAudit sets the store of the audit log, which should be durable.
+func WithAudit(audit AuditStore) Option {
+	return func(r *repository) {
+		r.audit = audit
+	}
+}
+
+// WithClock sets the function returning the current time, used to date the
+// deletions and to know when they can be purged.
+func WithClock(now func() time.Time) Option {
+	return func(r *repository) {
+		r.now = now
+	}
 }
 
 // AddAuthor adds a new author to the database.
-func (s *repository) AddAuthor(author data.Author) (string, error) {
-	return s.db.AddAuthor(author)
+func (s *repository) AddAuthor(ctx context.Context, author data.Author) (string, error) {
+	id, err := s.db.AddAuthor(ctx, author)
+	if err != nil {
+		return "", err
+	}
+	author.ID = id
+	author.Version = InitialVersion
+	s.record(ctx, AuditCreated, id, nil, &author)
+	return id, nil
 }
 
 // ListAll returns all authors in the database.
-func (s *repository) ListAll() []data.Author {
-	return s.db.ListAll()
+func (s *repository) ListAll(ctx context.Context) []data.Author {
+	return s.db.ListAll(ctx)
+}
+
+// ListPage returns a page of authors sorted by name.
+func (s *repository) ListPage(ctx context.Context, page PageRequest) (AuthorPage, error) {
+	return s.db.ListPage(ctx, page)
 }
 
 // UpdateAuthor updates an author in the database.
-func (s *repository) UpdateAuthor(author data.Author) (data.Author, error) {
-	return s.db.UpdateAuthor(author)
+func (s *repository) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	before, after, err := s.change(ctx, author.ID, expectedVersion, s.db.GetAuthor, func(version int64) (data.Author, error) {
+		return s.db.UpdateAuthor(ctx, author, version)
+	})
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.record(ctx, AuditUpdated, after.ID, &before, &after)
+	return after, nil
+}
+
+// PatchAuthor changes only the patched fields of an author in the database.
+func (s *repository) PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	before, after, err := s.change(ctx, id, expectedVersion, s.db.GetAuthor, func(version int64) (data.Author, error) {
+		return s.db.PatchAuthor(ctx, id, patch, version)
+	})
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.record(ctx, AuditUpdated, id, &before, &after)
+	return after, nil
+}
+
+// DeleteAuthor marks an author as deleted by the actor of the context. The
+// author and its quotes are kept until the author is purged.
+func (s *repository) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	before, after, err := s.change(ctx, id, expectedVersion, s.db.GetAuthor, func(version int64) (data.Author, error) {
+		return s.db.SoftDeleteAuthor(ctx, id, ActorFromContext(ctx), s.now().UTC(), version)
+	})
+	if err != nil {
+		return err
+	}
+	s.record(ctx, AuditDeleted, id, &before, &after)
+	return nil
+}
+
+// RestoreAuthor restores a deleted author that was not purged yet.
+func (s *repository) RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error) {
+	before, after, err := s.change(ctx, id, expectedVersion, s.db.GetDeletedAuthor, func(version int64) (data.Author, error) {
+		return s.db.RestoreAuthor(ctx, id, version)
+	})
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.record(ctx, AuditRestored, id, &before, &after)
+	return after, nil
+}
+
+// ListDeleted returns the deleted authors that were not purged yet.
+func (s *repository) ListDeleted(ctx context.Context) ([]data.Author, error) {
+	return s.db.ListDeleted(ctx, time.Time{})
 }
 
-// DeleteAuthor deletes an author from the database.
-func (s *repository) DeleteAuthor(id string) error {
-	if err := s.deleteAuthorQuotes(id); err != nil {
+// PurgeAuthor permanently deletes a deleted author, once the retention period
+// since its deletion is over. Its quotes are deleted afterwards by the outbox.
+func (s *repository) PurgeAuthor(ctx context.Context, id string) error {
+	author, err := s.db.GetDeletedAuthor(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		if _, getErr := s.db.GetAuthor(ctx, id); getErr == nil {
+			return failedPreconditionError("author %q must be deleted before being purged", id)
+		}
+	}
+	if err != nil {
 		return err
 	}
-	return s.db.DeleteAuthor(id)
+	if purgeAt := author.DeletedAt.Add(s.retention); s.now().Before(purgeAt) {
+		return failedPreconditionError("author %q cannot be purged before %s", id, purgeAt.Format(time.RFC3339))
+	}
+	return s.purge(ctx, author)
+}
+
+// PurgeExpired permanently deletes the deleted authors whose retention period is
+// over. It returns the IDs of the purged authors, and the errors of the authors
+// that could not be purged.
+func (s *repository) PurgeExpired(ctx context.Context) ([]string, error) {
+	authors, err := s.db.ListDeleted(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return nil, err
+	}
+	purged := make([]string, 0, len(authors))
+	var errs []error
+	for _, author := range authors {
+		if err := s.purge(ctx, author); err != nil {
+			Logger(ctx).Error("unable to purge author", "author_id", author.ID, "err", err)
+			errs = append(errs, err)
+			continue
+		}
+		purged = append(purged, author.ID)
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purge enqueues the deletion of the quotes of a deleted author and then
+// deletes the author, unless it was restored or changed in between. The task
+// is enqueued first so that the quotes of a purged author are always deleted,
+// and removed when the author changed since it is then kept. It is left for
+// the other failures, after which the author may have been deleted.
+func (s *repository) purge(ctx context.Context, author data.Author) error {
+	task := newDeleteAuthorQuotesTask(author.ID, RequestIDFromContext(ctx), s.now())
+	if err := s.outbox.Enqueue(ctx, task); err != nil {
+		return err
+	}
+	if err := s.db.DeleteAuthor(ctx, author.ID, author.Version); err != nil {
+		if errors.Is(err, ErrConflict) {
+			if completeErr := s.outbox.Complete(ctx, task.ID); completeErr != nil {
+				Logger(ctx).Error("unable to remove the outbox task of a failed purge", "task", task.ID, "err", completeErr)
+			}
+		}
+		return err
+	}
+	s.record(ctx, AuditPurged, author.ID, &author, nil)
+	return nil
+}
+
+// ProcessOutbox runs the outbox tasks that are due, each with the request ID
+// of the request that enqueued it. Failed tasks are retried later, waiting
+// longer after each failure.
+func (s *repository) ProcessOutbox(ctx context.Context) (OutboxResult, error) {
+	var result OutboxResult
+	for {
+		task, ok, err := s.outbox.Claim(ctx, s.now(), DefaultOutboxLease)
+		if err != nil || !ok {
+			return result, err
+		}
+		taskCtx := ctx
+		if task.RequestID != "" {
+			taskCtx = WithRequestID(ctx, task.RequestID)
+		}
+		if taskErr := s.runTask(taskCtx, task); taskErr != nil {
+			Logger(taskCtx).Error("outbox task failed", "task", task.ID, "attempts", task.Attempts+1, "err", taskErr)
+			next := s.now().Add(outboxBackoff(task.Attempts))
+			if err := s.outbox.Retry(ctx, task.ID, next, taskErr.Error()); err != nil {
+				return result, err
+			}
+			result.Failed++
+			continue
+		}
+		if err := s.outbox.Complete(ctx, task.ID); err != nil {
+			return result, err
+		}
+		result.Completed++
+	}
+}
+
+// ListOutbox returns the pending outbox tasks.
+func (s *repository) ListOutbox(ctx context.Context) ([]OutboxTask, error) {
+	return s.outbox.List(ctx)
+}
+
+// runTask runs an outbox task.
+func (s *repository) runTask(ctx context.Context, task OutboxTask) error {
+	switch task.Kind {
+	case TaskDeleteAuthorQuotes:
+		return s.cascadeAuthorDeletion(ctx, task.AuthorID)
+	default:
+		return fmt.Errorf("unknown outbox task kind %q", task.Kind)
+	}
+}
+
+// cascadeAuthorDeletion deletes the quotes of a purged author. It does nothing
+// when the author was restored instead, and fails while the author is still
+// waiting to be purged.
+func (s *repository) cascadeAuthorDeletion(ctx context.Context, id string) error {
+	if _, err := s.db.GetAuthor(ctx, id); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if _, err := s.db.GetDeletedAuthor(ctx, id); err == nil {
+		return failedPreconditionError("author %q is not purged yet", id)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return s.deleteAuthorQuotes(ctx, id)
 }
 
 // GetAuthor returns an author from the database.
-func (s *repository) GetAuthor(id string) (data.Author, error) {
-	return s.db.GetAuthor(id)
+func (s *repository) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	return s.db.GetAuthor(ctx, id)
+}
+
+// SearchAuthors returns the authors whose name matches the query.
+func (s *repository) SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error) {
+	return s.db.SearchAuthors(ctx, query, limit)
+}
+
+// AuthorHistory returns the audit log of an author, the oldest change first.
+// The history of a purged author is kept.
+func (s *repository) AuthorHistory(ctx context.Context, id string) ([]AuditEntry, error) {
+	entries, err := s.audit.History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return entries, nil
+	}
+	if _, err := s.db.GetAuthor(ctx, id); !errors.Is(err, ErrNotFound) {
+		return entries, err
+	}
+	if _, err := s.db.GetDeletedAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListRevisions returns the revisions of an author, the oldest first.
+func (s *repository) ListRevisions(ctx context.Context, id string) ([]Revision, error) {
+	return s.db.ListRevisions(ctx, id)
+}
+
+// GetRevision returns the revision of an author at the version.
+func (s *repository) GetRevision(ctx context.Context, id string, version int64) (Revision, error) {
+	return s.db.GetRevision(ctx, id, version)
+}
+
+// GetRevisionAt returns the author as it was at the time.
+func (s *repository) GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error) {
+	return s.db.GetRevisionAt(ctx, id, at)
 }
 
-// New creates a new repository.
-func New(db Database, clientRepository ClientRepository) Repository {
-	return &repository{
+// RevertAuthor replaces an author with its revision at the version, creating a
+// new revision. Deleted authors must be restored before being reverted.
+func (s *repository) RevertAuthor(ctx context.Context, id string, version int64, expectedVersion int64) (data.Author, error) {
+	revision, err := s.db.GetRevision(ctx, id, version)
+	if err != nil {
+		return data.Author{}, err
+	}
+	before, after, err := s.change(ctx, id, expectedVersion, s.db.GetAuthor, func(version int64) (data.Author, error) {
+		return s.db.UpdateAuthor(ctx, revision.Author, version)
+	})
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.record(ctx, AuditReverted, id, &before, &after)
+	return after, nil
+}
+
+// bulkAuditActions are the audit actions of the bulk operations.
+var bulkAuditActions = map[string]string{
+	BulkCreate: AuditCreated,
+	BulkUpdate: AuditUpdated,
+	BulkDelete: AuditDeleted,
+}
+
+// BulkWrite applies a batch of operations and returns the result of each of
+// them. The deletions are soft and recorded with the actor of the context.
+func (s *repository) BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	now := s.now().UTC()
+	batch := make([]BulkOperation, len(ops))
+	for index, op := range ops {
+		if op.Kind == BulkDelete {
+			op.DeletedBy = ActorFromContext(ctx)
+			op.DeletedAt = now
+		}
+		batch[index] = op
+	}
+	results, err := s.db.BulkWrite(ctx, batch, mode)
+	if err != nil {
+		return nil, err
+	}
+	for index, result := range results {
+		if result.Err == nil {
+			s.record(ctx, bulkAuditActions[batch[index].Kind], result.ID, result.Before, result.Author)
+		}
+	}
+	return results, nil
+}
+
+// change reads an author with get and applies a change to it at the version it
+// was read at, returning the author before and after the change. The change of
+// the caller is rejected when the author is not at the expected version. A
+// change without an expected version is applied again to the author read anew
+// when it changed in between, so that the author before is always the one the
+// change replaced.
+func (s *repository) change(ctx context.Context, id string, expectedVersion int64, get func(ctx context.Context, id string) (data.Author, error), apply func(version int64) (data.Author, error)) (data.Author, data.Author, error) {
+	for attempt := 1; ; attempt++ {
+		before, err := get(ctx, id)
+		if err != nil {
+			return data.Author{}, data.Author{}, err
+		}
+		if err := CheckVersion(id, before.Version, expectedVersion); err != nil {
+			return data.Author{}, data.Author{}, err
+		}
+		after, err := apply(before.Version)
+		if err == nil {
+			return before, after, nil
+		}
+		if expectedVersion != 0 || attempt >= maxChangeAttempts || !errors.Is(err, ErrConflict) {
+			return data.Author{}, data.Author{}, err
+		}
+	}
+}
+
+// record adds a change to the audit log. The change is already stored, so a
+// failure to record it is logged instead of failing the change.
+func (s *repository) record(ctx context.Context, action string, id string, before *data.Author, after *data.Author) {
+	entry := newAuditEntry(ctx, action, id, before, after, s.now().UTC())
+	if err := s.audit.Record(ctx, entry); err != nil {
+		Logger(ctx).Error("unable to record the audit entry", "author_id", id, "action", action, "err", err)
+	}
+}
+
+// ExportAuthors writes the authors in the format.
+func (s *repository) ExportAuthors(ctx context.Context, w io.Writer, format Format) (int, error) {
+	return ExportAuthors(ctx, s.db, w, format)
+}
+
+// ImportAuthors adds the authors read in the format, recording them in the
+// audit log.
+func (s *repository) ImportAuthors(ctx context.Context, r io.Reader, format Format, opts ImportOptions) (ImportReport, error) {
+	importer := newAuthorImporter(s.db, opts)
+	importer.now = s.now
+	importer.record = s.record
+	return importer.read(ctx, r, format)
+}
+
+// New creates a new repository. Deleted authors are kept for DefaultRetention
+// unless another retention is set, and the outbox and the audit log are kept in
+// memory unless other stores are set.
+func New(db Database, clientRepository ClientRepository, opts ...Option) Repository {
+	r := &repository{
 		db:               db,
 		clientRepository: clientRepository,
+		outbox:           NewInMemoryOutbox(),
+		audit:            NewInMemoryAudit(),
+		retention:        DefaultRetention,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // deleteQuotes deletes all quotes from an author.
-func (s *repository) deleteAuthorQuotes(id string) error {
-	res, err := s.clientRepository.DeleteAuthorQuotes(id)
+func (s *repository) deleteAuthorQuotes(ctx context.Context, id string) error {
+	res, err := s.clientRepository.DeleteAuthorQuotes(ctx, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("could not delete quotes from author with id %s: %v: %w", id, err, ErrDependencyFailed)
 	}
 	if !res {
-		return fmt.Errorf("could not delete quotes from author with id: %s", id)
+		return fmt.Errorf("could not delete quotes from author with id %s: %w", id, ErrDependencyFailed)
 	}
 	return nil
 }
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	faker "github.com/brianvoe/gofakeit/v6"
 	. "github.com/smartystreets/goconvey/convey"
@@ -10,11 +12,13 @@ import (
 )
 
 func TestRepository(t *testing.T) {
+	ctx := context.Background()
 
 	Convey("Given a new repository", t, func() {
 		db := NewInMemoryDatabase()
 		clientRepository := NewFakeClientRepository()
-		repo := New(db, clientRepository)
+		now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		repo := New(db, clientRepository, WithRetention(24*time.Hour), WithClock(func() time.Time { return now }))
 		fakeId := faker.UUID()
 		name := faker.Name()
 		picUrl := faker.ImageURL(100, 100)
@@ -26,19 +30,19 @@ func TestRepository(t *testing.T) {
 				WithName(name).
 				WithPicUrl(picUrl)
 			author := builder.Build()
-			id, _ := repo.AddAuthor(author)
+			id, _ := repo.AddAuthor(ctx, author)
 
 			Convey("The list of authors should contain the new author", func() {
-				So(len(repo.ListAll()), ShouldEqual, 1)
+				So(len(repo.ListAll(ctx)), ShouldEqual, 1)
 			})
 
 			Convey("Adding with same ID should fail", func() {
-				_, err := repo.AddAuthor(author)
-				So(err, ShouldNotBeNil)
+				_, err := repo.AddAuthor(ctx, author)
+				So(err, ShouldWrap, ErrAlreadyExists)
 			})
 
 			Convey("Getting the author by ID should return the correct author", func() {
-				author, _ := repo.GetAuthor(fakeId)
+				author, _ := repo.GetAuthor(ctx, fakeId)
 				So(author.ID, ShouldEqual, fakeId)
 				So(author.Name, ShouldEqual, name)
 				So(author.PicURL, ShouldEqual, picUrl)
@@ -46,12 +50,13 @@ func TestRepository(t *testing.T) {
 
 			Convey("Updating the author should return the updated author", func() {
 				newName := faker.Name()
-				author, _ := repo.UpdateAuthor(
+				author, _ := repo.UpdateAuthor(ctx,
 					builder.
 						WithId(id).
 						WithName(newName).
 						WithPicUrl(picUrl).
 						Build(),
+					0,
 				)
 				So(author.ID, ShouldEqual, id)
 				So(author.Name, ShouldNotEqual, name)
@@ -59,71 +64,328 @@ func TestRepository(t *testing.T) {
 			})
 		})
 
-		Convey("When deleting an author", func() {
-			authorID, _ := repo.AddAuthor(data.NewAuthorBuilder().WithName(name).Build())
+		Convey("When patching an author", func() {
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().
+				WithName(name).
+				WithPicUrl(picUrl).
+				Build())
 
-			Convey("Deleting the author should remove it from the list", func() {
-				if err := repo.DeleteAuthor(authorID); err != nil {
-					t.Fatal(err)
+			Convey("Only the patched fields should change", func() {
+				patch := data.AuthorPatch{
+					Author: data.Author{Biography: "A biography"},
+					Fields: []string{data.FieldBiography},
 				}
-				So(len(repo.ListAll()), ShouldEqual, 0)
+				author, err := repo.PatchAuthor(ctx, authorID, patch, 0)
+				So(err, ShouldBeNil)
+				So(author.Biography, ShouldEqual, "A biography")
+				So(author.Name, ShouldEqual, name)
+				So(author.PicURL, ShouldEqual, picUrl)
+				stored, _ := repo.GetAuthor(ctx, authorID)
+				So(stored, ShouldResemble, author)
+			})
+
+			Convey("Each change should increment the version", func() {
+				patch := data.AuthorPatch{Author: data.Author{Name: "New name"}, Fields: []string{data.FieldName}}
+				author, err := repo.PatchAuthor(ctx, authorID, patch, InitialVersion)
+				So(err, ShouldBeNil)
+				So(author.Version, ShouldEqual, 2)
+				author.Name = "Other name"
+				author, err = repo.UpdateAuthor(ctx, author, 2)
+				So(err, ShouldBeNil)
+				So(author.Version, ShouldEqual, 3)
+			})
+
+			Convey("Changing an author at another version should fail", func() {
+				patch := data.AuthorPatch{Author: data.Author{Name: "New name"}, Fields: []string{data.FieldName}}
+				_, err := repo.PatchAuthor(ctx, authorID, patch, 2)
+				So(err, ShouldWrap, ErrConflict)
+				author, _ := repo.GetAuthor(ctx, authorID)
+				_, err = repo.UpdateAuthor(ctx, author, 2)
+				So(err, ShouldWrap, ErrConflict)
+				stored, _ := repo.GetAuthor(ctx, authorID)
+				So(stored.Name, ShouldEqual, name)
+				So(stored.Version, ShouldEqual, InitialVersion)
+			})
+
+			Convey("Patching an author that does not exist should fail", func() {
+				_, err := repo.PatchAuthor(ctx, "123", data.AuthorPatch{Fields: []string{data.FieldName}}, 0)
+				So(err, ShouldWrap, ErrNotFound)
 			})
 		})
 
-		Convey("When deleting an author with errors on quote service", func() {
-			authorID, _ := repo.AddAuthor(data.NewAuthorBuilder().WithName(name).Build())
+		Convey("When deleting an author", func() {
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			clientRepository.SetDeleteAuthorQuotesReturn(false, fmt.Errorf("error"))
+			err := repo.DeleteAuthor(WithActor(ctx, "editor"), authorID, 0)
 
-			Convey("When quotes service throw error, should not delete author", func() {
-				clientRepository.SetDeleteAuthorQuotesReturn(true, fmt.Errorf("error"))
-				err := repo.DeleteAuthor(authorID)
-				So(err, ShouldNotBeNil)
-				So(len(repo.ListAll()), ShouldEqual, 1)
+			Convey("The author should be hidden without deleting its quotes", func() {
+				So(err, ShouldBeNil)
+				So(len(repo.ListAll(ctx)), ShouldEqual, 0)
+				_, err := repo.GetAuthor(ctx, authorID)
+				So(err, ShouldWrap, ErrNotFound)
 			})
 
-			Convey("When quotes service return false, should not delete author", func() {
-				clientRepository.SetDeleteAuthorQuotesReturn(false, nil)
-				err := repo.DeleteAuthor(authorID)
-				So(err, ShouldNotBeNil)
-				So(len(repo.ListAll()), ShouldEqual, 1)
+			Convey("The author should be listed as deleted by the actor", func() {
+				deleted, err := repo.ListDeleted(ctx)
+				So(err, ShouldBeNil)
+				So(len(deleted), ShouldEqual, 1)
+				So(deleted[0].ID, ShouldEqual, authorID)
+				So(*deleted[0].DeletedAt, ShouldEqual, now)
+				So(deleted[0].DeletedBy, ShouldEqual, "editor")
+			})
+
+			Convey("Restoring the author should make it visible again", func() {
+				author, err := repo.RestoreAuthor(ctx, authorID, 2)
+				So(err, ShouldBeNil)
+				So(author.IsDeleted(), ShouldBeFalse)
+				So(author.DeletedBy, ShouldBeEmpty)
+				So(author.Version, ShouldEqual, 3)
+				So(len(repo.ListAll(ctx)), ShouldEqual, 1)
+			})
+
+			Convey("Restoring the author at another version should fail", func() {
+				_, err := repo.RestoreAuthor(ctx, authorID, 1)
+				So(err, ShouldWrap, ErrConflict)
+			})
+
+			Convey("Changing the deleted author should fail", func() {
+				_, err := repo.PatchAuthor(ctx, authorID, data.AuthorPatch{Fields: []string{data.FieldName}}, 0)
+				So(err, ShouldWrap, ErrNotFound)
+				So(repo.DeleteAuthor(ctx, authorID, 0), ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Purging the author before the end of the retention should fail", func() {
+				err := repo.PurgeAuthor(ctx, authorID)
+				So(err, ShouldWrap, ErrFailedPrecondition)
+				deleted, _ := repo.ListDeleted(ctx)
+				So(len(deleted), ShouldEqual, 1)
+			})
+
+			Convey("After the retention", func() {
+				now = now.Add(24 * time.Hour)
+
+				Convey("Purging the author should delete it and then its quotes", func() {
+					So(repo.PurgeAuthor(ctx, authorID), ShouldBeNil)
+					deleted, _ := repo.ListDeleted(ctx)
+					So(len(deleted), ShouldEqual, 0)
+					_, err := repo.RestoreAuthor(ctx, authorID, 0)
+					So(err, ShouldWrap, ErrNotFound)
+					So(clientRepository.DeletedAuthorQuotes(), ShouldBeEmpty)
+
+					tasks, _ := repo.ListOutbox(ctx)
+					So(len(tasks), ShouldEqual, 1)
+					So(tasks[0].AuthorID, ShouldEqual, authorID)
+
+					clientRepository.SetDeleteAuthorQuotesReturn(true, nil)
+					result, err := repo.ProcessOutbox(ctx)
+					So(err, ShouldBeNil)
+					So(result, ShouldResemble, OutboxResult{Completed: 1})
+					So(clientRepository.DeletedAuthorQuotes(), ShouldResemble, []string{authorID})
+					tasks, _ = repo.ListOutbox(ctx)
+					So(tasks, ShouldBeEmpty)
+				})
+
+				Convey("Purging the expired authors should return their IDs", func() {
+					otherID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+					_ = repo.DeleteAuthor(ctx, otherID, 0)
+					purged, err := repo.PurgeExpired(ctx)
+					So(err, ShouldBeNil)
+					So(purged, ShouldResemble, []string{authorID})
+					deleted, _ := repo.ListDeleted(ctx)
+					So(len(deleted), ShouldEqual, 1)
+					So(deleted[0].ID, ShouldEqual, otherID)
+				})
+
+				Convey("When quotes service fails, the author should be purged and its quotes retried", func() {
+					So(repo.PurgeAuthor(ctx, authorID), ShouldBeNil)
+					result, err := repo.ProcessOutbox(ctx)
+					So(err, ShouldBeNil)
+					So(result, ShouldResemble, OutboxResult{Failed: 1})
+					tasks, _ := repo.ListOutbox(ctx)
+					So(len(tasks), ShouldEqual, 1)
+					So(tasks[0].Attempts, ShouldEqual, 1)
+					So(tasks[0].LastError, ShouldContainSubstring, "could not delete quotes")
+					So(tasks[0].NextAttemptAt, ShouldEqual, now.Add(outboxBackoff(0)))
+
+					clientRepository.SetDeleteAuthorQuotesReturn(false, nil)
+					now = now.Add(outboxBackoff(0))
+					result, _ = repo.ProcessOutbox(ctx)
+					So(result, ShouldResemble, OutboxResult{Failed: 1})
+					tasks, _ = repo.ListOutbox(ctx)
+					So(tasks[0].NextAttemptAt, ShouldEqual, now.Add(outboxBackoff(1)))
+
+					clientRepository.SetDeleteAuthorQuotesReturn(true, nil)
+					now = now.Add(outboxBackoff(1))
+					result, _ = repo.ProcessOutbox(ctx)
+					So(result, ShouldResemble, OutboxResult{Completed: 1})
+					So(clientRepository.DeletedAuthorQuotes(), ShouldResemble, []string{authorID})
+				})
+			})
+		})
+
+		Convey("When purging an author within a request", func() {
+			repo := New(db, clientRepository, WithRetention(0), WithClock(func() time.Time { return now }))
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			_ = repo.DeleteAuthor(ctx, authorID, 0)
+			So(repo.PurgeAuthor(WithRequestID(ctx, "purge-request"), authorID), ShouldBeNil)
+
+			Convey("The deletion of its quotes should run with the request ID", func() {
+				tasks, _ := repo.ListOutbox(ctx)
+				So(tasks[0].RequestID, ShouldEqual, "purge-request")
+				result, err := repo.ProcessOutbox(ctx)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, OutboxResult{Completed: 1})
+				So(clientRepository.RequestIDs(), ShouldResemble, []string{"purge-request"})
+			})
+		})
+
+		Convey("When the deletion of the quotes of an author is due", func() {
+			outbox := NewInMemoryOutbox()
+			repo := New(db, clientRepository, WithOutbox(outbox), WithClock(func() time.Time { return now }))
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			_ = outbox.Enqueue(ctx, newDeleteAuthorQuotesTask(authorID, "", now))
+
+			Convey("The quotes should be kept while the author is not purged", func() {
+				_ = repo.DeleteAuthor(ctx, authorID, 0)
+				result, err := repo.ProcessOutbox(ctx)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, OutboxResult{Failed: 1})
+				So(clientRepository.DeletedAuthorQuotes(), ShouldBeEmpty)
+			})
+
+			Convey("The task should be dropped when the author was restored", func() {
+				result, err := repo.ProcessOutbox(ctx)
+				So(err, ShouldBeNil)
+				So(result, ShouldResemble, OutboxResult{Completed: 1})
+				So(clientRepository.DeletedAuthorQuotes(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a deleted author is restored while it is purged", func() {
+			repo := New(restoringDatabase{db}, clientRepository, WithRetention(0), WithClock(func() time.Time { return now }))
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			_ = repo.DeleteAuthor(ctx, authorID, 0)
+
+			Convey("The purge should fail without leaving the deletion of its quotes", func() {
+				So(repo.PurgeAuthor(ctx, authorID), ShouldWrap, ErrConflict)
+				tasks, _ := repo.ListOutbox(ctx)
+				So(tasks, ShouldBeEmpty)
+				_, err := repo.GetAuthor(ctx, authorID)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When deleting an author at another version", func() {
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			err := repo.DeleteAuthor(ctx, authorID, 2)
+			Convey("An error should be returned", func() {
+				So(err, ShouldWrap, ErrConflict)
+				So(len(repo.ListAll(ctx)), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When purging an author that is not deleted", func() {
+			authorID, _ := repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			err := repo.PurgeAuthor(ctx, authorID)
+			Convey("An error should be returned", func() {
+				So(err, ShouldWrap, ErrFailedPrecondition)
 			})
 		})
 
 		Convey("When deleting an author that does not exist", func() {
-			err := repo.DeleteAuthor("123")
+			err := repo.DeleteAuthor(ctx, "123", 0)
 			Convey("An error should be returned", func() {
-				So(err, ShouldNotBeNil)
+				So(err, ShouldWrap, ErrNotFound)
 			})
 		})
 
 		Convey("When getting an author that does not exist", func() {
-			_, err := repo.GetAuthor("123")
+			_, err := repo.GetAuthor(ctx, "123")
 			Convey("An error should be returned", func() {
-				So(err, ShouldNotBeNil)
+				So(err, ShouldWrap, ErrNotFound)
 			})
 		})
 
 		Convey("When updating an author that does not exist", func() {
-			_, err := repo.UpdateAuthor(data.NewAuthorBuilder().Build())
+			_, err := repo.UpdateAuthor(ctx, data.NewAuthorBuilder().Build(), 0)
 			Convey("An error should be returned", func() {
-				So(err, ShouldNotBeNil)
+				So(err, ShouldWrap, ErrNotFound)
 			})
 		})
 
 		Convey("When listing all authors", func() {
-			_, _ = repo.AddAuthor(data.NewAuthorBuilder().
+			_, _ = repo.AddAuthor(ctx, data.NewAuthorBuilder().
 				WithName(name).
 				WithPicUrl(picUrl).
 				Build())
-			_, _ = repo.AddAuthor(
+			_, _ = repo.AddAuthor(ctx,
 				data.NewAuthorBuilder().
 					WithName(faker.Name()).
 					WithPicUrl(faker.ImageURL(100, 100)).
 					Build())
 
 			Convey("The list should contain all authors", func() {
-				authors := repo.ListAll()
+				authors := repo.ListAll(ctx)
 				So(len(authors), ShouldEqual, 2)
 			})
 		})
+
+		Convey("When searching authors", func() {
+			_, _ = repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName("Émile Zola").Build())
+			_, _ = repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName("Emily Dickinson").Build())
+			_, _ = repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName("Victor Hugo").Build())
+
+			Convey("The search should ignore case and diacritics", func() {
+				authors, err := repo.SearchAuthors(ctx, "emil", 10)
+				So(err, ShouldBeNil)
+				So(len(authors), ShouldEqual, 2)
+				So(authors[0].Name, ShouldEqual, "Emily Dickinson")
+				So(authors[1].Name, ShouldEqual, "Émile Zola")
+			})
+
+			Convey("An empty query should return an error", func() {
+				_, err := repo.SearchAuthors(ctx, "", 10)
+				So(err, ShouldWrap, ErrInvalidArgument)
+			})
+		})
+
+		Convey("When listing authors by page", func() {
+			for _, name := range []string{"Carl Sagan", "Ada Lovelace", "Blaise Pascal", "Ada Lovelace"} {
+				_, _ = repo.AddAuthor(ctx, data.NewAuthorBuilder().WithName(name).Build())
+			}
+
+			Convey("The pages should be sorted by name and cover all authors", func() {
+				first, err := repo.ListPage(ctx, PageRequest{Size: 3})
+				So(err, ShouldBeNil)
+				So(len(first.Authors), ShouldEqual, 3)
+				So(first.Authors[0].Name, ShouldEqual, "Ada Lovelace")
+				So(first.Authors[1].Name, ShouldEqual, "Ada Lovelace")
+				So(first.Authors[0].ID, ShouldBeLessThan, first.Authors[1].ID)
+				So(first.Authors[2].Name, ShouldEqual, "Blaise Pascal")
+				So(first.NextPageToken, ShouldNotBeEmpty)
+
+				second, err := repo.ListPage(ctx, PageRequest{Size: 3, Token: first.NextPageToken})
+				So(err, ShouldBeNil)
+				So(len(second.Authors), ShouldEqual, 1)
+				So(second.Authors[0].Name, ShouldEqual, "Carl Sagan")
+				So(second.NextPageToken, ShouldBeEmpty)
+			})
+
+			Convey("An invalid page token should return an error", func() {
+				_, err := repo.ListPage(ctx, PageRequest{Token: "invalid"})
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
+
+// restoringDatabase restores a deleted author right before it is permanently
+// deleted, as a concurrent request would.
+type restoringDatabase struct {
+	Database
+}
+
+func (db restoringDatabase) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	_, _ = db.RestoreAuthor(ctx, id, 0)
+	return db.Database.DeleteAuthor(ctx, id, expectedVersion)
+}
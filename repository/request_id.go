@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+)
+
+// RequestIDMetadata is the gRPC metadata key carrying the request ID, the
+// counterpart of the X-Request-ID HTTP header.
+const RequestIDMetadata = "x-request-id"
+
+// maxRequestIDLength bounds the length of a request ID accepted from a caller.
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context carrying the ID correlating the
+// logs of a request across the services.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the ID of the request, or an empty string when
+// the context does not carry one.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID generates a request ID.
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// ValidRequestID reports whether a request ID received from a caller can be
+// used as is: it must be short and only made of printable ASCII characters.
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Logger returns the default logger, with the request ID of the context if any.
+func Logger(ctx context.Context) *log.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return log.With("request_id", requestID)
+	}
+	return log.Default()
+}
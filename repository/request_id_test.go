@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequestID(t *testing.T) {
+	Convey("When the context carries a request ID", t, func() {
+		ctx := WithRequestID(context.Background(), "request-1")
+		So(RequestIDFromContext(ctx), ShouldEqual, "request-1")
+	})
+
+	Convey("When the context carries no request ID", t, func() {
+		So(RequestIDFromContext(context.Background()), ShouldBeEmpty)
+	})
+
+	Convey("When generating request IDs", t, func() {
+		first := NewRequestID()
+		So(ValidRequestID(first), ShouldBeTrue)
+		So(NewRequestID(), ShouldNotEqual, first)
+	})
+
+	Convey("When validating request IDs received from callers", t, func() {
+		So(ValidRequestID("5f0c6f3e-request"), ShouldBeTrue)
+		So(ValidRequestID(""), ShouldBeFalse)
+		So(ValidRequestID("with space"), ShouldBeFalse)
+		So(ValidRequestID("line\nbreak"), ShouldBeFalse)
+		So(ValidRequestID(strings.Repeat("a", maxRequestIDLength+1)), ShouldBeFalse)
+	})
+
+	Convey("When logging within a request", t, func() {
+		previous := log.Default()
+		defer log.SetDefault(previous)
+		var buf bytes.Buffer
+		log.SetDefault(log.New(&buf))
+
+		Logger(WithRequestID(context.Background(), "request-1")).Error("failed")
+		Logger(context.Background()).Error("failed")
+
+		Convey("The logs should carry the request ID", func() {
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			So(len(lines), ShouldEqual, 2)
+			So(lines[0], ShouldContainSubstring, "request_id=request-1")
+			So(lines[1], ShouldNotContainSubstring, "request_id")
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+// Revision is a snapshot of an author taken after each of its changes. The
+// revisions of an author are numbered by its versions.
+type Revision struct {
+	// Version is the version of the author after the change.
+	Version int64 `json:"version"`
+	// Time is when the change was made.
+	Time time.Time `json:"time"`
+	// Author is the author after the change.
+	Author data.Author `json:"author"`
+}
+
+// newRevision creates the revision of the author as changed at the time.
+func newRevision(author data.Author, now time.Time) Revision {
+	return Revision{Version: author.Version, Time: now, Author: author}
+}
+
+// revisionNotFoundError returns the error of a revision that does not exist.
+func revisionNotFoundError(id string, version int64) error {
+	return fmt.Errorf("revision %d of author %q does not exist: %w", version, id, ErrNotFound)
+}
+
+// revisionAtNotFoundError returns the error of an author that did not exist at the time.
+func revisionAtNotFoundError(id string, at time.Time) error {
+	return fmt.Errorf("author %q did not exist at %s: %w", id, at.Format(time.RFC3339), ErrNotFound)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestInMemoryRevisions(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given an in-memory database whose clock advances a minute per change", t, func() {
+		now := start
+		db := &inMemoryDatabase{
+			storage:   make(map[string]data.Author),
+			revisions: make(map[string][]Revision),
+			now: func() time.Time {
+				now = now.Add(time.Minute)
+				return now
+			},
+		}
+		_, err := db.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada"})
+		So(err, ShouldBeNil)
+		_, err = db.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 0)
+		So(err, ShouldBeNil)
+		_, err = db.SoftDeleteAuthor(ctx, "author", "editor", start, 0)
+		So(err, ShouldBeNil)
+
+		Convey("Each change should store a revision numbered by the version", func() {
+			revisions, err := db.ListRevisions(ctx, "author")
+			So(err, ShouldBeNil)
+			So(revisions, ShouldHaveLength, 3)
+			So(revisions[0].Version, ShouldEqual, 1)
+			So(revisions[0].Author.Name, ShouldEqual, "Ada")
+			So(revisions[0].Time, ShouldEqual, start.Add(time.Minute))
+			So(revisions[1].Author.Name, ShouldEqual, "Ada Lovelace")
+			So(revisions[2].Author.DeletedBy, ShouldEqual, "editor")
+		})
+
+		Convey("A revision should be returned by version", func() {
+			revision, err := db.GetRevision(ctx, "author", 2)
+			So(err, ShouldBeNil)
+			So(revision.Author.Name, ShouldEqual, "Ada Lovelace")
+
+			_, err = db.GetRevision(ctx, "author", 4)
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("The revision at a time should be the last one made at or before it", func() {
+			revision, err := db.GetRevisionAt(ctx, "author", start.Add(90*time.Second))
+			So(err, ShouldBeNil)
+			So(revision.Version, ShouldEqual, 1)
+			revision, err = db.GetRevisionAt(ctx, "author", start.Add(2*time.Minute))
+			So(err, ShouldBeNil)
+			So(revision.Version, ShouldEqual, 2)
+
+			_, err = db.GetRevisionAt(ctx, "author", start)
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("Deleting the author permanently should delete its revisions", func() {
+			So(db.DeleteAuthor(ctx, "author", 0), ShouldBeNil)
+			_, err := db.ListRevisions(ctx, "author")
+			So(err, ShouldWrap, ErrNotFound)
+		})
+	})
+}
+
+func TestRepositoryRevert(t *testing.T) {
+	Convey("Given an author that was renamed twice", t, func() {
+		ctx := WithActor(context.Background(), "editor")
+		repo := New(NewInMemoryDatabase(), NewFakeClientRepository())
+		_, _ = repo.AddAuthor(ctx, data.Author{ID: "author", Name: "Ada", Nationality: "British"})
+		_, _ = repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Ada Lovelace"}, 0)
+		_, _ = repo.UpdateAuthor(ctx, data.Author{ID: "author", Name: "Wrong"}, 0)
+
+		Convey("Reverting to a revision should create a new revision with its content", func() {
+			author, err := repo.RevertAuthor(ctx, "author", 1, 3)
+			So(err, ShouldBeNil)
+			So(author.Name, ShouldEqual, "Ada")
+			So(author.Nationality, ShouldEqual, "British")
+			So(author.Version, ShouldEqual, 4)
+
+			revisions, _ := repo.ListRevisions(ctx, "author")
+			So(revisions, ShouldHaveLength, 4)
+			entries, _ := repo.AuthorHistory(ctx, "author")
+			So(entries[3].Action, ShouldEqual, AuditReverted)
+			So(entries[3].Before.Name, ShouldEqual, "Wrong")
+			So(entries[3].After.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("Reverting an author changed in between should fail", func() {
+			_, err := repo.RevertAuthor(ctx, "author", 1, 2)
+			So(err, ShouldWrap, ErrConflict)
+		})
+
+		Convey("Reverting to an unknown revision should fail", func() {
+			_, err := repo.RevertAuthor(ctx, "author", 9, 0)
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("Reverting a deleted author should fail until it is restored", func() {
+			So(repo.DeleteAuthor(ctx, "author", 0), ShouldBeNil)
+			_, err := repo.RevertAuthor(ctx, "author", 1, 0)
+			So(err, ShouldWrap, ErrNotFound)
+		})
+	})
+}
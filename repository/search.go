@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"github.com/wcodesoft/mosha-author-service/data"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultSearchLimit is the number of results returned when no limit is requested.
+	DefaultSearchLimit = 10
+	// MaxSearchLimit is the largest number of results that can be requested.
+	MaxSearchLimit = 100
+)
+
+// Rank of a name matching a search query, lower is better.
+const (
+	matchPrefix = iota
+	matchWordPrefix
+	matchSubstring
+	noMatch
+)
+
+// searchLimit returns the requested limit bounded to the accepted range.
+func searchLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		return MaxSearchLimit
+	}
+	return limit
+}
+
+// searchQuery returns the trimmed query or an error when it is blank.
+func searchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", invalidArgumentError("search query is empty")
+	}
+	return query, nil
+}
+
+// foldName lower cases the name and removes its diacritics.
+func foldName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, name)
+	if err != nil {
+		folded = name
+	}
+	return strings.ToLower(folded)
+}
+
+// matchRank returns how well a folded name matches a folded query.
+func matchRank(name string, query string) int {
+	if strings.HasPrefix(name, query) {
+		return matchPrefix
+	}
+	for _, word := range strings.Fields(name) {
+		if strings.HasPrefix(word, query) {
+			return matchWordPrefix
+		}
+	}
+	if strings.Contains(name, query) {
+		return matchSubstring
+	}
+	return noMatch
+}
+
+// rankAuthors returns the authors matching the query, best matches first.
+func rankAuthors(authors []data.Author, query string, limit int) []data.Author {
+	type rankedAuthor struct {
+		author data.Author
+		rank   int
+	}
+	query = foldName(query)
+	var ranked []rankedAuthor
+	for _, author := range authors {
+		if rank := matchRank(foldName(author.Name), query); rank != noMatch {
+			ranked = append(ranked, rankedAuthor{author: author, rank: rank})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].rank < ranked[j].rank
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	results := make([]data.Author, len(ranked))
+	for index, v := range ranked {
+		results[index] = v.author
+	}
+	return results
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestSearch(t *testing.T) {
+	Convey("When folding names", t, func() {
+		So(foldName("Émile Zola"), ShouldEqual, "emile zola")
+		So(foldName("Søren Kierkegaard"), ShouldEqual, "søren kierkegaard")
+		So(foldName("FRIEDRICH NIETZSCHE"), ShouldEqual, "friedrich nietzsche")
+	})
+
+	Convey("When ranking a name against a query", t, func() {
+		So(matchRank("emile zola", "emi"), ShouldEqual, matchPrefix)
+		So(matchRank("emile zola", "zo"), ShouldEqual, matchWordPrefix)
+		So(matchRank("emile zola", "ola"), ShouldEqual, matchSubstring)
+		So(matchRank("emile zola", "victor"), ShouldEqual, noMatch)
+	})
+
+	Convey("When ranking authors", t, func() {
+		authors := []data.Author{
+			{ID: "1", Name: "Anna Karenina"},
+			{ID: "2", Name: "Karen Blixen"},
+			{ID: "3", Name: "Zola Karénine"},
+			{ID: "4", Name: "Victor Hugo"},
+		}
+
+		Convey("The best matches should come first", func() {
+			results := rankAuthors(authors, "KAREN", 10)
+			So(len(results), ShouldEqual, 3)
+			So(results[0].ID, ShouldEqual, "2")
+			So(results[1].ID, ShouldEqual, "1")
+			So(results[2].ID, ShouldEqual, "3")
+		})
+
+		Convey("The results should be limited", func() {
+			So(len(rankAuthors(authors, "karen", 1)), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When validating the search arguments", t, func() {
+		_, err := searchQuery("   ")
+		So(err, ShouldNotBeNil)
+		query, err := searchQuery(" hugo ")
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "hugo")
+		So(searchLimit(0), ShouldEqual, DefaultSearchLimit)
+		So(searchLimit(MaxSearchLimit+1), ShouldEqual, MaxSearchLimit)
+		So(searchLimit(5), ShouldEqual, 5)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"time"
+)
+
+// sqlAuditStatements are the prepared statements of a SQL audit store.
+type sqlAuditStatements struct {
+	record  *sql.Stmt
+	history *sql.Stmt
+}
+
+type sqlAudit struct {
+	stmts sqlAuditStatements
+}
+
+// NewSQLAudit creates an audit store in the author_audit table created by
+// MigrateSQL, and prepares its statements.
+func NewSQLAudit(ctx context.Context, db *sql.DB, dialect SQLDialect) (AuditStore, error) {
+	if _, err := dialect.driver(); err != nil {
+		return nil, err
+	}
+	s := &sqlAudit{}
+	err := prepareQueries(ctx, db, dialect, []sqlQuery{
+		{&s.stmts.record, "INSERT INTO author_audit (id, authorid, action, actor, requestid, recordedat, authorbefore, authorafter) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"},
+		{&s.stmts.history, "SELECT id, authorid, action, actor, requestid, recordedat, authorbefore, authorafter FROM author_audit WHERE authorid = ? ORDER BY recordedat, seq"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record adds an entry to the SQL audit log. The authors before and after the
+// change are stored as JSON.
+func (s *sqlAudit) Record(ctx context.Context, entry AuditEntry) error {
+	before, err := jsonColumn(entry.Before, entry.Before == nil)
+	if err != nil {
+		return err
+	}
+	after, err := jsonColumn(entry.After, entry.After == nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.stmts.record.ExecContext(ctx, entry.ID, entry.AuthorID, entry.Action, entry.Actor, entry.RequestID,
+		entry.Time.UnixNano(), before, after)
+	return err
+}
+
+// History returns the entries of an author from the SQL audit log, the oldest
+// first and then in the order they were recorded.
+func (s *sqlAudit) History(ctx context.Context, authorID string) ([]AuditEntry, error) {
+	rows, err := s.stmts.history.QueryContext(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := make([]AuditEntry, 0)
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// scanAuditEntry reads an entry from its columns.
+func scanAuditEntry(row rowScanner) (AuditEntry, error) {
+	var entry AuditEntry
+	var recordedAt int64
+	var before, after sql.NullString
+	err := row.Scan(&entry.ID, &entry.AuthorID, &entry.Action, &entry.Actor, &entry.RequestID, &recordedAt, &before, &after)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	columns := []struct {
+		raw    sql.NullString
+		author **data.Author
+	}{
+		{before, &entry.Before},
+		{after, &entry.After},
+	}
+	for _, column := range columns {
+		if !column.raw.Valid {
+			continue
+		}
+		if err := json.Unmarshal([]byte(column.raw.String), column.author); err != nil {
+			return AuditEntry{}, fmt.Errorf("invalid audit entry %q: %w", entry.ID, err)
+		}
+	}
+	entry.Time = time.Unix(0, recordedAt).UTC()
+	return entry, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestSQLAudit(t *testing.T) {
+	ctx := WithRequestID(WithActor(context.Background(), "editor"), "request")
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a SQLite audit store", t, func() {
+		_, db := openSQLite(t.TempDir())
+		Reset(func() { _ = db.Close() })
+		audit, err := NewSQLAudit(ctx, db, DialectSQLite)
+		So(err, ShouldBeNil)
+
+		Convey("The history of an author should list its entries from the oldest", func() {
+			author := data.Author{ID: "ada", Name: "Ada", Occupations: []string{"mathematician"}, Version: InitialVersion}
+			later := newAuditEntry(ctx, AuditDeleted, "ada", &author, &author, now.Add(time.Minute))
+			earlier := newAuditEntry(ctx, AuditCreated, "ada", nil, &author, now)
+			So(audit.Record(ctx, later), ShouldBeNil)
+			So(audit.Record(ctx, earlier), ShouldBeNil)
+			So(audit.Record(ctx, newAuditEntry(ctx, AuditCreated, "grace", nil, &author, now)), ShouldBeNil)
+
+			entries, err := audit.History(ctx, "ada")
+			So(err, ShouldBeNil)
+			So(entries, ShouldResemble, []AuditEntry{earlier, later})
+		})
+
+		Convey("The history of an unknown author should be empty", func() {
+			entries, err := audit.History(ctx, "unknown")
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+
+		Convey("A repository should record its changes in it", func() {
+			database, _ := NewSQLDatabase(ctx, db, DialectSQLite)
+			repo := New(database, NewFakeClientRepository(), WithAudit(audit), WithClock(func() time.Time { return now }))
+			_, _ = repo.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			_, _ = repo.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada Lovelace"}, 0)
+			entries, err := repo.AuthorHistory(ctx, "ada")
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 2)
+			So(entries[1].Before.Name, ShouldEqual, "Ada")
+			So(entries[1].After.Name, ShouldEqual, "Ada Lovelace")
+			So(entries[1].Actor, ShouldEqual, "editor")
+		})
+	})
+
+	Convey("When creating an audit store of an unknown dialect", t, func() {
+		_, err := NewSQLAudit(ctx, nil, "oracle")
+		So(err, ShouldWrap, ErrInvalidArgument)
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"sort"
+)
+
+// errBatchRolledBack rolls back the transaction of a batch leaving nothing to commit.
+var errBatchRolledBack = errors.New("batch rolled back")
+
+// BulkWrite applies the operations of a batch to the SQL database in a single
+// transaction. The rows of the changed authors are locked while the batch is
+// planned and written, and an atomic batch is rolled back when an operation fails.
+func (s *sqlDatabase) BulkWrite(ctx context.Context, ops []BulkOperation, mode BulkMode) ([]BulkResult, error) {
+	var results []BulkResult
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		current, err := s.lockAuthors(ctx, tx, ops)
+		if err != nil {
+			return err
+		}
+		results = planBulk(ops, current, mode)
+		if mode == BulkAtomic && bulkFailed(results) {
+			return errBatchRolledBack
+		}
+		written := 0
+		for index, result := range results {
+			if result.Err != nil {
+				continue
+			}
+			if result.Before == nil {
+				err = s.insertAuthor(ctx, tx, *result.Author)
+			} else {
+				err = s.replaceAuthor(ctx, tx, *result.Author, result.Before.Version)
+			}
+			if errors.Is(err, ErrAlreadyExists) || errors.Is(err, ErrConflict) {
+				results[index] = BulkResult{ID: result.ID, Err: err}
+				if mode == BulkAtomic {
+					abortBulk(results)
+					return errBatchRolledBack
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			written++
+		}
+		if written == 0 {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRolledBack) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// lockAuthors returns the stored authors changed by the operations, by ID,
+// locked until the end of the transaction. The rows are locked in the order of
+// their IDs so that concurrent batches do not deadlock.
+func (s *sqlDatabase) lockAuthors(ctx context.Context, tx *sql.Tx, ops []BulkOperation) (map[string]data.Author, error) {
+	ids := make([]string, len(ops))
+	for index, op := range ops {
+		ids[index] = op.AuthorID()
+	}
+	sort.Strings(ids)
+	current := make(map[string]data.Author, len(ids))
+	for index, id := range ids {
+		if index > 0 && ids[index-1] == id {
+			continue
+		}
+		author, err := s.lockAuthor(ctx, tx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		current[id] = author
+	}
+	return current, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestSQLBulkWrite(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a SQLite database with two authors", t, func() {
+		database, db := openSQLite(t.TempDir())
+		Reset(func() { _ = db.Close() })
+		_, _ = database.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+		_, _ = database.AddAuthor(ctx, data.Author{ID: "alan", Name: "Alan"})
+
+		Convey("An atomic batch of valid operations should apply all of them", func() {
+			results, err := database.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 1},
+				{Kind: BulkDelete, ID: "alan", DeletedBy: "editor", DeletedAt: time.Now()},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			for _, result := range results {
+				So(result.Err, ShouldBeNil)
+			}
+			So(results[1].Before.Name, ShouldEqual, "Ada")
+			So(results[1].Author.Version, ShouldEqual, 2)
+
+			So(database.ListAll(ctx), ShouldHaveLength, 2)
+			deleted, err := database.GetDeletedAuthor(ctx, "alan")
+			So(err, ShouldBeNil)
+			So(deleted.DeletedBy, ShouldEqual, "editor")
+			revisions, _ := database.ListRevisions(ctx, "ada")
+			So(revisions, ShouldHaveLength, 2)
+			revisions, _ = database.ListRevisions(ctx, "grace")
+			So(revisions, ShouldHaveLength, 1)
+		})
+
+		Convey("An atomic batch with a failed operation should apply none of them", func() {
+			results, err := database.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "grace", Name: "Grace"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "ada", Name: "Ada Lovelace"}, ExpectedVersion: 3},
+			}, BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, ErrAborted)
+			So(results[1].Err, ShouldWrap, ErrConflict)
+			_, err = database.GetAuthor(ctx, "grace")
+			So(err, ShouldWrap, ErrNotFound)
+			author, _ := database.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("A best effort batch should apply the operations that succeed", func() {
+			results, err := database.BulkWrite(ctx, []BulkOperation{
+				{Kind: BulkCreate, Author: data.Author{ID: "ada", Name: "Ada"}},
+				{Kind: BulkDelete, ID: "missing"},
+				{Kind: BulkUpdate, Author: data.Author{ID: "alan", Name: "Alan Turing"}},
+				{Kind: BulkUpdate, Author: data.Author{ID: "alan", Name: "Alan"}},
+			}, BulkBestEffort)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, ErrAlreadyExists)
+			So(results[1].Err, ShouldWrap, ErrNotFound)
+			So(results[2].Err, ShouldBeNil)
+			So(results[3].Err, ShouldWrap, ErrInvalidArgument)
+			author, _ := database.GetAuthor(ctx, "alan")
+			So(author.Name, ShouldEqual, "Alan Turing")
+		})
+	})
+}
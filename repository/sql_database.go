@@ -0,0 +1,635 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/wcodesoft/mosha-author-service/data"
+	_ "modernc.org/sqlite"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLDialect is the SQL database system storing the authors.
+type SQLDialect string
+
+const (
+	// DialectPostgres stores the authors in PostgreSQL.
+	DialectPostgres SQLDialect = "postgres"
+	// DialectSQLite stores the authors in SQLite.
+	DialectSQLite SQLDialect = "sqlite"
+)
+
+// driver returns the name of the database/sql driver of the dialect.
+func (d SQLDialect) driver() (string, error) {
+	switch d {
+	case DialectPostgres:
+		return "pgx", nil
+	case DialectSQLite:
+		return "sqlite", nil
+	default:
+		return "", invalidArgumentError("unknown SQL dialect %q", d)
+	}
+}
+
+// rebind replaces the ? placeholders of the query by the numbered ones of
+// PostgreSQL. The queries of the package have no ? in their literals.
+func (d SQLDialect) rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	count := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		count++
+		b.WriteString("$" + strconv.Itoa(count))
+	}
+	return b.String()
+}
+
+// lockRows returns the clause locking the selected rows until the end of the
+// transaction. SQLite locks the whole database instead.
+func (d SQLDialect) lockRows() string {
+	if d == DialectPostgres {
+		return " FOR UPDATE"
+	}
+	return ""
+}
+
+// textKey returns the type of the text columns sorted and compared byte by
+// byte, as in the other databases.
+func (d SQLDialect) textKey() string {
+	if d == DialectPostgres {
+		return `TEXT COLLATE "C"`
+	}
+	return "TEXT"
+}
+
+// serialKey returns the type of an integer primary key numbering the rows in
+// the order they are inserted.
+func (d SQLDialect) serialKey() string {
+	if d == DialectPostgres {
+		return "BIGSERIAL PRIMARY KEY"
+	}
+	return "INTEGER PRIMARY KEY AUTOINCREMENT"
+}
+
+// OpenSQL opens the SQL database of the data source name, such as
+// postgres://localhost:5432/mosha or a SQLite file. SQLite databases are used
+// through a single connection, so that their transactions do not contend.
+func OpenSQL(dialect SQLDialect, dsn string) (*sql.DB, error) {
+	driver, err := dialect.driver()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if dialect == DialectSQLite {
+		db.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// authorColumns are the columns an author is read from, in the order of scanAuthor.
+const authorColumns = "id, name, picurl, biography, birthdate, deathdate, nationality, occupations, alternatenames, version, deletedat, deletedby"
+
+// sqlStatements are the prepared statements of a SQL database.
+type sqlStatements struct {
+	insertAuthor    *sql.Stmt
+	selectAuthor    *sql.Stmt
+	lockAuthor      *sql.Stmt
+	updateAuthor    *sql.Stmt
+	deleteAuthor    *sql.Stmt
+	listAuthors     *sql.Stmt
+	listPage        *sql.Stmt
+	listPageAfter   *sql.Stmt
+	listDeleted     *sql.Stmt
+	listDeletedTo   *sql.Stmt
+	searchAuthors   *sql.Stmt
+	insertRevision  *sql.Stmt
+	listRevisions   *sql.Stmt
+	selectRevision  *sql.Stmt
+	revisionAt      *sql.Stmt
+	deleteRevisions *sql.Stmt
+}
+
+type sqlDatabase struct {
+	db      *sql.DB
+	dialect SQLDialect
+	stmts   sqlStatements
+	now     func() time.Time
+}
+
+// SQLOption configures a SQL database.
+type SQLOption func(*sqlDatabase)
+
+// WithSQLClock sets the function returning the time of the revisions.
+func WithSQLClock(now func() time.Time) SQLOption {
+	return func(s *sqlDatabase) {
+		s.now = now
+	}
+}
+
+// NewSQLDatabase creates a database storing the authors and their revisions in
+// the tables created by MigrateSQL, and prepares its statements.
+func NewSQLDatabase(ctx context.Context, db *sql.DB, dialect SQLDialect, opts ...SQLOption) (Database, error) {
+	if _, err := dialect.driver(); err != nil {
+		return nil, err
+	}
+	s := &sqlDatabase{db: db, dialect: dialect, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.prepare(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// prepare prepares the statements of the database.
+func (s *sqlDatabase) prepare(ctx context.Context) error {
+	selectAuthor := "SELECT " + authorColumns + " FROM authors WHERE id = ?"
+	listActive := "SELECT " + authorColumns + " FROM authors WHERE deletedat IS NULL"
+	listDeleted := "SELECT " + authorColumns + " FROM authors WHERE deletedat IS NOT NULL"
+	revisionColumns := "SELECT version, revisedat, author FROM author_revisions WHERE authorid = ?"
+	return prepareQueries(ctx, s.db, s.dialect, []sqlQuery{
+		{&s.stmts.insertAuthor, "INSERT INTO authors (" + authorColumns + ", searchname) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING"},
+		{&s.stmts.selectAuthor, selectAuthor},
+		{&s.stmts.lockAuthor, selectAuthor + s.dialect.lockRows()},
+		{&s.stmts.updateAuthor, "UPDATE authors SET name = ?, picurl = ?, biography = ?, birthdate = ?, deathdate = ?, nationality = ?, occupations = ?, alternatenames = ?, version = ?, deletedat = ?, deletedby = ?, searchname = ? WHERE id = ? AND version = ?"},
+		{&s.stmts.deleteAuthor, "DELETE FROM authors WHERE id = ?"},
+		{&s.stmts.listAuthors, listActive + " ORDER BY name, id"},
+		{&s.stmts.listPage, listActive + " ORDER BY name, id LIMIT ?"},
+		{&s.stmts.listPageAfter, listActive + " AND (name > ? OR (name = ? AND id > ?)) ORDER BY name, id LIMIT ?"},
+		{&s.stmts.listDeleted, listDeleted + " ORDER BY deletedat, id"},
+		{&s.stmts.listDeletedTo, listDeleted + " AND deletedat <= ? ORDER BY deletedat, id"},
+		{&s.stmts.searchAuthors, listActive + ` AND searchname LIKE ? ESCAPE '\' ORDER BY name, id`},
+		{&s.stmts.insertRevision, "INSERT INTO author_revisions (authorid, version, revisedat, author) VALUES (?, ?, ?, ?)"},
+		{&s.stmts.listRevisions, revisionColumns + " ORDER BY version"},
+		{&s.stmts.selectRevision, revisionColumns + " AND version = ?"},
+		{&s.stmts.revisionAt, revisionColumns + " AND revisedat <= ? ORDER BY revisedat DESC, version DESC LIMIT 1"},
+		{&s.stmts.deleteRevisions, "DELETE FROM author_revisions WHERE authorid = ?"},
+	})
+}
+
+// sqlQuery is a query prepared into a statement.
+type sqlQuery struct {
+	stmt  **sql.Stmt
+	query string
+}
+
+// prepareQueries prepares the queries in the dialect into their statements.
+func prepareQueries(ctx context.Context, db *sql.DB, dialect SQLDialect, queries []sqlQuery) error {
+	for _, q := range queries {
+		stmt, err := db.PrepareContext(ctx, dialect.rebind(q.query))
+		if err != nil {
+			return fmt.Errorf("unable to prepare %q: %w", q.query, err)
+		}
+		*q.stmt = stmt
+	}
+	return nil
+}
+
+// AddAuthor adds an author to the SQL database.
+func (s *sqlDatabase) AddAuthor(ctx context.Context, author data.Author) (string, error) {
+	author.Version = InitialVersion
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		return s.insertAuthor(ctx, tx, author)
+	})
+	if err != nil {
+		return "", err
+	}
+	return author.ID, nil
+}
+
+// ListAll returns all authors in the SQL database that are not deleted.
+func (s *sqlDatabase) ListAll(ctx context.Context) []data.Author {
+	authors, err := s.queryAuthors(ctx, s.stmts.listAuthors)
+	if err != nil {
+		return []data.Author{}
+	}
+	return authors
+}
+
+// ListPage returns a page of authors in the SQL database sorted by name and then by ID.
+func (s *sqlDatabase) ListPage(ctx context.Context, page PageRequest) (AuthorPage, error) {
+	cursor, err := page.cursor()
+	if err != nil {
+		return AuthorPage{}, err
+	}
+	size := page.pageSize()
+	var authors []data.Author
+	if cursor == nil {
+		authors, err = s.queryAuthors(ctx, s.stmts.listPage, size+1)
+	} else {
+		authors, err = s.queryAuthors(ctx, s.stmts.listPageAfter, cursor.Name, cursor.Name, cursor.ID, size+1)
+	}
+	if err != nil {
+		return AuthorPage{}, err
+	}
+	return newAuthorPage(authors, size), nil
+}
+
+// UpdateAuthor updates an author in the SQL database.
+func (s *sqlDatabase) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	return s.changeAuthor(ctx, author.ID, func(stored data.Author) (data.Author, error) {
+		if err := checkActive(stored, expectedVersion); err != nil {
+			return data.Author{}, err
+		}
+		author.DeletedAt = nil
+		author.DeletedBy = ""
+		return author, nil
+	})
+}
+
+// PatchAuthor changes only the patched fields of an author in the SQL database.
+func (s *sqlDatabase) PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	for _, field := range patch.Fields {
+		if _, ok := authorDBFields[field]; !ok {
+			return data.Author{}, invalidArgumentError("field %q cannot be patched", field)
+		}
+	}
+	return s.changeAuthor(ctx, id, func(stored data.Author) (data.Author, error) {
+		if err := checkActive(stored, expectedVersion); err != nil {
+			return data.Author{}, err
+		}
+		return patch.Apply(stored), nil
+	})
+}
+
+// SoftDeleteAuthor marks an author of the SQL database as deleted.
+func (s *sqlDatabase) SoftDeleteAuthor(ctx context.Context, id string, deletedBy string, deletedAt time.Time, expectedVersion int64) (data.Author, error) {
+	return s.changeAuthor(ctx, id, func(stored data.Author) (data.Author, error) {
+		if err := checkActive(stored, expectedVersion); err != nil {
+			return data.Author{}, err
+		}
+		deletedAt := deletedAt.UTC()
+		stored.DeletedAt = &deletedAt
+		stored.DeletedBy = deletedBy
+		return stored, nil
+	})
+}
+
+// RestoreAuthor clears the deletion of a deleted author of the SQL database.
+func (s *sqlDatabase) RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error) {
+	return s.changeAuthor(ctx, id, func(stored data.Author) (data.Author, error) {
+		if !stored.IsDeleted() {
+			return data.Author{}, notFoundError(id)
+		}
+		if err := CheckVersion(id, stored.Version, expectedVersion); err != nil {
+			return data.Author{}, err
+		}
+		stored.DeletedAt = nil
+		stored.DeletedBy = ""
+		return stored, nil
+	})
+}
+
+// changeAuthor locks the stored author, whatever its state, and stores the
+// author returned by the change at the next version along with its revision.
+// The change returns why the author cannot be changed, if so.
+func (s *sqlDatabase) changeAuthor(ctx context.Context, id string, change func(stored data.Author) (data.Author, error)) (data.Author, error) {
+	var changed data.Author
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		stored, err := s.lockAuthor(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		changed, err = change(stored)
+		if err != nil {
+			return err
+		}
+		changed.ID = id
+		changed.Version = stored.Version + 1
+		return s.replaceAuthor(ctx, tx, changed, stored.Version)
+	})
+	if err != nil {
+		return data.Author{}, err
+	}
+	return changed, nil
+}
+
+// DeleteAuthor permanently deletes an author and its revisions from the SQL database.
+func (s *sqlDatabase) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		stored, err := s.lockAuthor(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := CheckVersion(id, stored.Version, expectedVersion); err != nil {
+			return err
+		}
+		if _, err := tx.StmtContext(ctx, s.stmts.deleteRevisions).ExecContext(ctx, id); err != nil {
+			return err
+		}
+		_, err = tx.StmtContext(ctx, s.stmts.deleteAuthor).ExecContext(ctx, id)
+		return err
+	})
+}
+
+// GetAuthor returns an author that is not deleted from the SQL database.
+func (s *sqlDatabase) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	author, err := scanAuthor(s.stmts.selectAuthor.QueryRowContext(ctx, id))
+	if err == nil && author.IsDeleted() {
+		return data.Author{}, notFoundError(id)
+	}
+	return author, notFound(id, err)
+}
+
+// GetDeletedAuthor returns a deleted author from the SQL database.
+func (s *sqlDatabase) GetDeletedAuthor(ctx context.Context, id string) (data.Author, error) {
+	author, err := scanAuthor(s.stmts.selectAuthor.QueryRowContext(ctx, id))
+	if err == nil && !author.IsDeleted() {
+		return data.Author{}, notFoundError(id)
+	}
+	return author, notFound(id, err)
+}
+
+// ListDeleted returns the authors deleted before the time, or all deleted
+// authors when the time is zero, from the oldest deletion to the newest.
+func (s *sqlDatabase) ListDeleted(ctx context.Context, deletedBefore time.Time) ([]data.Author, error) {
+	if deletedBefore.IsZero() {
+		return s.queryAuthors(ctx, s.stmts.listDeleted)
+	}
+	return s.queryAuthors(ctx, s.stmts.listDeletedTo, deletedBefore.UnixNano())
+}
+
+// SearchAuthors returns the authors whose name starts with or contains the
+// query, ignoring case and diacritics, using the folded names stored along the authors.
+func (s *sqlDatabase) SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error) {
+	query, err := searchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	authors, err := s.queryAuthors(ctx, s.stmts.searchAuthors, "%"+escaper.Replace(foldName(query))+"%")
+	if err != nil {
+		return nil, err
+	}
+	return rankAuthors(authors, query, searchLimit(limit)), nil
+}
+
+// ListRevisions returns the revisions of an author from the SQL database, the oldest first.
+func (s *sqlDatabase) ListRevisions(ctx context.Context, id string) ([]Revision, error) {
+	rows, err := s.stmts.listRevisions.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var revisions []Revision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, notFoundError(id)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns the revision of an author at the version from the SQL database.
+func (s *sqlDatabase) GetRevision(ctx context.Context, id string, version int64) (Revision, error) {
+	revision, err := scanRevision(s.stmts.selectRevision.QueryRowContext(ctx, id, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Revision{}, revisionNotFoundError(id, version)
+	}
+	return revision, err
+}
+
+// GetRevisionAt returns the last revision of an author made at or before the
+// time from the SQL database.
+func (s *sqlDatabase) GetRevisionAt(ctx context.Context, id string, at time.Time) (Revision, error) {
+	revision, err := scanRevision(s.stmts.revisionAt.QueryRowContext(ctx, id, at.UnixNano()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Revision{}, revisionAtNotFoundError(id, at)
+	}
+	return revision, err
+}
+
+// Ping checks that the SQL database answers.
+func (s *sqlDatabase) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// inTx runs the function in a transaction, committed when it succeeds.
+func (s *sqlDatabase) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockAuthor returns the stored author whatever its state, locked until the
+// end of the transaction.
+func (s *sqlDatabase) lockAuthor(ctx context.Context, tx *sql.Tx, id string) (data.Author, error) {
+	author, err := scanAuthor(tx.StmtContext(ctx, s.stmts.lockAuthor).QueryRowContext(ctx, id))
+	return author, notFound(id, err)
+}
+
+// insertAuthor inserts a new author along with its first revision.
+func (s *sqlDatabase) insertAuthor(ctx context.Context, tx *sql.Tx, author data.Author) error {
+	values, err := authorValues(author)
+	if err != nil {
+		return err
+	}
+	result, err := tx.StmtContext(ctx, s.stmts.insertAuthor).ExecContext(ctx, append([]any{author.ID}, values...)...)
+	if err != nil {
+		return err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted == 0 {
+		return alreadyExistsError(author.ID)
+	}
+	return s.insertRevision(ctx, tx, author)
+}
+
+// replaceAuthor replaces the author stored at the previous version along with
+// its revision.
+func (s *sqlDatabase) replaceAuthor(ctx context.Context, tx *sql.Tx, author data.Author, previousVersion int64) error {
+	values, err := authorValues(author)
+	if err != nil {
+		return err
+	}
+	result, err := tx.StmtContext(ctx, s.stmts.updateAuthor).ExecContext(ctx, append(values, author.ID, previousVersion)...)
+	if err != nil {
+		return err
+	}
+	replaced, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if replaced == 0 {
+		return fmt.Errorf("author %q changed since version %d: %w", author.ID, previousVersion, ErrConflict)
+	}
+	return s.insertRevision(ctx, tx, author)
+}
+
+// insertRevision stores the revision of a changed author.
+func (s *sqlDatabase) insertRevision(ctx context.Context, tx *sql.Tx, author data.Author) error {
+	revision := newRevision(author, s.now().UTC())
+	raw, err := json.Marshal(revision.Author)
+	if err != nil {
+		return err
+	}
+	_, err = tx.StmtContext(ctx, s.stmts.insertRevision).ExecContext(ctx, author.ID, revision.Version, revision.Time.UnixNano(), string(raw))
+	return err
+}
+
+// queryAuthors returns the authors selected by the statement.
+func (s *sqlDatabase) queryAuthors(ctx context.Context, stmt *sql.Stmt, args ...any) ([]data.Author, error) {
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	authors := make([]data.Author, 0)
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, author)
+	}
+	return authors, rows.Err()
+}
+
+// checkActive returns why a stored author that is deleted or at another
+// version than the expected one cannot be changed.
+func checkActive(stored data.Author, expectedVersion int64) error {
+	if stored.IsDeleted() {
+		return notFoundError(stored.ID)
+	}
+	return CheckVersion(stored.ID, stored.Version, expectedVersion)
+}
+
+// notFound returns the error of an author that does not exist when no row was found.
+func notFound(id string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFoundError(id)
+	}
+	return err
+}
+
+// rowScanner is a row read from a SQL database.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// authorValues returns the values of the columns of the author after its ID,
+// in the order of authorColumns, followed by its folded name. The dates and the
+// lists are stored as JSON, and the times as nanoseconds since the Unix epoch
+// so that every dialect compares them alike.
+func authorValues(author data.Author) ([]any, error) {
+	columns := []struct {
+		value  any
+		absent bool
+	}{
+		{author.BirthDate, author.BirthDate == nil},
+		{author.DeathDate, author.DeathDate == nil},
+		{author.Occupations, author.Occupations == nil},
+		{author.AlternateNames, author.AlternateNames == nil},
+	}
+	encoded := make([]sql.NullString, len(columns))
+	for index, column := range columns {
+		raw, err := jsonColumn(column.value, column.absent)
+		if err != nil {
+			return nil, err
+		}
+		encoded[index] = raw
+	}
+	var deletedAt sql.NullInt64
+	if author.DeletedAt != nil {
+		deletedAt = sql.NullInt64{Int64: author.DeletedAt.UnixNano(), Valid: true}
+	}
+	return []any{
+		author.Name, author.PicURL, author.Biography, encoded[0], encoded[1], author.Nationality, encoded[2], encoded[3],
+		author.Version, deletedAt, author.DeletedBy, foldName(author.Name),
+	}, nil
+}
+
+// jsonColumn returns the JSON of the value, or NULL when it is absent.
+func jsonColumn(value any, absent bool) (sql.NullString, error) {
+	if absent {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(raw), Valid: true}, nil
+}
+
+// scanAuthor reads an author from the columns of authorColumns.
+func scanAuthor(row rowScanner) (data.Author, error) {
+	var author data.Author
+	var birthDate, deathDate, occupations, alternateNames sql.NullString
+	var deletedAt sql.NullInt64
+	err := row.Scan(&author.ID, &author.Name, &author.PicURL, &author.Biography, &birthDate, &deathDate,
+		&author.Nationality, &occupations, &alternateNames, &author.Version, &deletedAt, &author.DeletedBy)
+	if err != nil {
+		return data.Author{}, err
+	}
+	columns := []struct {
+		raw   sql.NullString
+		value any
+	}{
+		{birthDate, &author.BirthDate},
+		{deathDate, &author.DeathDate},
+		{occupations, &author.Occupations},
+		{alternateNames, &author.AlternateNames},
+	}
+	for _, column := range columns {
+		if !column.raw.Valid {
+			continue
+		}
+		if err := json.Unmarshal([]byte(column.raw.String), column.value); err != nil {
+			return data.Author{}, fmt.Errorf("invalid column of author %q: %w", author.ID, err)
+		}
+	}
+	if deletedAt.Valid {
+		at := time.Unix(0, deletedAt.Int64).UTC()
+		author.DeletedAt = &at
+	}
+	return author, nil
+}
+
+// scanRevision reads a revision from its version, time and author columns.
+func scanRevision(row rowScanner) (Revision, error) {
+	var revision Revision
+	var revisedAt int64
+	var raw string
+	if err := row.Scan(&revision.Version, &revisedAt, &raw); err != nil {
+		return Revision{}, err
+	}
+	if err := json.Unmarshal([]byte(raw), &revision.Author); err != nil {
+		return Revision{}, fmt.Errorf("invalid revision %d: %w", revision.Version, err)
+	}
+	revision.Time = time.Unix(0, revisedAt).UTC()
+	return revision, nil
+}
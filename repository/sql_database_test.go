@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+// openSQLite opens a migrated SQLite database in the directory.
+func openSQLite(dir string, opts ...SQLOption) (Database, *sql.DB) {
+	db, err := OpenSQL(DialectSQLite, filepath.Join(dir, "authors.db"))
+	So(err, ShouldBeNil)
+	_, err = MigrateSQL(context.Background(), db, DialectSQLite)
+	So(err, ShouldBeNil)
+	database, err := NewSQLDatabase(context.Background(), db, DialectSQLite, opts...)
+	So(err, ShouldBeNil)
+	return database, db
+}
+
+func TestSQLDatabase(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a SQLite database", t, func() {
+		dir := t.TempDir()
+		now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		database, db := openSQLite(dir, WithSQLClock(func() time.Time { return now }))
+		Reset(func() { _ = db.Close() })
+
+		Convey("Added authors should be read back with their profile", func() {
+			author := data.Author{
+				ID:             "plato",
+				Name:           "Plato",
+				BirthDate:      &data.PartialDate{Year: -428, Circa: true},
+				DeathDate:      &data.PartialDate{Year: -348},
+				Nationality:    "Greek",
+				Occupations:    []string{"philosopher"},
+				AlternateNames: []string{},
+				Version:        7,
+			}
+			id, err := database.AddAuthor(ctx, author)
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "plato")
+			stored, err := database.GetAuthor(ctx, "plato")
+			So(err, ShouldBeNil)
+			author.Version = InitialVersion
+			So(stored, ShouldResemble, author)
+
+			_, err = database.AddAuthor(ctx, data.Author{ID: "plato", Name: "Other"})
+			So(err, ShouldWrap, ErrAlreadyExists)
+			_, err = database.GetAuthor(ctx, "missing")
+			So(err, ShouldWrap, ErrNotFound)
+		})
+
+		Convey("Authors should be listed by name and then by ID, byte by byte", func() {
+			for _, author := range []data.Author{
+				{ID: "3", Name: "plato"},
+				{ID: "2", Name: "Aristotle"},
+				{ID: "1", Name: "Plato"},
+				{ID: "4", Name: "Deleted"},
+			} {
+				_, _ = database.AddAuthor(ctx, author)
+			}
+			_, _ = database.SoftDeleteAuthor(ctx, "4", "editor", now, 0)
+			ids := func(authors []data.Author) []string {
+				ids := make([]string, len(authors))
+				for index, author := range authors {
+					ids[index] = author.ID
+				}
+				return ids
+			}
+			So(ids(database.ListAll(ctx)), ShouldResemble, []string{"2", "1", "3"})
+
+			page, err := database.ListPage(ctx, PageRequest{Size: 2})
+			So(err, ShouldBeNil)
+			So(ids(page.Authors), ShouldResemble, []string{"2", "1"})
+			page, err = database.ListPage(ctx, PageRequest{Size: 2, Token: page.NextPageToken})
+			So(err, ShouldBeNil)
+			So(ids(page.Authors), ShouldResemble, []string{"3"})
+			So(page.NextPageToken, ShouldBeEmpty)
+			_, err = database.ListPage(ctx, PageRequest{Token: "invalid"})
+			So(err, ShouldWrap, ErrInvalidArgument)
+		})
+
+		Convey("Given an author", func() {
+			_, _ = database.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada", Occupations: []string{"mathematician"}})
+
+			Convey("Updating it should replace it at the next version", func() {
+				updated, err := database.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada Lovelace"}, InitialVersion)
+				So(err, ShouldBeNil)
+				So(updated.Version, ShouldEqual, 2)
+				So(updated.Occupations, ShouldBeNil)
+				_, err = database.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada"}, InitialVersion)
+				So(err, ShouldWrap, ErrConflict)
+				_, err = database.UpdateAuthor(ctx, data.Author{ID: "grace", Name: "Grace"}, 0)
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Patching it should only change the patched fields", func() {
+				patch := data.AuthorPatch{Author: data.Author{Nationality: "British"}, Fields: []string{data.FieldNationality}}
+				patched, err := database.PatchAuthor(ctx, "ada", patch, 0)
+				So(err, ShouldBeNil)
+				So(patched.Nationality, ShouldEqual, "British")
+				So(patched.Occupations, ShouldResemble, []string{"mathematician"})
+				_, err = database.PatchAuthor(ctx, "ada", data.AuthorPatch{Fields: []string{"version"}}, 0)
+				So(err, ShouldWrap, ErrInvalidArgument)
+			})
+
+			Convey("Deleting it should hide it until it is restored", func() {
+				deletedAt := now.In(time.FixedZone("CEST", 2*60*60))
+				deleted, err := database.SoftDeleteAuthor(ctx, "ada", "editor", deletedAt, InitialVersion)
+				So(err, ShouldBeNil)
+				So(deleted.DeletedAt.Equal(now), ShouldBeTrue)
+				So(deleted.DeletedBy, ShouldEqual, "editor")
+				_, err = database.GetAuthor(ctx, "ada")
+				So(err, ShouldWrap, ErrNotFound)
+				_, err = database.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada"}, 5)
+				So(err, ShouldWrap, ErrNotFound)
+				stored, err := database.GetDeletedAuthor(ctx, "ada")
+				So(err, ShouldBeNil)
+				So(stored, ShouldResemble, deleted)
+				So(database.ListAll(ctx), ShouldBeEmpty)
+
+				_, err = database.RestoreAuthor(ctx, "ada", InitialVersion)
+				So(err, ShouldWrap, ErrConflict)
+				restored, err := database.RestoreAuthor(ctx, "ada", 2)
+				So(err, ShouldBeNil)
+				So(restored.DeletedAt, ShouldBeNil)
+				So(restored.Version, ShouldEqual, 3)
+				_, err = database.RestoreAuthor(ctx, "ada", 0)
+				So(err, ShouldWrap, ErrNotFound)
+				_, err = database.GetDeletedAuthor(ctx, "ada")
+				So(err, ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Purging it should remove it along with its revisions", func() {
+				So(database.DeleteAuthor(ctx, "ada", 2), ShouldWrap, ErrConflict)
+				So(database.DeleteAuthor(ctx, "ada", InitialVersion), ShouldBeNil)
+				_, err := database.GetAuthor(ctx, "ada")
+				So(err, ShouldWrap, ErrNotFound)
+				_, err = database.ListRevisions(ctx, "ada")
+				So(err, ShouldWrap, ErrNotFound)
+				So(database.DeleteAuthor(ctx, "ada", 0), ShouldWrap, ErrNotFound)
+			})
+
+			Convey("Each change should store a revision", func() {
+				_, _ = database.UpdateAuthor(ctx, data.Author{ID: "ada", Name: "Ada Lovelace"}, 0)
+				now = now.Add(time.Hour)
+				_, _ = database.SoftDeleteAuthor(ctx, "ada", "editor", now, 0)
+
+				revisions, err := database.ListRevisions(ctx, "ada")
+				So(err, ShouldBeNil)
+				So(revisions, ShouldHaveLength, 3)
+				So(revisions[0].Author.Occupations, ShouldResemble, []string{"mathematician"})
+				So(revisions[2].Author.DeletedBy, ShouldEqual, "editor")
+				So(revisions[2].Time, ShouldEqual, now)
+
+				revision, err := database.GetRevision(ctx, "ada", 2)
+				So(err, ShouldBeNil)
+				So(revision.Author.Name, ShouldEqual, "Ada Lovelace")
+				_, err = database.GetRevision(ctx, "ada", 4)
+				So(err, ShouldWrap, ErrNotFound)
+
+				revision, err = database.GetRevisionAt(ctx, "ada", now.Add(-time.Minute))
+				So(err, ShouldBeNil)
+				So(revision.Version, ShouldEqual, 2)
+				_, err = database.GetRevisionAt(ctx, "ada", now.Add(-2*time.Hour))
+				So(err, ShouldWrap, ErrNotFound)
+			})
+		})
+
+		Convey("Deleted authors should be listed from the oldest deletion", func() {
+			for index, id := range []string{"c", "a", "b"} {
+				_, _ = database.AddAuthor(ctx, data.Author{ID: id, Name: id})
+				_, _ = database.SoftDeleteAuthor(ctx, id, "editor", now.Add(time.Duration(index%2)*time.Hour), 0)
+			}
+			deleted, err := database.ListDeleted(ctx, time.Time{})
+			So(err, ShouldBeNil)
+			So(deleted, ShouldHaveLength, 3)
+			So([]string{deleted[0].ID, deleted[1].ID, deleted[2].ID}, ShouldResemble, []string{"b", "c", "a"})
+			deleted, err = database.ListDeleted(ctx, now)
+			So(err, ShouldBeNil)
+			So(deleted, ShouldHaveLength, 2)
+		})
+
+		Convey("Searching should ignore case and diacritics and rank the matches", func() {
+			for _, author := range []data.Author{
+				{ID: "1", Name: "Anna Karenina"},
+				{ID: "2", Name: "Karen Blixen"},
+				{ID: "3", Name: "Zola Karénine"},
+				{ID: "4", Name: "Victor Hugo"},
+				{ID: "5", Name: "100% Karen_"},
+			} {
+				_, _ = database.AddAuthor(ctx, author)
+			}
+			results, err := database.SearchAuthors(ctx, "KAREN", 3)
+			So(err, ShouldBeNil)
+			So([]string{results[0].ID, results[1].ID, results[2].ID}, ShouldResemble, []string{"2", "5", "1"})
+			results, err = database.SearchAuthors(ctx, "0%", 10)
+			So(err, ShouldBeNil)
+			So(results, ShouldHaveLength, 1)
+			results, _ = database.SearchAuthors(ctx, "n_", 10)
+			So(results, ShouldHaveLength, 1)
+			_, err = database.SearchAuthors(ctx, " ", 10)
+			So(err, ShouldWrap, ErrInvalidArgument)
+		})
+
+		Convey("Authors should be kept once the database is reopened", func() {
+			_, _ = database.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			So(db.Close(), ShouldBeNil)
+			database, db = openSQLite(dir)
+			author, err := database.GetAuthor(ctx, "ada")
+			So(err, ShouldBeNil)
+			So(author.Name, ShouldEqual, "Ada")
+			So(database.Ping(ctx), ShouldBeNil)
+		})
+
+		Convey("Concurrent changes of the same author should all be applied", func() {
+			_, _ = database.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+			errs := runConcurrently(stressWorkers, func(worker int) error {
+				patch := data.AuthorPatch{Author: data.Author{Biography: fmt.Sprint(worker)}, Fields: []string{data.FieldBiography}}
+				_, err := database.PatchAuthor(ctx, "ada", patch, 0)
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			author, _ := database.GetAuthor(ctx, "ada")
+			So(author.Version, ShouldEqual, stressWorkers+1)
+			revisions, _ := database.ListRevisions(ctx, "ada")
+			So(revisions, ShouldHaveLength, stressWorkers+1)
+		})
+	})
+
+	Convey("When placing the parameters of a query", t, func() {
+		query := "SELECT id FROM authors WHERE name = ? AND id > ?"
+		So(DialectSQLite.rebind(query), ShouldEqual, query)
+		So(DialectPostgres.rebind(query), ShouldEqual, "SELECT id FROM authors WHERE name = $1 AND id > $2")
+	})
+
+	Convey("When opening a database of an unknown dialect", t, func() {
+		_, err := OpenSQL("oracle", "")
+		So(err, ShouldWrap, ErrInvalidArgument)
+		_, err = NewSQLDatabase(ctx, nil, "oracle")
+		So(err, ShouldWrap, ErrInvalidArgument)
+	})
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqlMigrationLock is the key of the PostgreSQL advisory lock held while the
+// schema is migrated, so that instances starting together migrate it once.
+const sqlMigrationLock = 7_246_105_113
+
+// sqlMigration is a change of the schema of a SQL database, applied once and
+// recorded in the author_schema_migrations table. Migrations are only ever
+// appended, and never changed once released.
+type sqlMigration struct {
+	Version     int
+	Description string
+	// Statements returns the statements of the migration in the dialect.
+	Statements func(d SQLDialect) []string
+}
+
+// sqlMigrations are the migrations of the schema, by increasing version.
+var sqlMigrations = []sqlMigration{
+	{
+		Version:     1,
+		Description: "create the authors and their revisions",
+		Statements: func(d SQLDialect) []string {
+			return []string{
+				`CREATE TABLE authors (
+					id ` + d.textKey() + ` PRIMARY KEY,
+					name ` + d.textKey() + ` NOT NULL,
+					picurl TEXT NOT NULL,
+					biography TEXT NOT NULL,
+					birthdate TEXT,
+					deathdate TEXT,
+					nationality TEXT NOT NULL,
+					occupations TEXT,
+					alternatenames TEXT,
+					version BIGINT NOT NULL,
+					deletedat BIGINT,
+					deletedby TEXT NOT NULL,
+					searchname TEXT NOT NULL
+				)`,
+				"CREATE INDEX authors_name_id ON authors (name, id)",
+				"CREATE INDEX authors_deletedat_id ON authors (deletedat, id)",
+				`CREATE TABLE author_revisions (
+					authorid ` + d.textKey() + ` NOT NULL,
+					version BIGINT NOT NULL,
+					revisedat BIGINT NOT NULL,
+					author TEXT NOT NULL,
+					PRIMARY KEY (authorid, version)
+				)`,
+				"CREATE INDEX author_revisions_authorid_revisedat ON author_revisions (authorid, revisedat)",
+			}
+		},
+	},
+	{
+		Version:     2,
+		Description: "create the outbox and the audit log",
+		Statements: func(d SQLDialect) []string {
+			return []string{
+				`CREATE TABLE author_outbox (
+					id ` + d.textKey() + ` PRIMARY KEY,
+					kind TEXT NOT NULL,
+					authorid TEXT NOT NULL,
+					attempts INTEGER NOT NULL,
+					createdat BIGINT NOT NULL,
+					nextattemptat BIGINT NOT NULL,
+					lasterror TEXT NOT NULL,
+					requestid TEXT NOT NULL
+				)`,
+				"CREATE INDEX author_outbox_nextattemptat ON author_outbox (nextattemptat)",
+				`CREATE TABLE author_audit (
+					seq ` + d.serialKey() + `,
+					id ` + d.textKey() + ` NOT NULL UNIQUE,
+					authorid ` + d.textKey() + ` NOT NULL,
+					action TEXT NOT NULL,
+					actor TEXT NOT NULL,
+					requestid TEXT NOT NULL,
+					recordedat BIGINT NOT NULL,
+					authorbefore TEXT,
+					authorafter TEXT
+				)`,
+				"CREATE INDEX author_audit_authorid_recordedat ON author_audit (authorid, recordedat, seq)",
+			}
+		},
+	},
+}
+
+// MigrateSQL applies the migrations of the schema that were not applied yet to
+// the SQL database, each in its own transaction, and returns the version of the
+// schema. It fails when the schema is newer than the migrations it knows. On
+// PostgreSQL, the migrations are applied under an advisory lock so that the
+// instances starting together apply them once.
+func MigrateSQL(ctx context.Context, db *sql.DB, dialect SQLDialect) (int, error) {
+	if _, err := dialect.driver(); err != nil {
+		return 0, err
+	}
+	// The advisory lock belongs to the session, so the whole migration runs on
+	// the connection that holds it.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if dialect == DialectPostgres {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", sqlMigrationLock); err != nil {
+			return 0, err
+		}
+		defer func() {
+			_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", sqlMigrationLock)
+		}()
+	}
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS author_schema_migrations (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		appliedat BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return 0, err
+	}
+	version, err := schemaVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	latest := sqlMigrations[len(sqlMigrations)-1].Version
+	if version > latest {
+		return version, failedPreconditionError("schema version %d is newer than the supported version %d", version, latest)
+	}
+	for _, migration := range sqlMigrations {
+		if migration.Version <= version {
+			continue
+		}
+		if err := applyMigration(ctx, conn, dialect, migration); err != nil {
+			return version, fmt.Errorf("unable to apply migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+		version = migration.Version
+	}
+	return version, nil
+}
+
+// applyMigration applies the migration in a transaction on the connection.
+func applyMigration(ctx context.Context, conn *sql.Conn, dialect SQLDialect, migration sqlMigration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, statement := range migration.Statements(dialect) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	insert := dialect.rebind("INSERT INTO author_schema_migrations (version, description, appliedat) VALUES (?, ?, ?)")
+	if _, err := tx.ExecContext(ctx, insert, migration.Version, migration.Description, time.Now().UnixNano()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlQuerier runs queries on a SQL database or in one of its transactions.
+type sqlQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// schemaVersion returns the version of the last applied migration, zero when none was.
+func schemaVersion(ctx context.Context, db sqlQuerier) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRowContext(ctx, "SELECT MAX(version) FROM author_schema_migrations").Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
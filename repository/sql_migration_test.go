@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMigrateSQL(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an empty SQLite database", t, func() {
+		db, err := OpenSQL(DialectSQLite, filepath.Join(t.TempDir(), "authors.db"))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = db.Close() })
+		latest := sqlMigrations[len(sqlMigrations)-1].Version
+
+		Convey("Migrating should apply every migration once", func() {
+			version, err := MigrateSQL(ctx, db, DialectSQLite)
+			So(err, ShouldBeNil)
+			So(version, ShouldEqual, latest)
+			version, err = MigrateSQL(ctx, db, DialectSQLite)
+			So(err, ShouldBeNil)
+			So(version, ShouldEqual, latest)
+
+			var applied int
+			So(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM author_schema_migrations").Scan(&applied), ShouldBeNil)
+			So(applied, ShouldEqual, len(sqlMigrations))
+		})
+
+		Convey("Concurrent migrations should apply every migration once", func() {
+			errs := runConcurrently(stressWorkers, func(int) error {
+				_, err := MigrateSQL(ctx, db, DialectSQLite)
+				return err
+			})
+			So(errs, ShouldBeEmpty)
+			var applied int
+			So(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM author_schema_migrations").Scan(&applied), ShouldBeNil)
+			So(applied, ShouldEqual, len(sqlMigrations))
+		})
+
+		Convey("A schema newer than the migrations should fail the migration", func() {
+			_, _ = MigrateSQL(ctx, db, DialectSQLite)
+			_, err := db.ExecContext(ctx, "INSERT INTO author_schema_migrations (version, description, appliedat) VALUES (?, 'future', 0)", latest+1)
+			So(err, ShouldBeNil)
+			version, err := MigrateSQL(ctx, db, DialectSQLite)
+			So(err, ShouldWrap, ErrFailedPrecondition)
+			So(version, ShouldEqual, latest+1)
+		})
+
+		Convey("A failed migration should leave the schema unchanged", func() {
+			_, err := db.ExecContext(ctx, "CREATE TABLE author_revisions (id TEXT)")
+			So(err, ShouldBeNil)
+			version, err := MigrateSQL(ctx, db, DialectSQLite)
+			So(err, ShouldNotBeNil)
+			So(version, ShouldEqual, 0)
+			var tables int
+			So(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'authors'").Scan(&tables), ShouldBeNil)
+			So(tables, ShouldEqual, 0)
+		})
+	})
+}
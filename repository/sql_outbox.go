@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// outboxColumns are the columns a task is read from, in the order of scanOutboxTask.
+const outboxColumns = "id, kind, authorid, attempts, createdat, nextattemptat, lasterror, requestid"
+
+// sqlOutboxStatements are the prepared statements of a SQL outbox.
+type sqlOutboxStatements struct {
+	enqueue  *sql.Stmt
+	due      *sql.Stmt
+	lease    *sql.Stmt
+	complete *sql.Stmt
+	retry    *sql.Stmt
+	list     *sql.Stmt
+}
+
+type sqlOutbox struct {
+	db    *sql.DB
+	stmts sqlOutboxStatements
+}
+
+// NewSQLOutbox creates an outbox store in the author_outbox table created by
+// MigrateSQL, and prepares its statements.
+func NewSQLOutbox(ctx context.Context, db *sql.DB, dialect SQLDialect) (OutboxStore, error) {
+	if _, err := dialect.driver(); err != nil {
+		return nil, err
+	}
+	s := &sqlOutbox{db: db}
+	// The workers of PostgreSQL skip the tasks claimed by the others instead
+	// of waiting for them.
+	due := "SELECT " + outboxColumns + " FROM author_outbox WHERE nextattemptat <= ? ORDER BY nextattemptat LIMIT 1"
+	if dialect == DialectPostgres {
+		due += " FOR UPDATE SKIP LOCKED"
+	}
+	err := prepareQueries(ctx, db, dialect, []sqlQuery{
+		{&s.stmts.enqueue, "INSERT INTO author_outbox (" + outboxColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO UPDATE SET nextattemptat = excluded.nextattemptat"},
+		{&s.stmts.due, due},
+		{&s.stmts.lease, "UPDATE author_outbox SET nextattemptat = ? WHERE id = ?"},
+		{&s.stmts.complete, "DELETE FROM author_outbox WHERE id = ?"},
+		{&s.stmts.retry, "UPDATE author_outbox SET attempts = attempts + 1, nextattemptat = ?, lasterror = ? WHERE id = ?"},
+		{&s.stmts.list, "SELECT " + outboxColumns + " FROM author_outbox ORDER BY createdat, id"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Enqueue adds a task to the SQL outbox, or makes the pending task with the same ID due.
+func (s *sqlOutbox) Enqueue(ctx context.Context, task OutboxTask) error {
+	_, err := s.stmts.enqueue.ExecContext(ctx, task.ID, task.Kind, task.AuthorID, task.Attempts,
+		task.CreatedAt.UnixNano(), task.NextAttemptAt.UnixNano(), task.LastError, task.RequestID)
+	return err
+}
+
+// Claim returns the task of the SQL outbox that is due the longest and hides it
+// for the lease. The task is read and leased in a transaction, so concurrent
+// workers never claim the same task.
+func (s *sqlOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration) (OutboxTask, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return OutboxTask{}, false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	task, err := scanOutboxTask(tx.StmtContext(ctx, s.stmts.due).QueryRowContext(ctx, now.UnixNano()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return OutboxTask{}, false, nil
+	}
+	if err != nil {
+		return OutboxTask{}, false, err
+	}
+	if _, err := tx.StmtContext(ctx, s.stmts.lease).ExecContext(ctx, now.Add(lease).UnixNano(), task.ID); err != nil {
+		return OutboxTask{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return OutboxTask{}, false, err
+	}
+	return task, true, nil
+}
+
+// Complete removes a task from the SQL outbox.
+func (s *sqlOutbox) Complete(ctx context.Context, id string) error {
+	_, err := s.stmts.complete.ExecContext(ctx, id)
+	return err
+}
+
+// Retry records the failure of a task of the SQL outbox.
+func (s *sqlOutbox) Retry(ctx context.Context, id string, nextAttemptAt time.Time, lastError string) error {
+	_, err := s.stmts.retry.ExecContext(ctx, nextAttemptAt.UnixNano(), lastError, id)
+	return err
+}
+
+// List returns the pending tasks of the SQL outbox, the oldest first.
+func (s *sqlOutbox) List(ctx context.Context) ([]OutboxTask, error) {
+	rows, err := s.stmts.list.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tasks := make([]OutboxTask, 0)
+	for rows.Next() {
+		task, err := scanOutboxTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// scanOutboxTask reads a task from the columns of outboxColumns.
+func scanOutboxTask(row rowScanner) (OutboxTask, error) {
+	var task OutboxTask
+	var createdAt, nextAttemptAt int64
+	err := row.Scan(&task.ID, &task.Kind, &task.AuthorID, &task.Attempts, &createdAt, &nextAttemptAt, &task.LastError, &task.RequestID)
+	if err != nil {
+		return OutboxTask{}, err
+	}
+	task.CreatedAt = time.Unix(0, createdAt).UTC()
+	task.NextAttemptAt = time.Unix(0, nextAttemptAt).UTC()
+	return task, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSQLOutbox(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a SQLite outbox", t, func() {
+		_, db := openSQLite(t.TempDir())
+		Reset(func() { _ = db.Close() })
+		outbox, err := NewSQLOutbox(ctx, db, DialectSQLite)
+		So(err, ShouldBeNil)
+
+		Convey("Enqueued tasks should be listed from the oldest", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("grace", "", now.Add(time.Second))), ShouldBeNil)
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "request", now)), ShouldBeNil)
+			tasks, err := outbox.List(ctx)
+			So(err, ShouldBeNil)
+			So(tasks, ShouldResemble, []OutboxTask{
+				newDeleteAuthorQuotesTask("ada", "request", now),
+				newDeleteAuthorQuotesTask("grace", "", now.Add(time.Second)),
+			})
+		})
+
+		Convey("Enqueuing a pending task should only make it due again", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "", now)), ShouldBeNil)
+			So(outbox.Retry(ctx, TaskDeleteAuthorQuotes+":ada", now.Add(time.Hour), "unavailable"), ShouldBeNil)
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "", now.Add(time.Minute))), ShouldBeNil)
+			tasks, _ := outbox.List(ctx)
+			So(tasks, ShouldHaveLength, 1)
+			So(tasks[0].Attempts, ShouldEqual, 1)
+			So(tasks[0].LastError, ShouldEqual, "unavailable")
+			So(tasks[0].CreatedAt, ShouldEqual, now)
+			So(tasks[0].NextAttemptAt, ShouldEqual, now.Add(time.Minute))
+		})
+
+		Convey("A claimed task should be hidden for the lease", func() {
+			So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask("ada", "", now)), ShouldBeNil)
+			task, ok, err := outbox.Claim(ctx, now, time.Minute)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(task.AuthorID, ShouldEqual, "ada")
+			_, ok, _ = outbox.Claim(ctx, now, time.Minute)
+			So(ok, ShouldBeFalse)
+			_, ok, _ = outbox.Claim(ctx, now.Add(time.Minute), time.Minute)
+			So(ok, ShouldBeTrue)
+
+			So(outbox.Complete(ctx, task.ID), ShouldBeNil)
+			tasks, _ := outbox.List(ctx)
+			So(tasks, ShouldBeEmpty)
+		})
+
+		Convey("Concurrent claims should never claim the same task", func() {
+			for _, id := range []string{"a", "b", "c"} {
+				So(outbox.Enqueue(ctx, newDeleteAuthorQuotesTask(id, "", now)), ShouldBeNil)
+			}
+			claimed := make(chan string, stressWorkers)
+			errs := runConcurrently(stressWorkers, func(int) error {
+				task, ok, err := outbox.Claim(ctx, now, time.Minute)
+				if ok {
+					claimed <- task.ID
+				}
+				return err
+			})
+			close(claimed)
+			So(errs, ShouldBeEmpty)
+			ids := make(map[string]bool)
+			for id := range claimed {
+				So(ids[id], ShouldBeFalse)
+				ids[id] = true
+			}
+			So(ids, ShouldHaveLength, 3)
+		})
+	})
+
+	Convey("When creating an outbox of an unknown dialect", t, func() {
+		_, err := NewSQLOutbox(ctx, nil, "oracle")
+		So(err, ShouldWrap, ErrInvalidArgument)
+	})
+}
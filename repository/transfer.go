@@ -0,0 +1,524 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is the file format of the exported and imported authors.
+type Format string
+
+const (
+	// FormatNDJSON has one JSON author per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV has a header line naming the columns, then one author per line.
+	FormatCSV Format = "csv"
+)
+
+// ParseFormat returns the format named by the string, FormatNDJSON when it is empty.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case "", FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", invalidArgumentError("unknown format %q", name)
+	}
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// ImportMode tells how an import handles the authors that already exist.
+type ImportMode string
+
+const (
+	// ImportUpsert replaces the authors that already exist.
+	ImportUpsert ImportMode = "upsert"
+	// ImportInsert fails the rows of the authors that already exist.
+	ImportInsert ImportMode = "insert"
+)
+
+// ParseImportMode returns the mode named by the string, ImportUpsert when it is empty.
+func ParseImportMode(name string) (ImportMode, error) {
+	switch ImportMode(name) {
+	case "", ImportUpsert:
+		return ImportUpsert, nil
+	case ImportInsert:
+		return ImportInsert, nil
+	default:
+		return "", invalidArgumentError("unknown import mode %q", name)
+	}
+}
+
+// csvColumns are the columns of the CSV format, named as the author fields in
+// JSON. The lists are joined with csvListSeparator, escaped in their values
+// with csvListEscape.
+var csvColumns = []string{
+	"id",
+	data.FieldName,
+	data.FieldPicURL,
+	data.FieldBiography,
+	data.FieldBirthDate,
+	data.FieldDeathDate,
+	data.FieldNationality,
+	data.FieldOccupations,
+	data.FieldAlternateNames,
+}
+
+// csvListSeparator separates the values of the list columns of the CSV format.
+const csvListSeparator = ';'
+
+// csvListEscape precedes the separators and the escapes that are part of the
+// values of a list column.
+const csvListEscape = '\\'
+
+// maxImportLine is the length of the longest line of an NDJSON import.
+const maxImportLine = 1 << 20
+
+// ImportOptions configure an import.
+type ImportOptions struct {
+	// Mode tells how the authors that already exist are handled.
+	Mode ImportMode
+	// DryRun checks the rows and reports what would be imported without
+	// storing anything.
+	DryRun bool
+	// Audit records the imported authors when set. The authors imported through
+	// a Repository are recorded in its own audit log instead.
+	Audit AuditStore
+	// OnImport is called with each imported author when set.
+	OnImport func(action string, author data.Author)
+}
+
+// ImportReport is the outcome of an import. In a dry run, the created and
+// updated authors are the ones that would have been.
+type ImportReport struct {
+	DryRun  bool          `json:"dryRun"`
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors"`
+}
+
+// ImportError is the error of a row that was not imported. The row is the line
+// of the row in the imported file.
+type ImportError struct {
+	Row    int               `json:"row"`
+	ID     string            `json:"id,omitempty"`
+	Error  string            `json:"error"`
+	Fields []data.FieldError `json:"fields,omitempty"`
+}
+
+// ExportAuthors writes the authors of the database, excluding the deleted ones,
+// in the format and sorted by name, a page at a time. It returns the number of
+// exported authors.
+func ExportAuthors(ctx context.Context, db Database, w io.Writer, format Format) (int, error) {
+	var writeAuthor func(author data.Author) error
+	flush := func() error { return nil }
+	switch format {
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		writeAuthor = func(author data.Author) error {
+			return encoder.Encode(author)
+		}
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvColumns); err != nil {
+			return 0, err
+		}
+		writeAuthor = func(author data.Author) error {
+			return writer.Write(toCSVRecord(author))
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	default:
+		return 0, invalidArgumentError("unknown format %q", format)
+	}
+
+	count := 0
+	request := PageRequest{Size: MaxPageSize}
+	for {
+		page, err := db.ListPage(ctx, request)
+		if err != nil {
+			return count, err
+		}
+		for _, author := range page.Authors {
+			if err := writeAuthor(author); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if err := flush(); err != nil {
+			return count, err
+		}
+		if page.NextPageToken == "" {
+			return count, nil
+		}
+		request.Token = page.NextPageToken
+	}
+}
+
+// ImportAuthors reads the authors in the format and adds them to the database,
+// one row at a time. The rows that cannot be read, that are invalid or that
+// cannot be stored are reported without stopping the import, which fails as a
+// whole only when the file cannot be read. Authors without an ID are created
+// with a new one, and the versions and deletions of the rows are ignored. The
+// rows of deleted authors fail until the authors are restored.
+func ImportAuthors(ctx context.Context, db Database, r io.Reader, format Format, opts ImportOptions) (ImportReport, error) {
+	return newAuthorImporter(db, opts).read(ctx, r, format)
+}
+
+// authorImporter imports the rows of a file.
+type authorImporter struct {
+	db     Database
+	opts   ImportOptions
+	report ImportReport
+	// imported are the IDs imported by a dry run, which exist afterwards
+	// although nothing is stored.
+	imported map[string]bool
+	now      func() time.Time
+	// record adds an imported author to the audit log.
+	record func(ctx context.Context, action string, id string, before *data.Author, after *data.Author)
+}
+
+// newAuthorImporter creates an importer recording the imported authors in the
+// audit log of the options, if any.
+func newAuthorImporter(db Database, opts ImportOptions) *authorImporter {
+	importer := &authorImporter{
+		db:       db,
+		opts:     opts,
+		report:   ImportReport{DryRun: opts.DryRun, Errors: []ImportError{}},
+		imported: make(map[string]bool),
+		now:      time.Now,
+	}
+	importer.record = importer.recordAudit
+	return importer
+}
+
+// read imports the rows read in the format.
+func (i *authorImporter) read(ctx context.Context, r io.Reader, format Format) (ImportReport, error) {
+	var err error
+	switch format {
+	case FormatNDJSON:
+		err = i.readNDJSON(ctx, r)
+	case FormatCSV:
+		err = i.readCSV(ctx, r)
+	default:
+		err = invalidArgumentError("unknown format %q", format)
+	}
+	if err != nil {
+		return ImportReport{}, err
+	}
+	return i.report, nil
+}
+
+func (i *authorImporter) readNDJSON(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	for row := 1; scanner.Scan(); row++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var author data.Author
+		if err := json.Unmarshal([]byte(line), &author); err != nil {
+			i.fail(row, "", invalidArgumentError("invalid author: %v", err))
+			continue
+		}
+		i.importAuthor(ctx, row, author)
+	}
+	if err := scanner.Err(); err != nil {
+		return InvalidArgument(fmt.Errorf("unable to read the authors: %w", err))
+	}
+	return nil
+}
+
+func (i *authorImporter) readCSV(ctx context.Context, r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return InvalidArgument(fmt.Errorf("unable to read the CSV header: %w", err))
+	}
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		name = strings.TrimSpace(name)
+		if !isCSVColumn(name) {
+			return invalidArgumentError("unknown CSV column %q", name)
+		}
+		columns[name] = index
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			i.fail(parseErr.StartLine, "", invalidArgumentError("invalid row: %v", parseErr.Err))
+			continue
+		}
+		if err != nil {
+			return InvalidArgument(fmt.Errorf("unable to read the authors: %w", err))
+		}
+		row, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			i.fail(row, "", invalidArgumentError("row has %d columns instead of %d", len(record), len(header)))
+			continue
+		}
+		author, err := fromCSVRecord(record, columns)
+		if err != nil {
+			i.fail(row, author.ID, err)
+			continue
+		}
+		i.importAuthor(ctx, row, author)
+	}
+}
+
+// importAuthor creates or replaces the author of a row. An author replaced
+// without an expected version is conditioned on the version that was read, and
+// read again when it changed in between, as the repository does for its own
+// changes, so that the audit log records the author that was replaced.
+func (i *authorImporter) importAuthor(ctx context.Context, row int, author data.Author) {
+	if author.ID == "" {
+		author.ID = uuid.New().String()
+	}
+	author.Version = 0
+	author.DeletedAt = nil
+	author.DeletedBy = ""
+	if err := author.Validate(); err != nil {
+		i.fail(row, author.ID, InvalidArgument(err))
+		return
+	}
+	for attempt := 1; ; attempt++ {
+		err := i.writeAuthor(ctx, author)
+		if err == nil {
+			return
+		}
+		raced := errors.Is(err, ErrConflict) || (i.opts.Mode == ImportUpsert && errors.Is(err, ErrAlreadyExists))
+		if !raced || attempt >= maxChangeAttempts {
+			i.fail(row, author.ID, err)
+			return
+		}
+	}
+}
+
+// writeAuthor creates or replaces an author, depending on whether it is stored.
+func (i *authorImporter) writeAuthor(ctx context.Context, author data.Author) error {
+	before, err := i.db.GetAuthor(ctx, author.ID)
+	exists := err == nil || i.imported[author.ID]
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if !exists {
+		_, err := i.db.GetDeletedAuthor(ctx, author.ID)
+		if err == nil {
+			return failedPreconditionError("author %q is deleted and must be restored before being imported", author.ID)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	if exists && i.opts.Mode == ImportInsert {
+		return alreadyExistsError(author.ID)
+	}
+	action := AuditCreated
+	if exists {
+		action = AuditUpdated
+	}
+	if i.opts.DryRun {
+		i.imported[author.ID] = true
+		i.count(action)
+		return nil
+	}
+
+	var after data.Author
+	if exists {
+		after, err = i.db.UpdateAuthor(ctx, author, before.Version)
+	} else {
+		_, err = i.db.AddAuthor(ctx, author)
+		after = author
+		after.Version = InitialVersion
+	}
+	if err != nil {
+		return err
+	}
+	i.count(action)
+	var previous *data.Author
+	if exists {
+		previous = &before
+	}
+	i.record(ctx, action, author.ID, previous, &after)
+	if i.opts.OnImport != nil {
+		i.opts.OnImport(action, after)
+	}
+	return nil
+}
+
+// recordAudit adds an imported author to the audit log of the options, logging
+// a failure since the author is already stored.
+func (i *authorImporter) recordAudit(ctx context.Context, action string, id string, before *data.Author, after *data.Author) {
+	if i.opts.Audit == nil {
+		return
+	}
+	entry := newAuditEntry(ctx, action, id, before, after, i.now().UTC())
+	if err := i.opts.Audit.Record(ctx, entry); err != nil {
+		Logger(ctx).Error("unable to record the audit entry", "author_id", id, "action", action, "err", err)
+	}
+}
+
+func (i *authorImporter) count(action string) {
+	if action == AuditCreated {
+		i.report.Created++
+	} else {
+		i.report.Updated++
+	}
+}
+
+// fail reports the error of a row.
+func (i *authorImporter) fail(row int, id string, err error) {
+	importErr := ImportError{Row: row, ID: id, Error: err.Error()}
+	var verr *data.ValidationError
+	if errors.As(err, &verr) {
+		importErr.Fields = verr.Fields
+	}
+	i.report.Failed++
+	i.report.Errors = append(i.report.Errors, importErr)
+}
+
+func isCSVColumn(name string) bool {
+	for _, column := range csvColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func toCSVRecord(author data.Author) []string {
+	return []string{
+		author.ID,
+		author.Name,
+		author.PicURL,
+		author.Biography,
+		formatCSVDate(author.BirthDate),
+		formatCSVDate(author.DeathDate),
+		author.Nationality,
+		formatCSVList(author.Occupations),
+		formatCSVList(author.AlternateNames),
+	}
+}
+
+// fromCSVRecord returns the author of a CSV row, whose columns are given by name.
+func fromCSVRecord(record []string, columns map[string]int) (data.Author, error) {
+	value := func(name string) string {
+		if index, ok := columns[name]; ok {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+	author := data.Author{
+		ID:             value("id"),
+		Name:           value(data.FieldName),
+		PicURL:         value(data.FieldPicURL),
+		Biography:      value(data.FieldBiography),
+		Nationality:    value(data.FieldNationality),
+		Occupations:    parseCSVList(value(data.FieldOccupations)),
+		AlternateNames: parseCSVList(value(data.FieldAlternateNames)),
+	}
+	var err error
+	if author.BirthDate, err = parseCSVDate(data.FieldBirthDate, value(data.FieldBirthDate)); err != nil {
+		return author, err
+	}
+	if author.DeathDate, err = parseCSVDate(data.FieldDeathDate, value(data.FieldDeathDate)); err != nil {
+		return author, err
+	}
+	return author, nil
+}
+
+func formatCSVDate(date *data.PartialDate) string {
+	if date == nil {
+		return ""
+	}
+	return date.String()
+}
+
+func parseCSVDate(field string, value string) (*data.PartialDate, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := data.ParsePartialDate(value)
+	if err != nil {
+		return nil, invalidArgumentError("invalid %s %q: %v", field, value, err)
+	}
+	return &date, nil
+}
+
+// formatCSVList joins the values of a list, escaping the separators and the
+// escapes they contain, so that "poet; essayist" is read back as one value.
+func formatCSVList(values []string) string {
+	var b strings.Builder
+	for index, value := range values {
+		if index > 0 {
+			b.WriteRune(csvListSeparator)
+		}
+		for _, r := range value {
+			if r == csvListSeparator || r == csvListEscape {
+				b.WriteRune(csvListEscape)
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseCSVList splits a list on its separators that are not escaped.
+func parseCSVList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var values []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == csvListEscape:
+			escaped = true
+		case r == csvListSeparator:
+			values = append(values, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(values, strings.TrimSpace(b.String()))
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+)
+
+func TestParseFormat(t *testing.T) {
+	Convey("Parsing a format", t, func() {
+		format, err := ParseFormat("")
+		So(err, ShouldBeNil)
+		So(format, ShouldEqual, FormatNDJSON)
+		format, err = ParseFormat("CSV")
+		So(err, ShouldBeNil)
+		So(format, ShouldEqual, FormatCSV)
+		So(format.ContentType(), ShouldEqual, "text/csv")
+		_, err = ParseFormat("xml")
+		So(err, ShouldWrap, ErrInvalidArgument)
+
+		mode, err := ParseImportMode("")
+		So(err, ShouldBeNil)
+		So(mode, ShouldEqual, ImportUpsert)
+		_, err = ParseImportMode("merge")
+		So(err, ShouldWrap, ErrInvalidArgument)
+	})
+}
+
+func TestExportAuthors(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a database with more authors than a page", t, func() {
+		db := NewInMemoryDatabase()
+		for index := 0; index < MaxPageSize+2; index++ {
+			_, _ = db.AddAuthor(ctx, data.Author{ID: fmt.Sprintf("author-%03d", index), Name: "Author"})
+		}
+		_, _ = db.AddAuthor(ctx, data.Author{
+			ID:          "ada",
+			Name:        "Ada, Countess of Lovelace",
+			BirthDate:   &data.PartialDate{Year: 1815, Month: 12, Day: 10},
+			Occupations: []string{"mathematician", "writer"},
+		})
+		_, _ = db.AddAuthor(ctx, data.Author{ID: "deleted", Name: "Deleted"})
+		_, _ = db.SoftDeleteAuthor(ctx, "deleted", "editor", time.Now(), 0)
+
+		Convey("Exporting in NDJSON should write every author on its own line", func() {
+			var buf bytes.Buffer
+			count, err := ExportAuthors(ctx, db, &buf, FormatNDJSON)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, MaxPageSize+3)
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			So(lines, ShouldHaveLength, MaxPageSize+3)
+			var first data.Author
+			So(json.Unmarshal([]byte(lines[0]), &first), ShouldBeNil)
+			So(first.ID, ShouldEqual, "ada")
+			So(first.Occupations, ShouldResemble, []string{"mathematician", "writer"})
+		})
+
+		Convey("Exporting in CSV should write a header and a line per author", func() {
+			var buf bytes.Buffer
+			count, err := ExportAuthors(ctx, db, &buf, FormatCSV)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, MaxPageSize+3)
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			So(lines, ShouldHaveLength, MaxPageSize+4)
+			So(lines[0], ShouldEqual, "id,name,picUrl,biography,birthDate,deathDate,nationality,occupations,alternateNames")
+			So(lines[1], ShouldEqual, `ada,"Ada, Countess of Lovelace",,,1815-12-10,,,mathematician;writer,`)
+		})
+
+		Convey("Exporting in an unknown format should fail", func() {
+			_, err := ExportAuthors(ctx, db, &bytes.Buffer{}, Format("xml"))
+			So(err, ShouldWrap, ErrInvalidArgument)
+		})
+	})
+}
+
+func TestImportAuthors(t *testing.T) {
+	ctx := WithActor(context.Background(), "importer")
+
+	Convey("Given a database with an author", t, func() {
+		db := NewInMemoryDatabase()
+		_, _ = db.AddAuthor(ctx, data.Author{ID: "ada", Name: "Ada"})
+		audit := NewInMemoryAudit()
+		ndjson := strings.Join([]string{
+			`{"id": "ada", "name": "Ada Lovelace", "version": 7}`,
+			`{"id": "grace", "name": "Grace Hopper"}`,
+			``,
+			`{"name": "Alan Turing"}`,
+			`{"id": "bad", "name": ""}`,
+			`not json`,
+		}, "\n")
+
+		Convey("An upsert should create the new authors and replace the existing ones", func() {
+			var imported []string
+			report, err := ImportAuthors(ctx, db, strings.NewReader(ndjson), FormatNDJSON, ImportOptions{
+				Audit:    audit,
+				OnImport: func(action string, author data.Author) { imported = append(imported, action+" "+author.Name) },
+			})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 2)
+			So(report.Updated, ShouldEqual, 1)
+			So(report.Failed, ShouldEqual, 2)
+			So(report.Errors[0].Row, ShouldEqual, 5)
+			So(report.Errors[0].ID, ShouldEqual, "bad")
+			So(report.Errors[0].Fields, ShouldNotBeEmpty)
+			So(report.Errors[1].Row, ShouldEqual, 6)
+			So(imported, ShouldResemble, []string{"updated Ada Lovelace", "created Grace Hopper", "created Alan Turing"})
+
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada Lovelace")
+			So(author.Version, ShouldEqual, 2)
+			So(db.ListAll(ctx), ShouldHaveLength, 3)
+			entries, _ := audit.History(ctx, "ada")
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Action, ShouldEqual, AuditUpdated)
+			So(entries[0].Actor, ShouldEqual, "importer")
+			So(entries[0].Before.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("An insert should fail the rows of the existing authors", func() {
+			report, err := ImportAuthors(ctx, db, strings.NewReader(ndjson), FormatNDJSON, ImportOptions{Mode: ImportInsert})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 2)
+			So(report.Updated, ShouldEqual, 0)
+			So(report.Failed, ShouldEqual, 3)
+			So(report.Errors[0].Row, ShouldEqual, 1)
+			So(report.Errors[0].Error, ShouldContainSubstring, "already exists")
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("A dry run should report the import without storing anything", func() {
+			rows := ndjson + "\n" + `{"id": "grace", "name": "Grace Brewster Hopper"}`
+			report, err := ImportAuthors(ctx, db, strings.NewReader(rows), FormatNDJSON, ImportOptions{DryRun: true, Audit: audit})
+			So(err, ShouldBeNil)
+			So(report.DryRun, ShouldBeTrue)
+			So(report.Created, ShouldEqual, 2)
+			So(report.Updated, ShouldEqual, 2)
+			So(report.Failed, ShouldEqual, 2)
+			So(db.ListAll(ctx), ShouldHaveLength, 1)
+			entries, _ := audit.History(ctx, "grace")
+			So(entries, ShouldBeEmpty)
+		})
+
+		Convey("An import should fail the rows of the deleted authors", func() {
+			_, _ = db.SoftDeleteAuthor(ctx, "ada", "editor", time.Now(), 0)
+			report, err := ImportAuthors(ctx, db, strings.NewReader(ndjson), FormatNDJSON, ImportOptions{})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 2)
+			So(report.Failed, ShouldEqual, 3)
+			So(report.Errors[0].Row, ShouldEqual, 1)
+			So(report.Errors[0].Error, ShouldContainSubstring, "deleted")
+			author, _ := db.GetDeletedAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("An upsert of an author changed in between should record the author it replaced", func() {
+			racing := &racingDatabase{Database: db, races: 1}
+			report, err := ImportAuthors(ctx, racing, strings.NewReader(`{"id": "ada", "name": "Ada Lovelace"}`), FormatNDJSON, ImportOptions{Audit: audit})
+			So(err, ShouldBeNil)
+			So(report.Updated, ShouldEqual, 1)
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada Lovelace")
+			So(author.Version, ShouldEqual, 3)
+			entries, _ := audit.History(ctx, "ada")
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Before.Name, ShouldEqual, "Ada Byron")
+			So(entries[0].Before.Version, ShouldEqual, 2)
+		})
+
+		Convey("An upsert of an author that keeps changing should fail its row", func() {
+			racing := &racingDatabase{Database: db, races: maxChangeAttempts}
+			report, err := ImportAuthors(ctx, racing, strings.NewReader(`{"id": "ada", "name": "Ada Lovelace"}`), FormatNDJSON, ImportOptions{Audit: audit})
+			So(err, ShouldBeNil)
+			So(report.Failed, ShouldEqual, 1)
+			So(report.Errors[0].Error, ShouldContainSubstring, "version")
+			author, _ := db.GetAuthor(ctx, "ada")
+			So(author.Name, ShouldEqual, "Ada Byron")
+			entries, _ := audit.History(ctx, "ada")
+			So(entries, ShouldBeEmpty)
+		})
+
+		Convey("An import through a repository should be recorded in its audit log at its time", func() {
+			now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+			repo := New(db, NewFakeClientRepository(), WithAudit(audit), WithClock(func() time.Time { return now }))
+			_, err := repo.ImportAuthors(ctx, strings.NewReader(ndjson), FormatNDJSON, ImportOptions{})
+			So(err, ShouldBeNil)
+			entries, _ := repo.AuthorHistory(ctx, "grace")
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Action, ShouldEqual, AuditCreated)
+			So(entries[0].Time, ShouldEqual, now)
+		})
+
+		Convey("A CSV import should read the columns named by the header", func() {
+			csv := strings.Join([]string{
+				"name,id,occupations,birthDate",
+				`"Hopper, Grace",grace,admiral; computer scientist,1906-12-09`,
+				"Alan,alan,,sometime",
+				"Too,many,columns,here,really",
+			}, "\n")
+			report, err := ImportAuthors(ctx, db, strings.NewReader(csv), FormatCSV, ImportOptions{})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 1)
+			So(report.Failed, ShouldEqual, 2)
+			So(report.Errors[0].Row, ShouldEqual, 3)
+			So(report.Errors[0].ID, ShouldEqual, "alan")
+			So(report.Errors[1].Row, ShouldEqual, 4)
+
+			author, err := db.GetAuthor(ctx, "grace")
+			So(err, ShouldBeNil)
+			So(author.Name, ShouldEqual, "Hopper, Grace")
+			So(author.Occupations, ShouldResemble, []string{"admiral", "computer scientist"})
+			So(author.BirthDate, ShouldResemble, &data.PartialDate{Year: 1906, Month: 12, Day: 9})
+		})
+
+		Convey("A CSV import with an unknown column should fail", func() {
+			_, err := ImportAuthors(ctx, db, strings.NewReader("name,quotes\nAda,none\n"), FormatCSV, ImportOptions{})
+			So(err, ShouldWrap, ErrInvalidArgument)
+		})
+
+		Convey("An exported file should be imported back", func() {
+			var buf bytes.Buffer
+			_, _ = db.UpdateAuthor(ctx, data.Author{
+				ID:             "ada",
+				Name:           "Ada",
+				Nationality:    "British",
+				Occupations:    []string{"poet; essayist", `back\slash`, "mathematician"},
+				AlternateNames: []string{"Ada King"},
+			}, 0)
+			_, err := ExportAuthors(ctx, db, &buf, FormatCSV)
+			So(err, ShouldBeNil)
+
+			target := NewInMemoryDatabase()
+			report, err := ImportAuthors(ctx, target, &buf, FormatCSV, ImportOptions{})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 1)
+			author, _ := target.GetAuthor(ctx, "ada")
+			So(author.Nationality, ShouldEqual, "British")
+			So(author.Occupations, ShouldResemble, []string{"poet; essayist", `back\slash`, "mathematician"})
+			So(author.AlternateNames, ShouldResemble, []string{"Ada King"})
+			So(author.Version, ShouldEqual, InitialVersion)
+		})
+	})
+}
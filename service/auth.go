@@ -0,0 +1,61 @@
+package service
+
+import (
+	"github.com/wcodesoft/mosha-author-service/auth"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
+	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+)
+
+// grpcMethodRoles are the roles required by the gRPC methods. The health
+// service is public.
+var grpcMethodRoles = auth.MethodRoles{
+	pb.AuthorService_GetAuthor_FullMethodName:    auth.RoleReader,
+	pb.AuthorService_ListAuthors_FullMethodName:  auth.RoleReader,
+	pb.AuthorService_CreateAuthor_FullMethodName: auth.RoleEditor,
+	pb.AuthorService_UpdateAuthor_FullMethodName: auth.RoleEditor,
+	pb.AuthorService_DeleteAuthor_FullMethodName: auth.RoleEditor,
+
+	cpb.AuthorCatalogService_GetAuthor_FullMethodName:           auth.RoleReader,
+	cpb.AuthorCatalogService_ListAuthorsPage_FullMethodName:     auth.RoleReader,
+	cpb.AuthorCatalogService_SearchAuthors_FullMethodName:       auth.RoleReader,
+	cpb.AuthorCatalogService_GetAuthorHistory_FullMethodName:    auth.RoleReader,
+	cpb.AuthorCatalogService_ListAuthorRevisions_FullMethodName: auth.RoleReader,
+	cpb.AuthorCatalogService_GetAuthorRevision_FullMethodName:   auth.RoleReader,
+	cpb.AuthorCatalogService_WatchAuthors_FullMethodName:        auth.RoleReader,
+	cpb.AuthorCatalogService_RevertAuthor_FullMethodName:        auth.RoleEditor,
+	cpb.AuthorCatalogService_BulkWriteAuthors_FullMethodName:    auth.RoleEditor,
+	cpb.AuthorCatalogService_StreamWriteAuthors_FullMethodName:  auth.RoleEditor,
+	cpb.AuthorCatalogService_CreateAuthor_FullMethodName:        auth.RoleEditor,
+	cpb.AuthorCatalogService_UpdateAuthor_FullMethodName:        auth.RoleEditor,
+	cpb.AuthorCatalogService_PatchAuthor_FullMethodName:         auth.RoleEditor,
+	cpb.AuthorCatalogService_DeleteAuthor_FullMethodName:        auth.RoleEditor,
+	cpb.AuthorCatalogService_RestoreAuthor_FullMethodName:       auth.RoleEditor,
+	cpb.AuthorCatalogService_ListDeletedAuthors_FullMethodName:  auth.RoleAdmin,
+	cpb.AuthorCatalogService_PurgeAuthor_FullMethodName:         auth.RoleAdmin,
+	cpb.AuthorCatalogService_PurgeExpiredAuthors_FullMethodName: auth.RoleAdmin,
+
+	healthpb.Health_Check_FullMethodName: auth.Public,
+	healthpb.Health_Watch_FullMethodName: auth.Public,
+}
+
+// WithAuthenticator authenticates the calls with the authenticator and
+// rejects the ones whose caller is not allowed the role of the method.
+func WithAuthenticator(authenticator *auth.Authenticator) GrpcOption {
+	return WithInterceptors(
+		authenticator.UnaryServerInterceptor(grpcMethodRoles),
+		authenticator.StreamServerInterceptor(grpcMethodRoles),
+	)
+}
+
+// require returns the middleware rejecting the requests whose caller is not
+// allowed the role, or a middleware doing nothing when authentication is disabled.
+func (as *AuthorService) require(role auth.Role) func(http.Handler) http.Handler {
+	if as.Auth == nil {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	return auth.Require(role)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/auth"
+	"github.com/wcodesoft/mosha-author-service/data"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	mhttp "github.com/wcodesoft/mosha-service-common/http"
+	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testAuthenticator returns an authenticator with an API key for each role.
+func testAuthenticator() *auth.Authenticator {
+	return auth.New(auth.WithAPIKeys(
+		auth.APIKey{Name: "reader-service", Role: auth.RoleReader, Key: "reader-key"},
+		auth.APIKey{Name: "editor-service", Role: auth.RoleEditor, Key: "editor-key"},
+		auth.APIKey{Name: "admin-service", Role: auth.RoleAdmin, Key: "admin-key"},
+	))
+}
+
+func TestHttpAuth(t *testing.T) {
+	Convey("Given a handler with authentication", t, func() {
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		hs := AuthorService{Service: New(repo), Port: "8080", Name: "AuthorService", Auth: testAuthenticator()}
+		handler := hs.MakeHandler()
+		serve := func(method string, target string, key string, body any) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, jsonReaderFactory(body))
+			if key != "" {
+				req.Header.Set(auth.APIKeyHeader, key)
+			}
+			return executeRequest(req, handler)
+		}
+
+		Convey("Health routes should not require credentials", func() {
+			So(serve("GET", "/healthz", "", nil).Code, ShouldEqual, http.StatusOK)
+			So(serve("GET", "/readyz", "", nil).Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Reading authors should require the reader role", func() {
+			So(serve("GET", "/api/v1/author/all", "", nil).Code, ShouldEqual, http.StatusUnauthorized)
+			So(serve("GET", "/api/v1/author/all", "reader-key", nil).Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Creating authors should require the editor role", func() {
+			author := data.Author{Name: "Ada Lovelace"}
+			So(serve("POST", "/api/v1/author", "reader-key", author).Code, ShouldEqual, http.StatusForbidden)
+			So(serve("POST", "/api/v1/author", "editor-key", author).Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Purging authors should require the admin role", func() {
+			So(serve("POST", "/api/v1/author/purge", "editor-key", nil).Code, ShouldEqual, http.StatusForbidden)
+			So(serve("POST", "/api/v1/author/purge", "admin-key", nil).Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("The authenticated caller should be the actor of the changes", func() {
+			var resp mhttp.IdResponse
+			_ = json.NewDecoder(serve("POST", "/api/v1/author", "editor-key", data.Author{Name: "Ada Lovelace"}).Body).Decode(&resp)
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/delete/%s", resp.ID), nil)
+			req.Header.Set(auth.APIKeyHeader, "editor-key")
+			req.Header.Set("X-Actor", "someone-else")
+			So(executeRequest(req, handler).Code, ShouldEqual, http.StatusOK)
+
+			var deleted []data.Author
+			_ = json.NewDecoder(serve("GET", "/api/v1/author/deleted", "admin-key", nil).Body).Decode(&deleted)
+			So(deleted, ShouldHaveLength, 1)
+			So(deleted[0].DeletedBy, ShouldEqual, "editor-service")
+		})
+	})
+}
+
+func TestGrpcMethodRoles(t *testing.T) {
+	Convey("Every gRPC method should have a role", t, func() {
+		for _, desc := range []grpc.ServiceDesc{pb.AuthorService_ServiceDesc, cpb.AuthorCatalogService_ServiceDesc, healthpb.Health_ServiceDesc} {
+			for _, method := range desc.Methods {
+				So(grpcMethodRoles, ShouldContainKey, fmt.Sprintf("/%s/%s", desc.ServiceName, method.MethodName))
+			}
+			for _, stream := range desc.Streams {
+				So(grpcMethodRoles, ShouldContainKey, fmt.Sprintf("/%s/%s", desc.ServiceName, stream.StreamName))
+			}
+		}
+	})
+}
+
+func TestGrpcAuth(t *testing.T) {
+	Convey("Given a router with authentication", t, func() {
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		router := NewGrpcRouter(New(repo), "AuthorService", WithAuthenticator(testAuthenticator()))
+		go func() { _ = router.Start("18384") }()
+		defer router.Stop(context.Background())
+		time.Sleep(50 * time.Millisecond)
+
+		conn, err := grpc.Dial("localhost:18384", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		client := pb.NewAuthorServiceClient(conn)
+		withKey := func(key string) context.Context {
+			return metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyMetadata, key)
+		}
+
+		Convey("Calls without credentials should be unauthenticated", func() {
+			_, err := client.ListAuthors(context.Background(), &emptypb.Empty{})
+			So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+		})
+
+		Convey("Creating an author should require the editor role", func() {
+			request := &pb.CreateAuthorRequest{Author: &pb.Author{Name: "Ada Lovelace"}}
+			_, err := client.CreateAuthor(withKey("reader-key"), request)
+			So(status.Code(err), ShouldEqual, codes.PermissionDenied)
+			_, err = client.CreateAuthor(withKey("editor-key"), request)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Health checks should not require credentials", func() {
+			_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -2,18 +2,30 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/wcodesoft/mosha-author-service/auth"
 	"github.com/wcodesoft/mosha-author-service/data"
-	"github.com/wcodesoft/mosha-service-common/grpc"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
+	"github.com/wcodesoft/mosha-author-service/repository"
 	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"net"
 )
 
 // GrpcRouter represents the gRPC router.
 type GrpcRouter struct {
-	serviceName string
-	server      pb.AuthorServiceServer
+	serviceName   string
+	server        pb.AuthorServiceServer
+	catalogServer *catalogServer
+	health        *healthServer
+	grpcServer    *grpc.Server
 }
 
 type server struct {
@@ -22,17 +34,17 @@ type server struct {
 }
 
 // GetAuthor returns an author by id.
-func (g *server) GetAuthor(_ context.Context, request *pb.GetAuthorRequest) (*pb.Author, error) {
-	author, err := g.service.GetAuthor(request.Id)
+func (g *server) GetAuthor(ctx context.Context, request *pb.GetAuthorRequest) (*pb.Author, error) {
+	author, err := g.service.GetAuthor(ctx, request.Id)
 	if err != nil {
-		return nil, fmt.Errorf("could not get author: %v", err)
+		return nil, statusError("could not get author", err)
 	}
 	return toProtoAuthor(author), nil
 }
 
 // ListAuthors returns all authors in the database.
-func (g *server) ListAuthors(_ context.Context, _ *emptypb.Empty) (*pb.ListAuthorsResponse, error) {
-	authors := g.service.ListAll()
+func (g *server) ListAuthors(ctx context.Context, _ *emptypb.Empty) (*pb.ListAuthorsResponse, error) {
+	authors := g.service.ListAll(ctx)
 	var pbAuthors []*pb.Author
 	for _, author := range authors {
 		pbAuthors = append(pbAuthors, toProtoAuthor(author))
@@ -40,40 +52,45 @@ func (g *server) ListAuthors(_ context.Context, _ *emptypb.Empty) (*pb.ListAutho
 	return &pb.ListAuthorsResponse{Authors: pbAuthors}, nil
 }
 
-// UpdateAuthor updates an author.
-func (g *server) UpdateAuthor(_ context.Context, request *pb.UpdateAuthorRequest) (*pb.Author, error) {
+// UpdateAuthor updates the name and picture of an author.
+func (g *server) UpdateAuthor(ctx context.Context, request *pb.UpdateAuthorRequest) (*pb.Author, error) {
 	author := request.GetAuthor()
 	if author == nil {
-		return nil, fmt.Errorf("author is nil")
+		return nil, status.Error(codes.InvalidArgument, "author is nil")
 	}
-	updatedAuthor, err := g.service.UpdateAuthor(toAuthorDB(author))
+	// The shared author message has no profile fields, keep the stored ones.
+	patch := data.AuthorPatch{
+		Author: toAuthorDB(author),
+		Fields: []string{data.FieldName, data.FieldPicURL},
+	}
+	updatedAuthor, err := g.service.PatchAuthor(actorContext(ctx), author.Id, patch, 0)
 	if err != nil {
-		return nil, fmt.Errorf("could not update author: %v", err)
+		return nil, statusError("could not update author", err)
 	}
 	return toProtoAuthor(updatedAuthor), nil
 }
 
 // DeleteAuthor deletes an author by id.
-func (g *server) DeleteAuthor(_ context.Context, request *pb.DeleteAuthorRequest) (*pb.DeleteAuthorResponse, error) {
+func (g *server) DeleteAuthor(ctx context.Context, request *pb.DeleteAuthorRequest) (*pb.DeleteAuthorResponse, error) {
 	id := request.GetId()
-	err := g.service.DeleteAuthor(id)
+	err := g.service.DeleteAuthor(actorContext(ctx), id, 0)
 	if err != nil {
-		return nil, fmt.Errorf("could not delete author: %v", err)
+		return nil, statusError("could not delete author", err)
 	}
 	return &pb.DeleteAuthorResponse{Success: true}, nil
 }
 
 // CreateAuthor registers a new Author in the database.
-func (g *server) CreateAuthor(_ context.Context, req *pb.CreateAuthorRequest) (*pb.Author, error) {
+func (g *server) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.Author, error) {
 	author := req.GetAuthor()
 
 	if author == nil {
-		return nil, fmt.Errorf("author is nil")
+		return nil, status.Error(codes.InvalidArgument, "author is nil")
 	}
 
-	id, err := g.service.CreateAuthor(toAuthorDB(author))
+	id, err := g.service.CreateAuthor(actorContext(ctx), toAuthorDB(author))
 	if err != nil {
-		return nil, err
+		return nil, statusError("could not create author", err)
 	}
 	return &pb.Author{Id: id, Name: author.Name, PicUrl: author.PicUrl}, nil
 }
@@ -86,29 +103,145 @@ func toAuthorDB(author *pb.Author) data.Author {
 	return data.Author{ID: author.Id, Name: author.Name, PicURL: author.PicUrl}
 }
 
+// statusError converts the error to a gRPC status error with the code matching its kind.
+// Validation errors include the details of each invalid field.
+func statusError(msg string, err error) error {
+	st := status.Newf(statusCode(err), "%s: %v", msg, err)
+	var verr *data.ValidationError
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(verr.Fields))
+	for index, field := range verr.Fields {
+		violations[index] = &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message}
+	}
+	detailed, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// actorContext puts the actor in the context: the authenticated caller, or
+// else the one named by the x-actor metadata of the call.
+func actorContext(ctx context.Context) context.Context {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return repository.WithActor(ctx, principal.Subject)
+	}
+	if actors := metadata.ValueFromIncomingContext(ctx, "x-actor"); len(actors) > 0 {
+		return repository.WithActor(ctx, actors[0])
+	}
+	return ctx
+}
+
+// statusCode returns the gRPC status code matching the error.
+func statusCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return codes.AlreadyExists
+	case errors.Is(err, repository.ErrInvalidArgument):
+		return codes.InvalidArgument
+	case errors.Is(err, repository.ErrConflict), errors.Is(err, repository.ErrAborted):
+		return codes.Aborted
+	case errors.Is(err, repository.ErrFailedPrecondition):
+		return codes.FailedPrecondition
+	case errors.Is(err, repository.ErrDependencyFailed):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
 func newServer(s Service) pb.AuthorServiceServer {
 	return &server{
 		service: s,
 	}
 }
 
+// GrpcOption configures a GrpcRouter.
+type GrpcOption func(*grpcOptions)
+
+type grpcOptions struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+// WithInterceptors adds interceptors to the unary and streaming calls. They run
+// after the logging interceptor, in the order they are added.
+func WithInterceptors(unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) GrpcOption {
+	return func(o *grpcOptions) {
+		o.unary = append(o.unary, unary)
+		o.stream = append(o.stream, stream)
+	}
+}
+
+// newGrpcServer creates a gRPC server handling the request ID and logging the
+// calls, followed by the interceptors of the options.
+func newGrpcServer(options grpcOptions) *grpc.Server {
+	unary := append([]grpc.UnaryServerInterceptor{loggingUnaryInterceptor}, options.unary...)
+	stream := append([]grpc.StreamServerInterceptor{loggingStreamInterceptor}, options.stream...)
+	return grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+}
+
 // NewGrpcRouter creates a new gRPC router.
-func NewGrpcRouter(s Service, serviceName string) GrpcRouter {
-	return GrpcRouter{
-		server:      newServer(s),
-		serviceName: serviceName,
+func NewGrpcRouter(s Service, serviceName string, opts ...GrpcOption) GrpcRouter {
+	var options grpcOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	router := GrpcRouter{
+		server:        newServer(s),
+		catalogServer: newCatalogServer(s),
+		serviceName:   serviceName,
+		health: newHealthServer(s,
+			pb.AuthorService_ServiceDesc.ServiceName,
+			cpb.AuthorCatalogService_ServiceDesc.ServiceName,
+		),
+		grpcServer: newGrpcServer(options),
 	}
+	pb.RegisterAuthorServiceServer(router.grpcServer, router.server)
+	cpb.RegisterAuthorCatalogServiceServer(router.grpcServer, router.catalogServer)
+	healthpb.RegisterHealthServer(router.grpcServer, router.health)
+	return router
 }
 
+// Start serves the gRPC services on the port until the router is stopped.
 func (g *GrpcRouter) Start(port string) error {
-	grpcServer := grpc.CreateNewGRPCServer()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
-	pb.RegisterAuthorServiceServer(grpcServer, g.server)
-	if err := grpcServer.Serve(lis); err != nil {
+	if err := g.grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 		return fmt.Errorf("failed to serve: %v", err)
 	}
 	return nil
 }
+
+// Stop stops accepting new calls and waits for the ongoing ones to finish. The
+// calls still running when the context is done are canceled.
+func (g *GrpcRouter) Stop(ctx context.Context) error {
+	g.health.shutdown()
+	g.catalogServer.shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		g.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		g.grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
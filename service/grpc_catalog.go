@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/events"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"sync"
+	"time"
+)
+
+type catalogServer struct {
+	service      Service
+	done         chan struct{}
+	shutdownOnce sync.Once
+	cpb.UnimplementedAuthorCatalogServiceServer
+}
+
+// GetAuthor returns an author with its full profile by id.
+func (c *catalogServer) GetAuthor(ctx context.Context, request *cpb.GetAuthorRequest) (*cpb.Author, error) {
+	author, err := c.service.GetAuthor(ctx, request.GetId())
+	if err != nil {
+		return nil, statusError("could not get author", err)
+	}
+	return toCatalogAuthor(author), nil
+}
+
+// CreateAuthor registers a new Author with its full profile in the database.
+func (c *catalogServer) CreateAuthor(ctx context.Context, request *cpb.CreateAuthorRequest) (*cpb.Author, error) {
+	author := request.GetAuthor()
+	if author == nil {
+		return nil, status.Error(codes.InvalidArgument, "author is nil")
+	}
+	newAuthor := fromCatalogAuthor(author)
+	id, err := c.service.CreateAuthor(actorContext(ctx), newAuthor)
+	if err != nil {
+		return nil, statusError("could not create author", err)
+	}
+	newAuthor.ID = id
+	newAuthor.Version = repository.InitialVersion
+	return toCatalogAuthor(newAuthor), nil
+}
+
+// UpdateAuthor replaces an author with its full profile.
+func (c *catalogServer) UpdateAuthor(ctx context.Context, request *cpb.UpdateAuthorRequest) (*cpb.Author, error) {
+	author := request.GetAuthor()
+	if author == nil {
+		return nil, status.Error(codes.InvalidArgument, "author is nil")
+	}
+	updatedAuthor, err := c.service.UpdateAuthor(actorContext(ctx), fromCatalogAuthor(author), request.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError("could not update author", err)
+	}
+	return toCatalogAuthor(updatedAuthor), nil
+}
+
+// PatchAuthor changes only the fields of an author listed in the update mask.
+func (c *catalogServer) PatchAuthor(ctx context.Context, request *cpb.PatchAuthorRequest) (*cpb.Author, error) {
+	author := request.GetAuthor()
+	if author == nil {
+		return nil, status.Error(codes.InvalidArgument, "author is nil")
+	}
+	patch := data.AuthorPatch{
+		Author: fromCatalogAuthor(author),
+		Fields: request.GetUpdateMask().GetPaths(),
+	}
+	patchedAuthor, err := c.service.PatchAuthor(actorContext(ctx), author.Id, patch, request.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError("could not patch author", err)
+	}
+	return toCatalogAuthor(patchedAuthor), nil
+}
+
+// DeleteAuthor deletes an author by id, if it is at the expected version.
+func (c *catalogServer) DeleteAuthor(ctx context.Context, request *cpb.DeleteAuthorRequest) (*cpb.DeleteAuthorResponse, error) {
+	err := c.service.DeleteAuthor(actorContext(ctx), request.GetId(), request.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError("could not delete author", err)
+	}
+	return &cpb.DeleteAuthorResponse{Success: true}, nil
+}
+
+// RestoreAuthor restores a deleted author, if it is at the expected version.
+func (c *catalogServer) RestoreAuthor(ctx context.Context, request *cpb.RestoreAuthorRequest) (*cpb.Author, error) {
+	author, err := c.service.RestoreAuthor(actorContext(ctx), request.GetId(), request.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError("could not restore author", err)
+	}
+	return toCatalogAuthor(author), nil
+}
+
+// ListDeletedAuthors returns the deleted authors that can be restored.
+func (c *catalogServer) ListDeletedAuthors(ctx context.Context, _ *emptypb.Empty) (*cpb.ListDeletedAuthorsResponse, error) {
+	authors, err := c.service.ListDeleted(ctx)
+	if err != nil {
+		return nil, statusError("could not list deleted authors", err)
+	}
+	return &cpb.ListDeletedAuthorsResponse{Authors: toCatalogAuthors(authors)}, nil
+}
+
+// PurgeAuthor permanently deletes a deleted author, its quotes are deleted afterwards.
+func (c *catalogServer) PurgeAuthor(ctx context.Context, request *cpb.PurgeAuthorRequest) (*cpb.PurgeAuthorResponse, error) {
+	if err := c.service.PurgeAuthor(actorContext(ctx), request.GetId()); err != nil {
+		return nil, statusError("could not purge author", err)
+	}
+	return &cpb.PurgeAuthorResponse{Success: true}, nil
+}
+
+// PurgeExpiredAuthors permanently deletes the deleted authors whose retention period is over.
+func (c *catalogServer) PurgeExpiredAuthors(ctx context.Context, _ *emptypb.Empty) (*cpb.PurgeExpiredAuthorsResponse, error) {
+	purged, err := c.service.PurgeExpired(actorContext(ctx))
+	if err != nil {
+		return nil, statusError(fmt.Sprintf("could not purge all expired authors, purged %v", purged), err)
+	}
+	return &cpb.PurgeExpiredAuthorsResponse{PurgedIds: purged}, nil
+}
+
+// ListAuthorsPage returns a page of authors sorted by name.
+func (c *catalogServer) ListAuthorsPage(ctx context.Context, request *cpb.ListAuthorsPageRequest) (*cpb.ListAuthorsPageResponse, error) {
+	page, err := c.service.ListPage(ctx, repository.PageRequest{
+		Size:  int(request.GetPageSize()),
+		Token: request.GetPageToken(),
+	})
+	if err != nil {
+		return nil, statusError("could not list authors", err)
+	}
+	return &cpb.ListAuthorsPageResponse{Authors: toCatalogAuthors(page.Authors), NextPageToken: page.NextPageToken}, nil
+}
+
+// SearchAuthors returns the authors whose name matches the query.
+func (c *catalogServer) SearchAuthors(ctx context.Context, request *cpb.SearchAuthorsRequest) (*cpb.SearchAuthorsResponse, error) {
+	authors, err := c.service.SearchAuthors(ctx, request.GetQuery(), int(request.GetLimit()))
+	if err != nil {
+		return nil, statusError("could not search authors", err)
+	}
+	return &cpb.SearchAuthorsResponse{Authors: toCatalogAuthors(authors)}, nil
+}
+
+// GetAuthorHistory returns the changes of an author, the oldest first.
+func (c *catalogServer) GetAuthorHistory(ctx context.Context, request *cpb.GetAuthorHistoryRequest) (*cpb.GetAuthorHistoryResponse, error) {
+	entries, err := c.service.AuthorHistory(ctx, request.GetId())
+	if err != nil {
+		return nil, statusError("could not get author history", err)
+	}
+	pbEntries := make([]*cpb.AuditEntry, len(entries))
+	for index, entry := range entries {
+		pbEntries[index] = toCatalogAuditEntry(entry)
+	}
+	return &cpb.GetAuthorHistoryResponse{Entries: pbEntries}, nil
+}
+
+// ListAuthorRevisions returns the revisions of an author, the oldest first.
+func (c *catalogServer) ListAuthorRevisions(ctx context.Context, request *cpb.ListAuthorRevisionsRequest) (*cpb.ListAuthorRevisionsResponse, error) {
+	revisions, err := c.service.ListRevisions(ctx, request.GetId())
+	if err != nil {
+		return nil, statusError("could not list author revisions", err)
+	}
+	pbRevisions := make([]*cpb.Revision, len(revisions))
+	for index, revision := range revisions {
+		pbRevisions[index] = toCatalogRevision(revision)
+	}
+	return &cpb.ListAuthorRevisionsResponse{Revisions: pbRevisions}, nil
+}
+
+// GetAuthorRevision returns the revision of an author at the version or time of the request.
+func (c *catalogServer) GetAuthorRevision(ctx context.Context, request *cpb.GetAuthorRevisionRequest) (*cpb.Revision, error) {
+	var revision repository.Revision
+	var err error
+	switch selector := request.GetSelector().(type) {
+	case *cpb.GetAuthorRevisionRequest_Version:
+		revision, err = c.service.GetRevision(ctx, request.GetId(), selector.Version)
+	case *cpb.GetAuthorRevisionRequest_Time:
+		revision, err = c.service.GetRevisionAt(ctx, request.GetId(), selector.Time.AsTime())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "version or time is required")
+	}
+	if err != nil {
+		return nil, statusError("could not get author revision", err)
+	}
+	return toCatalogRevision(revision), nil
+}
+
+// RevertAuthor replaces an author with its revision at the version.
+func (c *catalogServer) RevertAuthor(ctx context.Context, request *cpb.RevertAuthorRequest) (*cpb.Author, error) {
+	author, err := c.service.RevertAuthor(actorContext(ctx), request.GetId(), request.GetVersion(), request.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError("could not revert author", err)
+	}
+	return toCatalogAuthor(author), nil
+}
+
+// WatchAuthors sends the events of the watched authors until the call is
+// canceled or the server shuts down.
+func (c *catalogServer) WatchAuthors(request *cpb.WatchAuthorsRequest, stream cpb.AuthorCatalogService_WatchAuthorsServer) error {
+	watched := make(map[string]bool, len(request.GetAuthorIds()))
+	for _, id := range request.GetAuthorIds() {
+		watched[id] = true
+	}
+	subscription := c.service.WatchAuthors()
+	defer subscription.Close()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-c.done:
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return status.Errorf(codes.Aborted, "could not watch authors: %v", subscription.Err())
+			}
+			if len(watched) > 0 && !watched[event.AuthorID] {
+				continue
+			}
+			if err := stream.Send(toCatalogEvent(event)); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+		}
+	}
+}
+
+// BulkWriteAuthors applies a batch of operations and returns their results.
+func (c *catalogServer) BulkWriteAuthors(ctx context.Context, request *cpb.BulkWriteAuthorsRequest) (*cpb.BulkWriteAuthorsResponse, error) {
+	return c.bulkWrite(ctx, request.GetMode(), request.GetOperations())
+}
+
+// StreamWriteAuthors applies the operations of the streamed requests as a
+// single batch once the client closes the stream.
+func (c *catalogServer) StreamWriteAuthors(stream cpb.AuthorCatalogService_StreamWriteAuthorsServer) error {
+	var mode string
+	var operations []*cpb.BulkOperation
+	for first := true; ; first = false {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			mode = request.GetMode()
+		}
+		operations = append(operations, request.GetOperations()...)
+		if len(operations) > MaxBulkOperations {
+			return status.Errorf(codes.InvalidArgument, "a batch has at most %d operations", MaxBulkOperations)
+		}
+	}
+	response, err := c.bulkWrite(stream.Context(), mode, operations)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(response)
+}
+
+func (c *catalogServer) bulkWrite(ctx context.Context, modeName string, operations []*cpb.BulkOperation) (*cpb.BulkWriteAuthorsResponse, error) {
+	mode, err := repository.ParseBulkMode(modeName)
+	if err != nil {
+		return nil, statusError("could not write authors", err)
+	}
+	ops := make([]repository.BulkOperation, len(operations))
+	for index, operation := range operations {
+		ops[index] = repository.BulkOperation{
+			Kind:            operation.GetKind(),
+			ID:              operation.GetId(),
+			ExpectedVersion: operation.GetExpectedVersion(),
+		}
+		if operation.GetAuthor() != nil {
+			ops[index].Author = fromCatalogAuthor(operation.GetAuthor())
+		}
+	}
+	results, err := c.service.BulkWrite(actorContext(ctx), ops, mode)
+	if err != nil {
+		return nil, statusError("could not write authors", err)
+	}
+	response := &cpb.BulkWriteAuthorsResponse{Results: make([]*cpb.BulkResult, len(results))}
+	for index, result := range results {
+		if result.Err != nil {
+			response.Results[index] = &cpb.BulkResult{Id: result.ID, Code: int32(statusCode(result.Err)), Error: result.Err.Error()}
+			response.Failed++
+			continue
+		}
+		response.Results[index] = &cpb.BulkResult{Id: result.ID, Code: int32(codes.OK), Author: toCatalogAuthor(*result.Author)}
+		response.Applied++
+	}
+	return response, nil
+}
+
+// shutdown ends the ongoing watches, so that they do not hold a graceful stop
+// of the server.
+func (c *catalogServer) shutdown() {
+	c.shutdownOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func toCatalogAuthors(authors []data.Author) []*cpb.Author {
+	pbAuthors := make([]*cpb.Author, len(authors))
+	for index, author := range authors {
+		pbAuthors[index] = toCatalogAuthor(author)
+	}
+	return pbAuthors
+}
+
+func toCatalogAuthor(author data.Author) *cpb.Author {
+	return &cpb.Author{
+		Id:             author.ID,
+		Name:           author.Name,
+		PicUrl:         author.PicURL,
+		Biography:      author.Biography,
+		BirthDate:      toCatalogDate(author.BirthDate),
+		DeathDate:      toCatalogDate(author.DeathDate),
+		Nationality:    author.Nationality,
+		Occupations:    author.Occupations,
+		AlternateNames: author.AlternateNames,
+		Version:        author.Version,
+		DeletedAt:      toTimestamp(author.DeletedAt),
+		DeletedBy:      author.DeletedBy,
+	}
+}
+
+func toCatalogAuditEntry(entry repository.AuditEntry) *cpb.AuditEntry {
+	pbEntry := &cpb.AuditEntry{
+		Id:        entry.ID,
+		AuthorId:  entry.AuthorID,
+		Action:    entry.Action,
+		Actor:     entry.Actor,
+		RequestId: entry.RequestID,
+		Time:      timestamppb.New(entry.Time),
+	}
+	if entry.Before != nil {
+		pbEntry.Before = toCatalogAuthor(*entry.Before)
+	}
+	if entry.After != nil {
+		pbEntry.After = toCatalogAuthor(*entry.After)
+	}
+	return pbEntry
+}
+
+func toCatalogRevision(revision repository.Revision) *cpb.Revision {
+	return &cpb.Revision{
+		Version: revision.Version,
+		Time:    timestamppb.New(revision.Time),
+		Author:  toCatalogAuthor(revision.Author),
+	}
+}
+
+func toCatalogEvent(event events.Event) *cpb.AuthorEvent {
+	pbEvent := &cpb.AuthorEvent{
+		Id:        event.ID,
+		Type:      event.Type,
+		AuthorId:  event.AuthorID,
+		Version:   event.Version,
+		Time:      timestamppb.New(event.Time),
+		Actor:     event.Actor,
+		RequestId: event.RequestID,
+	}
+	if event.Author != nil {
+		pbEvent.Author = toCatalogAuthor(*event.Author)
+	}
+	return pbEvent
+}
+
+func fromCatalogAuthor(author *cpb.Author) data.Author {
+	return data.Author{
+		ID:             author.Id,
+		Name:           author.Name,
+		PicURL:         author.PicUrl,
+		Biography:      author.Biography,
+		BirthDate:      fromCatalogDate(author.BirthDate),
+		DeathDate:      fromCatalogDate(author.DeathDate),
+		Nationality:    author.Nationality,
+		Occupations:    author.Occupations,
+		AlternateNames: author.AlternateNames,
+	}
+}
+
+func toCatalogDate(date *data.PartialDate) *cpb.PartialDate {
+	if date == nil {
+		return nil
+	}
+	return &cpb.PartialDate{
+		Year:  int32(date.Year),
+		Month: int32(date.Month),
+		Day:   int32(date.Day),
+		Circa: date.Circa,
+	}
+}
+
+func fromCatalogDate(date *cpb.PartialDate) *data.PartialDate {
+	if date == nil {
+		return nil
+	}
+	return &data.PartialDate{
+		Year:  int(date.Year),
+		Month: int(date.Month),
+		Day:   int(date.Day),
+		Circa: date.Circa,
+	}
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func newCatalogServer(s Service) *catalogServer {
+	return &catalogServer{
+		service: s,
+		done:    make(chan struct{}),
+	}
+}
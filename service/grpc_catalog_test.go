@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	faker "github.com/brianvoe/gofakeit/v6"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/events"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGrpcCatalog(t *testing.T) {
+	Convey("When managing an author with a profile", t, func() {
+		router := createGrpcRouter()
+		author := &cpb.Author{
+			Id:             faker.UUID(),
+			Name:           "Plato",
+			Biography:      "Greek philosopher",
+			BirthDate:      &cpb.PartialDate{Year: -428, Circa: true},
+			DeathDate:      &cpb.PartialDate{Year: -348},
+			Nationality:    "Greek",
+			Occupations:    []string{"philosopher"},
+			AlternateNames: []string{"Aristocles"},
+		}
+
+		created, err := router.catalogServer.CreateAuthor(context.Background(),
+			&cpb.CreateAuthorRequest{Author: author},
+		)
+		So(err, ShouldBeNil)
+		So(created.Id, ShouldEqual, author.Id)
+		So(created.Version, ShouldEqual, repository.InitialVersion)
+		author.Version = created.Version
+
+		Convey("Getting the author should return the full profile", func() {
+			res, err := router.catalogServer.GetAuthor(context.Background(),
+				&cpb.GetAuthorRequest{Id: author.Id},
+			)
+			So(err, ShouldBeNil)
+			So(proto.Equal(res, author), ShouldBeTrue)
+		})
+
+		Convey("Updating the author should replace the profile", func() {
+			updated := proto.Clone(author).(*cpb.Author)
+			updated.Biography = "Athenian philosopher"
+			updated.DeathDate = nil
+			res, err := router.catalogServer.UpdateAuthor(context.Background(),
+				&cpb.UpdateAuthorRequest{Author: updated, ExpectedVersion: author.Version},
+			)
+			So(err, ShouldBeNil)
+			updated.Version = author.Version + 1
+			So(proto.Equal(res, updated), ShouldBeTrue)
+		})
+
+		Convey("Changing the author at another version should be aborted", func() {
+			_, err := router.catalogServer.UpdateAuthor(context.Background(),
+				&cpb.UpdateAuthorRequest{Author: author, ExpectedVersion: author.Version + 1},
+			)
+			So(status.Code(err), ShouldEqual, codes.Aborted)
+			_, err = router.catalogServer.PatchAuthor(context.Background(),
+				&cpb.PatchAuthorRequest{
+					Author:          &cpb.Author{Id: author.Id, Nationality: "Athenian"},
+					UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"nationality"}},
+					ExpectedVersion: author.Version + 1,
+				},
+			)
+			So(status.Code(err), ShouldEqual, codes.Aborted)
+			_, err = router.catalogServer.DeleteAuthor(context.Background(),
+				&cpb.DeleteAuthorRequest{Id: author.Id, ExpectedVersion: author.Version + 1},
+			)
+			So(status.Code(err), ShouldEqual, codes.Aborted)
+		})
+
+		Convey("Deleting the author at its version should hide it until it is restored", func() {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor", "editor"))
+			res, err := router.catalogServer.DeleteAuthor(ctx,
+				&cpb.DeleteAuthorRequest{Id: author.Id, ExpectedVersion: author.Version},
+			)
+			So(err, ShouldBeNil)
+			So(res.Success, ShouldBeTrue)
+			_, err = router.catalogServer.GetAuthor(context.Background(), &cpb.GetAuthorRequest{Id: author.Id})
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+
+			deleted, err := router.catalogServer.ListDeletedAuthors(context.Background(), &emptypb.Empty{})
+			So(err, ShouldBeNil)
+			So(len(deleted.Authors), ShouldEqual, 1)
+			So(deleted.Authors[0].DeletedBy, ShouldEqual, "editor")
+			So(deleted.Authors[0].DeletedAt, ShouldNotBeNil)
+
+			_, err = router.catalogServer.PurgeAuthor(context.Background(), &cpb.PurgeAuthorRequest{Id: author.Id})
+			So(status.Code(err), ShouldEqual, codes.FailedPrecondition)
+			purged, err := router.catalogServer.PurgeExpiredAuthors(context.Background(), &emptypb.Empty{})
+			So(err, ShouldBeNil)
+			So(purged.PurgedIds, ShouldBeEmpty)
+
+			restored, err := router.catalogServer.RestoreAuthor(context.Background(),
+				&cpb.RestoreAuthorRequest{Id: author.Id, ExpectedVersion: deleted.Authors[0].Version},
+			)
+			So(err, ShouldBeNil)
+			So(restored.DeletedAt, ShouldBeNil)
+			So(restored.Name, ShouldEqual, author.Name)
+		})
+
+		Convey("Updating through the shared service should keep the profile", func() {
+			_, err := router.server.UpdateAuthor(context.Background(),
+				&pb.UpdateAuthorRequest{Author: &pb.Author{Id: author.Id, Name: "Platon"}},
+			)
+			So(err, ShouldBeNil)
+			res, _ := router.catalogServer.GetAuthor(context.Background(),
+				&cpb.GetAuthorRequest{Id: author.Id},
+			)
+			So(res.Name, ShouldEqual, "Platon")
+			So(res.Biography, ShouldEqual, author.Biography)
+			So(res.Occupations, ShouldResemble, author.Occupations)
+		})
+
+		Convey("Patching the author should only change the fields in the mask", func() {
+			res, err := router.catalogServer.PatchAuthor(context.Background(),
+				&cpb.PatchAuthorRequest{
+					Author:     &cpb.Author{Id: author.Id, Name: "Ignored", Nationality: "Athenian"},
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"nationality", "deathDate"}},
+				},
+			)
+			So(err, ShouldBeNil)
+			So(res.Nationality, ShouldEqual, "Athenian")
+			So(res.DeathDate, ShouldBeNil)
+			So(res.Name, ShouldEqual, author.Name)
+			So(res.Biography, ShouldEqual, author.Biography)
+		})
+
+		Convey("Patching with an invalid mask should fail", func() {
+			for _, paths := range [][]string{nil, {"id"}} {
+				_, err := router.catalogServer.PatchAuthor(context.Background(),
+					&cpb.PatchAuthorRequest{
+						Author:     &cpb.Author{Id: author.Id},
+						UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+					},
+				)
+				So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			}
+			_, err := router.catalogServer.PatchAuthor(context.Background(), &cpb.PatchAuthorRequest{})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Creating an author with an invalid date should fail", func() {
+			invalid := &cpb.Author{Name: faker.Name(), BirthDate: &cpb.PartialDate{Year: 1900, Month: 13}}
+			res, err := router.catalogServer.CreateAuthor(context.Background(),
+				&cpb.CreateAuthorRequest{Author: invalid},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Creating or updating a nil author should fail", func() {
+			_, err := router.catalogServer.CreateAuthor(context.Background(), &cpb.CreateAuthorRequest{})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = router.catalogServer.UpdateAuthor(context.Background(), &cpb.UpdateAuthorRequest{})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Getting an author that does not exist should fail", func() {
+			_, err := router.catalogServer.GetAuthor(context.Background(), &cpb.GetAuthorRequest{Id: faker.UUID()})
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+		})
+	})
+
+	Convey("With authors in the database", t, func() {
+		router := createGrpcRouter()
+		for i := 0; i < 3; i++ {
+			_, _ = router.server.CreateAuthor(context.Background(),
+				&pb.CreateAuthorRequest{Author: &pb.Author{Id: faker.UUID(), Name: faker.Name()}},
+			)
+		}
+
+		Convey("When listing the authors by page", func() {
+			first, err := router.catalogServer.ListAuthorsPage(context.Background(),
+				&cpb.ListAuthorsPageRequest{PageSize: 2},
+			)
+			So(err, ShouldBeNil)
+			So(len(first.Authors), ShouldEqual, 2)
+			So(first.NextPageToken, ShouldNotBeEmpty)
+
+			second, err := router.catalogServer.ListAuthorsPage(context.Background(),
+				&cpb.ListAuthorsPageRequest{PageSize: 2, PageToken: first.NextPageToken},
+			)
+			So(err, ShouldBeNil)
+			So(len(second.Authors), ShouldEqual, 1)
+			So(second.NextPageToken, ShouldBeEmpty)
+		})
+
+		Convey("When searching the authors", func() {
+			_, _ = router.server.CreateAuthor(context.Background(),
+				&pb.CreateAuthorRequest{Author: &pb.Author{Id: faker.UUID(), Name: "Søren Kierkegaard"}},
+			)
+			res, err := router.catalogServer.SearchAuthors(context.Background(),
+				&cpb.SearchAuthorsRequest{Query: "kierke", Limit: 5},
+			)
+			So(err, ShouldBeNil)
+			So(len(res.Authors), ShouldEqual, 1)
+			So(res.Authors[0].Name, ShouldEqual, "Søren Kierkegaard")
+		})
+
+		Convey("When searching with an empty query", func() {
+			res, err := router.catalogServer.SearchAuthors(context.Background(),
+				&cpb.SearchAuthorsRequest{},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("When listing with an invalid page token", func() {
+			res, err := router.catalogServer.ListAuthorsPage(context.Background(),
+				&cpb.ListAuthorsPageRequest{PageToken: "invalid"},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+	})
+}
+
+func TestGrpcCatalogHistory(t *testing.T) {
+	Convey("Given an author that was deleted", t, func() {
+		router := createGrpcRouter()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor", "editor"))
+		created, err := router.catalogServer.CreateAuthor(ctx, &cpb.CreateAuthorRequest{Author: &cpb.Author{Name: "Plato"}})
+		So(err, ShouldBeNil)
+		_, err = router.catalogServer.DeleteAuthor(ctx, &cpb.DeleteAuthorRequest{Id: created.Id})
+		So(err, ShouldBeNil)
+
+		Convey("Its history should return the changes with the authors before and after them", func() {
+			res, err := router.catalogServer.GetAuthorHistory(ctx, &cpb.GetAuthorHistoryRequest{Id: created.Id})
+			So(err, ShouldBeNil)
+			So(res.Entries, ShouldHaveLength, 2)
+			So(res.Entries[0].Action, ShouldEqual, repository.AuditCreated)
+			So(res.Entries[0].Before, ShouldBeNil)
+			So(res.Entries[0].After.Name, ShouldEqual, "Plato")
+			So(res.Entries[1].Action, ShouldEqual, repository.AuditDeleted)
+			So(res.Entries[1].Actor, ShouldEqual, "editor")
+			So(res.Entries[1].After.DeletedBy, ShouldEqual, "editor")
+		})
+
+		Convey("The history of an unknown author should fail with NotFound", func() {
+			_, err := router.catalogServer.GetAuthorHistory(ctx, &cpb.GetAuthorHistoryRequest{Id: "unknown"})
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+		})
+	})
+}
+
+func TestGrpcCatalogRevisions(t *testing.T) {
+	Convey("Given an author that was renamed", t, func() {
+		router := createGrpcRouter()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor", "editor"))
+		created, err := router.catalogServer.CreateAuthor(ctx, &cpb.CreateAuthorRequest{Author: &cpb.Author{Name: "Plato"}})
+		So(err, ShouldBeNil)
+		_, err = router.catalogServer.UpdateAuthor(ctx, &cpb.UpdateAuthorRequest{Author: &cpb.Author{Id: created.Id, Name: "Aristocles"}})
+		So(err, ShouldBeNil)
+
+		Convey("Its revisions should be listed from the oldest", func() {
+			res, err := router.catalogServer.ListAuthorRevisions(ctx, &cpb.ListAuthorRevisionsRequest{Id: created.Id})
+			So(err, ShouldBeNil)
+			So(res.Revisions, ShouldHaveLength, 2)
+			So(res.Revisions[0].Version, ShouldEqual, 1)
+			So(res.Revisions[0].Author.Name, ShouldEqual, "Plato")
+			So(res.Revisions[1].Author.Name, ShouldEqual, "Aristocles")
+		})
+
+		Convey("A revision should be returned by version or time", func() {
+			revision, err := router.catalogServer.GetAuthorRevision(ctx, &cpb.GetAuthorRevisionRequest{
+				Id:       created.Id,
+				Selector: &cpb.GetAuthorRevisionRequest_Version{Version: 1},
+			})
+			So(err, ShouldBeNil)
+			So(revision.Author.Name, ShouldEqual, "Plato")
+
+			revision, err = router.catalogServer.GetAuthorRevision(ctx, &cpb.GetAuthorRevisionRequest{
+				Id:       created.Id,
+				Selector: &cpb.GetAuthorRevisionRequest_Time{Time: timestamppb.New(time.Now().Add(time.Hour))},
+			})
+			So(err, ShouldBeNil)
+			So(revision.Version, ShouldEqual, 2)
+
+			_, err = router.catalogServer.GetAuthorRevision(ctx, &cpb.GetAuthorRevisionRequest{Id: created.Id})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Reverting to a revision should record the actor", func() {
+			author, err := router.catalogServer.RevertAuthor(ctx, &cpb.RevertAuthorRequest{Id: created.Id, Version: 1, ExpectedVersion: 2})
+			So(err, ShouldBeNil)
+			So(author.Name, ShouldEqual, "Plato")
+			So(author.Version, ShouldEqual, 3)
+
+			res, err := router.catalogServer.GetAuthorHistory(ctx, &cpb.GetAuthorHistoryRequest{Id: created.Id})
+			So(err, ShouldBeNil)
+			So(res.Entries[2].Action, ShouldEqual, repository.AuditReverted)
+			So(res.Entries[2].Actor, ShouldEqual, "editor")
+		})
+
+		Convey("Reverting an author changed in between should fail with Aborted", func() {
+			_, err := router.catalogServer.RevertAuthor(ctx, &cpb.RevertAuthorRequest{Id: created.Id, Version: 1, ExpectedVersion: 1})
+			So(status.Code(err), ShouldEqual, codes.Aborted)
+		})
+	})
+}
+
+// recordingEventServer records the events sent to an author watcher.
+type recordingEventServer struct {
+	ctx  context.Context
+	sent chan *cpb.AuthorEvent
+	grpc.ServerStream
+}
+
+func (r *recordingEventServer) Send(event *cpb.AuthorEvent) error {
+	r.sent <- event
+	return nil
+}
+
+func (r *recordingEventServer) Context() context.Context {
+	return r.ctx
+}
+
+func TestGrpcCatalogWatch(t *testing.T) {
+	Convey("Given a watcher of the authors", t, func() {
+		s := New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository()))
+		catalog := newCatalogServer(s)
+		ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor", "editor")))
+		defer cancel()
+		stream := &recordingEventServer{ctx: ctx, sent: make(chan *cpb.AuthorEvent, 10)}
+		watchErr := make(chan error, 1)
+		watch := func(ids ...string) {
+			go func() { watchErr <- catalog.WatchAuthors(&cpb.WatchAuthorsRequest{AuthorIds: ids}, stream) }()
+			for s.(*service).broker.Subscribers() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		Convey("The changes of the authors should be sent as they happen", func() {
+			watch()
+			created, err := catalog.CreateAuthor(ctx, &cpb.CreateAuthorRequest{Author: &cpb.Author{Name: "Plato"}})
+			So(err, ShouldBeNil)
+			_, err = catalog.PatchAuthor(ctx, &cpb.PatchAuthorRequest{
+				Author:     &cpb.Author{Id: created.Id, Nationality: "Greek"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"nationality"}},
+			})
+			So(err, ShouldBeNil)
+			_, err = catalog.DeleteAuthor(ctx, &cpb.DeleteAuthorRequest{Id: created.Id})
+			So(err, ShouldBeNil)
+
+			event := <-stream.sent
+			So(event.Type, ShouldEqual, events.Created)
+			So(event.AuthorId, ShouldEqual, created.Id)
+			So(event.Version, ShouldEqual, 1)
+			So(event.Actor, ShouldEqual, "editor")
+			So(event.Author.Name, ShouldEqual, "Plato")
+			event = <-stream.sent
+			So(event.Type, ShouldEqual, events.Updated)
+			So(event.Version, ShouldEqual, 2)
+			So(event.Author.Nationality, ShouldEqual, "Greek")
+			event = <-stream.sent
+			So(event.Type, ShouldEqual, events.Deleted)
+			So(event.Author, ShouldBeNil)
+
+			cancel()
+			So(status.Code(<-watchErr), ShouldEqual, codes.Canceled)
+			So(s.(*service).broker.Subscribers(), ShouldEqual, 0)
+		})
+
+		Convey("Only the changes of the watched authors should be sent", func() {
+			watch("watched")
+			_, _ = s.CreateAuthor(ctx, data.Author{ID: "other", Name: "Homer"})
+			_, _ = s.CreateAuthor(ctx, data.Author{ID: "watched", Name: "Sappho"})
+			So((<-stream.sent).AuthorId, ShouldEqual, "watched")
+		})
+
+		Convey("Shutting down should end the watch", func() {
+			watch()
+			catalog.shutdown()
+			So(<-watchErr, ShouldBeNil)
+		})
+	})
+}
+
+// bulkRequestStream streams its requests to a bulk writer and records the response.
+type bulkRequestStream struct {
+	ctx      context.Context
+	requests []*cpb.BulkWriteAuthorsRequest
+	response *cpb.BulkWriteAuthorsResponse
+	grpc.ServerStream
+}
+
+func (b *bulkRequestStream) Recv() (*cpb.BulkWriteAuthorsRequest, error) {
+	if len(b.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := b.requests[0]
+	b.requests = b.requests[1:]
+	return request, nil
+}
+
+func (b *bulkRequestStream) SendAndClose(response *cpb.BulkWriteAuthorsResponse) error {
+	b.response = response
+	return nil
+}
+
+func (b *bulkRequestStream) Context() context.Context {
+	return b.ctx
+}
+
+func TestGrpcCatalogBulkWrite(t *testing.T) {
+	Convey("Given an author", t, func() {
+		router := createGrpcRouter()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor", "editor"))
+		created, err := router.catalogServer.CreateAuthor(ctx, &cpb.CreateAuthorRequest{Author: &cpb.Author{Name: "Plato"}})
+		So(err, ShouldBeNil)
+
+		Convey("A batch should return the result of each operation", func() {
+			res, err := router.catalogServer.BulkWriteAuthors(ctx, &cpb.BulkWriteAuthorsRequest{
+				Mode: string(repository.BulkBestEffort),
+				Operations: []*cpb.BulkOperation{
+					{Kind: repository.BulkCreate, Author: &cpb.Author{Name: "Aristotle"}},
+					{Kind: repository.BulkUpdate, Author: &cpb.Author{Id: created.Id, Name: "Aristocles"}, ExpectedVersion: 2},
+					{Kind: repository.BulkDelete, Id: created.Id},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(res.Applied, ShouldEqual, 1)
+			So(res.Failed, ShouldEqual, 2)
+			So(res.Results[0].Code, ShouldEqual, codes.OK)
+			So(res.Results[0].Author.Name, ShouldEqual, "Aristotle")
+			So(res.Results[1].Code, ShouldEqual, codes.Aborted)
+			So(res.Results[1].Author, ShouldBeNil)
+			So(res.Results[2].Code, ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("An invalid batch should fail", func() {
+			_, err := router.catalogServer.BulkWriteAuthors(ctx, &cpb.BulkWriteAuthorsRequest{})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = router.catalogServer.BulkWriteAuthors(ctx, &cpb.BulkWriteAuthorsRequest{
+				Mode:       "eventually",
+				Operations: []*cpb.BulkOperation{{Kind: repository.BulkDelete, Id: created.Id}},
+			})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Streamed operations should be applied as a single atomic batch", func() {
+			stream := &bulkRequestStream{ctx: ctx, requests: []*cpb.BulkWriteAuthorsRequest{
+				{Operations: []*cpb.BulkOperation{{Kind: repository.BulkCreate, Author: &cpb.Author{Name: "Aristotle"}}}},
+				{Mode: string(repository.BulkBestEffort), Operations: []*cpb.BulkOperation{{Kind: repository.BulkDelete, Id: "unknown"}}},
+			}}
+			So(router.catalogServer.StreamWriteAuthors(stream), ShouldBeNil)
+			So(stream.response.Applied, ShouldEqual, 0)
+			So(stream.response.Results[0].Code, ShouldEqual, codes.Aborted)
+			So(stream.response.Results[1].Code, ShouldEqual, codes.NotFound)
+		})
+
+		Convey("A stream of too many operations should fail", func() {
+			operations := make([]*cpb.BulkOperation, MaxBulkOperations+1)
+			for index := range operations {
+				operations[index] = &cpb.BulkOperation{Kind: repository.BulkDelete, Id: faker.UUID()}
+			}
+			stream := &bulkRequestStream{ctx: ctx, requests: []*cpb.BulkWriteAuthorsRequest{{Operations: operations}}}
+			So(status.Code(router.catalogServer.StreamWriteAuthors(stream)), ShouldEqual, codes.InvalidArgument)
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// healthWatchInterval is how often the readiness is checked for the watchers
+// of the gRPC health service.
+const healthWatchInterval = 5 * time.Second
+
+// healthServer implements the standard gRPC health service on top of the
+// readiness of the service. The empty service name and the names of the
+// exposed gRPC services are known.
+type healthServer struct {
+	service      Service
+	services     map[string]bool
+	interval     time.Duration
+	done         chan struct{}
+	shutdownOnce sync.Once
+	healthpb.UnimplementedHealthServer
+}
+
+func newHealthServer(s Service, services ...string) *healthServer {
+	known := map[string]bool{"": true}
+	for _, name := range services {
+		known[name] = true
+	}
+	return &healthServer{
+		service:  s,
+		services: known,
+		interval: healthWatchInterval,
+		done:     make(chan struct{}),
+	}
+}
+
+// Check returns whether the service is ready to serve calls.
+func (h *healthServer) Check(ctx context.Context, request *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !h.services[request.GetService()] {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", request.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: h.servingStatus(ctx)}, nil
+}
+
+// Watch sends the serving status of the service whenever it changes, until the
+// call is canceled or the server shuts down.
+func (h *healthServer) Watch(request *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	var last healthpb.HealthCheckResponse_ServingStatus = -1
+	for {
+		current := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if h.services[request.GetService()] {
+			current = h.servingStatus(ctx)
+		}
+		if current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			last = current
+		}
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-h.done:
+			if last != healthpb.HealthCheckResponse_NOT_SERVING {
+				_ = stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus returns NOT_SERVING once the server shuts down or when a
+// critical dependency is unavailable.
+func (h *healthServer) servingStatus(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	select {
+	case <-h.done:
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	default:
+	}
+	if !h.service.Readiness(ctx).Ready() {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+// shutdown reports NOT_SERVING from now on and ends the ongoing watches, so
+// that they do not hold a graceful stop of the server.
+func (h *healthServer) shutdown() {
+	h.shutdownOnce.Do(func() {
+		close(h.done)
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recordingWatchServer records the statuses sent to a health watcher.
+type recordingWatchServer struct {
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+	grpc.ServerStream
+}
+
+func (r *recordingWatchServer) Send(response *healthpb.HealthCheckResponse) error {
+	r.sent <- response.Status
+	return nil
+}
+
+func (r *recordingWatchServer) Context() context.Context {
+	return r.ctx
+}
+
+func (r *recordingWatchServer) SetHeader(metadata.MD) error { return nil }
+
+func TestGrpcHealth(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a health server", t, func() {
+		db := &switchableDatabase{Database: repository.NewInMemoryDatabase()}
+		repo := repository.New(db, repository.NewFakeClientRepository())
+		health := newHealthServer(New(repo), pb.AuthorService_ServiceDesc.ServiceName)
+		health.interval = 10 * time.Millisecond
+
+		Convey("Checking the server should report it serving", func() {
+			res, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+			res, err = health.Check(ctx, &healthpb.HealthCheckRequest{Service: pb.AuthorService_ServiceDesc.ServiceName})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+		})
+
+		Convey("Checking an unknown service should fail", func() {
+			_, err := health.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+		})
+
+		Convey("Checking the server without database should report it not serving", func() {
+			db.setDown(true)
+			res, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+			So(err, ShouldBeNil)
+			So(res.Status, ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+		})
+
+		Convey("Watching the server should send the status changes", func() {
+			stream := &recordingWatchServer{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+			watchErr := make(chan error, 1)
+			go func() { watchErr <- health.Watch(&healthpb.HealthCheckRequest{}, stream) }()
+
+			So(<-stream.sent, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+			db.setDown(true)
+			So(<-stream.sent, ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+			db.setDown(false)
+			So(<-stream.sent, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+			Convey("Shutting down should end the watch", func() {
+				health.shutdown()
+				So(<-watchErr, ShouldBeNil)
+				So(<-stream.sent, ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+
+				res, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+				So(err, ShouldBeNil)
+				So(res.Status, ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+			})
+		})
+
+		Convey("Watching an unknown service should report it unknown", func() {
+			stream := &recordingWatchServer{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+			go func() { _ = health.Watch(&healthpb.HealthCheckRequest{Service: "unknown"}, stream) }()
+			So(<-stream.sent, ShouldEqual, healthpb.HealthCheckResponse_SERVICE_UNKNOWN)
+			health.shutdown()
+		})
+	})
+}
@@ -2,11 +2,18 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/wcodesoft/mosha-author-service/repository"
 	pb "github.com/wcodesoft/mosha-service-common/protos/authorservice"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	faker "github.com/brianvoe/gofakeit/v6"
@@ -21,6 +28,21 @@ func createGrpcRouter() GrpcRouter {
 	return router
 }
 
+func TestStatusCode(t *testing.T) {
+	Convey("When converting errors to gRPC codes", t, func() {
+		So(statusCode(fmt.Errorf("wrapped: %w", repository.ErrNotFound)), ShouldEqual, codes.NotFound)
+		So(statusCode(repository.ErrAlreadyExists), ShouldEqual, codes.AlreadyExists)
+		So(statusCode(repository.ErrInvalidArgument), ShouldEqual, codes.InvalidArgument)
+		So(statusCode(repository.ErrDependencyFailed), ShouldEqual, codes.Unavailable)
+		So(statusCode(repository.ErrConflict), ShouldEqual, codes.Aborted)
+		So(statusCode(repository.ErrAborted), ShouldEqual, codes.Aborted)
+		So(statusCode(repository.ErrFailedPrecondition), ShouldEqual, codes.FailedPrecondition)
+		So(statusCode(context.DeadlineExceeded), ShouldEqual, codes.DeadlineExceeded)
+		So(statusCode(context.Canceled), ShouldEqual, codes.Canceled)
+		So(statusCode(fmt.Errorf("unexpected")), ShouldEqual, codes.Internal)
+	})
+}
+
 func TestGrpc(t *testing.T) {
 	id := faker.UUID()
 	name := faker.Name()
@@ -96,11 +118,47 @@ func TestGrpc(t *testing.T) {
 			Convey("The response should be nil", func() {
 				So(res, ShouldBeNil)
 			})
-			Convey("The error should not be nil", func() {
-				So(err, ShouldNotBeNil)
+			Convey("The error should be not found", func() {
+				So(status.Code(err), ShouldEqual, codes.NotFound)
 			})
 		})
 
+		Convey("When adding an author that already exists", func() {
+			res, err := router.server.CreateAuthor(context.Background(),
+				&pb.CreateAuthorRequest{Author: author},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.AlreadyExists)
+		})
+
+		Convey("When adding an author with invalid fields", func() {
+			res, err := router.server.CreateAuthor(context.Background(),
+				&pb.CreateAuthorRequest{Author: &pb.Author{Id: "invalid id", Name: name}},
+			)
+			So(res, ShouldBeNil)
+			st := status.Convert(err)
+			So(st.Code(), ShouldEqual, codes.InvalidArgument)
+			So(len(st.Details()), ShouldEqual, 1)
+			badRequest := st.Details()[0].(*errdetails.BadRequest)
+			So(badRequest.FieldViolations[0].Field, ShouldEqual, "id")
+		})
+
+		Convey("When adding a nil author", func() {
+			res, err := router.server.CreateAuthor(context.Background(),
+				&pb.CreateAuthorRequest{},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("When getting an author that does not exist", func() {
+			res, err := router.server.GetAuthor(context.Background(),
+				&pb.GetAuthorRequest{Id: faker.UUID()},
+			)
+			So(res, ShouldBeNil)
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+		})
+
 		Convey("When updating the author", func() {
 			res, err := router.server.UpdateAuthor(context.Background(),
 				&pb.UpdateAuthorRequest{Author: updatedAuthor},
@@ -136,3 +194,57 @@ func TestGrpc(t *testing.T) {
 		})
 	})
 }
+
+func TestGrpcRouterLifecycle(t *testing.T) {
+	Convey("Given a started gRPC router", t, func() {
+		router := createGrpcRouter()
+		started := make(chan error, 1)
+		go func() { started <- router.Start("18381") }()
+		time.Sleep(50 * time.Millisecond)
+
+		conn, err := grpc.Dial("localhost:18381", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		_, err = pb.NewAuthorServiceClient(conn).ListAuthors(context.Background(), &emptypb.Empty{})
+		So(err, ShouldBeNil)
+
+		Convey("Stopping it should make Start return without error", func() {
+			So(router.Stop(context.Background()), ShouldBeNil)
+			So(<-started, ShouldBeNil)
+		})
+	})
+
+	Convey("Stopping a router that was not started should succeed", t, func() {
+		router := createGrpcRouter()
+		So(router.Stop(context.Background()), ShouldBeNil)
+		So(router.Start("18382"), ShouldBeNil)
+	})
+}
+
+func TestGrpcRouterInterceptors(t *testing.T) {
+	Convey("Given a router with interceptors", t, func() {
+		var methods []string
+		unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			methods = append(methods, info.FullMethod)
+			return handler(ctx, req)
+		}
+		stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, ss)
+		}
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		router := NewGrpcRouter(New(repo), "AuthorService", WithInterceptors(unary, stream))
+		go func() { _ = router.Start("18383") }()
+		defer router.Stop(context.Background())
+		time.Sleep(50 * time.Millisecond)
+
+		conn, err := grpc.Dial("localhost:18383", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		_, err = pb.NewAuthorServiceClient(conn).ListAuthors(context.Background(), &emptypb.Empty{})
+		So(err, ShouldBeNil)
+
+		Convey("The interceptors should run on the calls", func() {
+			So(methods, ShouldResemble, []string{"/authorservice.AuthorService/ListAuthors"})
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthCheckTimeout bounds the check of each dependency.
+const HealthCheckTimeout = 2 * time.Second
+
+// HealthStatus is the health of the service or of one of its dependencies.
+type HealthStatus string
+
+const (
+	// HealthOK means that everything is available.
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded means that a dependency that is not critical is unavailable.
+	// The service is still ready to serve requests.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthUnavailable means that a critical dependency is unavailable.
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// DependencyHealth is the outcome of the check of a dependency.
+type DependencyHealth struct {
+	Status   HealthStatus `json:"status"`
+	Critical bool         `json:"critical"`
+	Error    string       `json:"error,omitempty"`
+	Latency  string       `json:"latency"`
+}
+
+// HealthReport is the readiness of the service, with the detail of each dependency.
+type HealthReport struct {
+	Status       HealthStatus                `json:"status"`
+	Dependencies map[string]DependencyHealth `json:"dependencies,omitempty"`
+}
+
+// Ready reports whether the service can serve requests.
+func (r HealthReport) Ready() bool {
+	return r.Status != HealthUnavailable
+}
+
+// Readiness checks the dependencies concurrently, each within HealthCheckTimeout.
+func (s *service) Readiness(ctx context.Context) HealthReport {
+	dependencies := s.repo.Dependencies()
+	report := HealthReport{
+		Status:       HealthOK,
+		Dependencies: make(map[string]DependencyHealth, len(dependencies)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dependency := range dependencies {
+		dependency := dependency
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			err := dependency.Ping(checkCtx)
+			health := DependencyHealth{
+				Status:   HealthOK,
+				Critical: dependency.Critical,
+				Latency:  time.Since(start).String(),
+			}
+			if err != nil {
+				health.Status = HealthUnavailable
+				health.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[dependency.Name] = health
+			switch {
+			case err == nil:
+			case dependency.Critical:
+				report.Status = HealthUnavailable
+			case report.Status == HealthOK:
+				report.Status = HealthDegraded
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/repository"
+)
+
+// switchableDatabase is an in-memory database whose ping fails while it is down.
+type switchableDatabase struct {
+	repository.Database
+	mu   sync.Mutex
+	down bool
+}
+
+func (d *switchableDatabase) setDown(down bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.down = down
+}
+
+func (d *switchableDatabase) Ping(_ context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.down {
+		return errors.New("server selection timeout")
+	}
+	return nil
+}
+
+func TestReadiness(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("When all the dependencies are available", t, func() {
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		report := New(repo).Readiness(ctx)
+
+		Convey("The service should be ready", func() {
+			So(report.Status, ShouldEqual, HealthOK)
+			So(report.Ready(), ShouldBeTrue)
+			So(report.Dependencies[repository.DependencyDatabase].Status, ShouldEqual, HealthOK)
+			So(report.Dependencies[repository.DependencyQuoteService].Status, ShouldEqual, HealthOK)
+		})
+	})
+
+	Convey("When QuoteService is unavailable", t, func() {
+		clientRepo := repository.NewFakeClientRepository()
+		clientRepo.SetPingError(repository.ErrCircuitOpen)
+		repo := repository.New(repository.NewInMemoryDatabase(), clientRepo)
+		report := New(repo).Readiness(ctx)
+
+		Convey("The service should be degraded but ready", func() {
+			So(report.Status, ShouldEqual, HealthDegraded)
+			So(report.Ready(), ShouldBeTrue)
+			quoteService := report.Dependencies[repository.DependencyQuoteService]
+			So(quoteService.Status, ShouldEqual, HealthUnavailable)
+			So(quoteService.Critical, ShouldBeFalse)
+			So(quoteService.Error, ShouldEqual, repository.ErrCircuitOpen.Error())
+		})
+	})
+
+	Convey("When the database is unavailable", t, func() {
+		clientRepo := repository.NewFakeClientRepository()
+		clientRepo.SetPingError(repository.ErrCircuitOpen)
+		db := &switchableDatabase{Database: repository.NewInMemoryDatabase(), down: true}
+		report := New(repository.New(db, clientRepo)).Readiness(ctx)
+
+		Convey("The service should not be ready", func() {
+			So(report.Status, ShouldEqual, HealthUnavailable)
+			So(report.Ready(), ShouldBeFalse)
+			database := report.Dependencies[repository.DependencyDatabase]
+			So(database.Status, ShouldEqual, HealthUnavailable)
+			So(database.Critical, ShouldBeTrue)
+			So(database.Error, ShouldEqual, "server selection timeout")
+		})
+	})
+}
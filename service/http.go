@@ -2,13 +2,22 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/wcodesoft/mosha-author-service/auth"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/metrics"
+	"github.com/wcodesoft/mosha-author-service/repository"
 	mhttp "github.com/wcodesoft/mosha-service-common/http"
+	"io"
 
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // AuthorService represents the service interface.
@@ -16,9 +25,23 @@ type AuthorService struct {
 	Service Service
 	Name    string
 	Port    string
+	// Metrics, when set, instruments the requests and exposes /metrics.
+	Metrics *metrics.Metrics
+	// Middlewares run first on every request, such as tracing.
+	Middlewares []func(http.Handler) http.Handler
+	// Auth, when set, authenticates the requests and restricts the routes to
+	// the callers with the required role.
+	Auth *auth.Authenticator
+	// MaxImportBytes is the size of the largest import body, beyond which the
+	// import is refused with 413 Request Entity Too Large. Zero stands for
+	// DefaultMaxImportBytes.
+	MaxImportBytes int64
 	mhttp.MoshaHttpService
 }
 
+// DefaultMaxImportBytes is the size of the largest import body by default.
+const DefaultMaxImportBytes = 32 << 20
+
 // GetName returns the name of the service.
 func (as *AuthorService) GetName() string {
 	return as.Name
@@ -36,14 +59,52 @@ func (as *AuthorService) MakeHandler() http.Handler {
 		Repanic: true,
 	})
 
-	r.Use(middleware.Logger)
+	r.Use(as.Middlewares...)
+	if as.Metrics != nil {
+		r.Use(as.Metrics.HttpMiddleware)
+	}
+	r.Use(requestIDMiddleware)
+	r.Use(accessLogMiddleware)
 	r.Use(middleware.Recoverer)
 	r.Use(sentryHandler.Handle)
-	r.Get("/api/v1/author/all", as.listAllHandler)
-	r.Get("/api/v1/author/{id}", as.createGetAuthorHandler)
-	r.Post("/api/v1/author/delete/{id}", as.deleteAuthorHandler)
-	r.Post("/api/v1/author/update", as.updateAuthorHandler)
-	r.Post("/api/v1/author", as.addAuthorHandler)
+	if as.Auth != nil {
+		r.Use(as.Auth.HttpMiddleware)
+	}
+	r.Use(actorMiddleware)
+	if as.Metrics != nil {
+		r.Method(http.MethodGet, "/metrics", as.Metrics.Handler())
+	}
+	r.Get("/healthz", as.livenessHandler)
+	r.Get("/readyz", as.readinessHandler)
+	r.Group(func(r chi.Router) {
+		r.Use(as.require(auth.RoleReader))
+		r.Get("/api/v1/author/all", as.listAllHandler)
+		r.Get("/api/v1/author/search", as.searchAuthorsHandler)
+		r.Get("/api/v1/author/export", as.exportAuthorsHandler)
+		r.Get("/api/v1/author/{id}", as.createGetAuthorHandler)
+		r.Get("/api/v1/author/{id}/history", as.authorHistoryHandler)
+		r.Get("/api/v1/author/{id}/revisions", as.listRevisionsHandler)
+		r.Get("/api/v1/author/{id}/revisions/at", as.getRevisionAtHandler)
+		r.Get("/api/v1/author/{id}/revisions/{version}", as.getRevisionHandler)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(as.require(auth.RoleEditor))
+		r.Post("/api/v1/author/delete/{id}", as.deleteAuthorHandler)
+		r.Post("/api/v1/author/restore/{id}", as.restoreAuthorHandler)
+		r.Post("/api/v1/author/update", as.updateAuthorHandler)
+		r.Patch("/api/v1/author/{id}", as.patchAuthorHandler)
+		r.Post("/api/v1/author/{id}/revisions/{version}/revert", as.revertAuthorHandler)
+		r.Post("/api/v1/author", as.addAuthorHandler)
+		r.Post("/api/v1/author/batch", as.bulkWriteHandler)
+		r.Post("/api/v1/author/import", as.importAuthorsHandler)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(as.require(auth.RoleAdmin))
+		r.Get("/api/v1/author/deleted", as.listDeletedHandler)
+		r.Get("/api/v1/author/outbox", as.listOutboxHandler)
+		r.Post("/api/v1/author/purge/{id}", as.purgeAuthorHandler)
+		r.Post("/api/v1/author/purge", as.purgeExpiredHandler)
+	})
 
 	return r
 }
@@ -51,14 +112,14 @@ func (as *AuthorService) MakeHandler() http.Handler {
 func (as *AuthorService) addAuthorHandler(w http.ResponseWriter, r *http.Request) {
 	var request data.Author
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, repository.InvalidArgument(err))
 		return
 	}
 
-	resp, err := as.Service.CreateAuthor(request)
+	resp, err := as.Service.CreateAuthor(r.Context(), request)
 
 	if err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, err)
 		return
 	}
 
@@ -68,48 +129,528 @@ func (as *AuthorService) addAuthorHandler(w http.ResponseWriter, r *http.Request
 func (as *AuthorService) createGetAuthorHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	resp, err := as.Service.GetAuthor(id)
+	resp, err := as.Service.GetAuthor(r.Context(), id)
 
 	if err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, err)
 		return
 	}
 
+	setETag(w, resp)
 	mhttp.EncodeResponse(w, resp)
 }
 
 func (as *AuthorService) deleteAuthorHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	err = as.Service.DeleteAuthor(r.Context(), id, version)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, mhttp.IdResponse{ID: id})
+}
+
+func (as *AuthorService) restoreAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp, err := as.Service.RestoreAuthor(r.Context(), id, version)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	setETag(w, resp)
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) listDeletedHandler(w http.ResponseWriter, r *http.Request) {
+	resp, err := as.Service.ListDeleted(r.Context())
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) listOutboxHandler(w http.ResponseWriter, r *http.Request) {
+	resp, err := as.Service.ListOutbox(r.Context())
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) purgeAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
 
-	err := as.Service.DeleteAuthor(id)
+	err := as.Service.PurgeAuthor(r.Context(), id)
 
 	if err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, err)
 		return
 	}
 
 	mhttp.EncodeResponse(w, mhttp.IdResponse{ID: id})
 }
 
+// purgeResponse represents the response of a purge of the expired authors.
+type purgeResponse struct {
+	Purged []string `json:"purged"`
+	Error  string   `json:"error,omitempty"`
+}
+
+func (as *AuthorService) purgeExpiredHandler(w http.ResponseWriter, r *http.Request) {
+	purged, err := as.Service.PurgeExpired(r.Context())
+
+	resp := purgeResponse{Purged: purged}
+	if err != nil {
+		// Some authors may have been purged before the error, they are reported too.
+		w.WriteHeader(httpStatus(err))
+		resp.Error = err.Error()
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+// bulkRequest represents a batch of operations. The mode is atomic unless it
+// is set to best_effort.
+type bulkRequest struct {
+	Mode       repository.BulkMode        `json:"mode"`
+	Operations []repository.BulkOperation `json:"operations"`
+}
+
+// bulkResponse represents the results of a batch, in the order of its operations.
+type bulkResponse struct {
+	Applied int                `json:"applied"`
+	Failed  int                `json:"failed"`
+	Results []bulkItemResponse `json:"results"`
+}
+
+// bulkItemResponse represents the result of an operation of a batch, with the
+// HTTP status the operation would have had on its own.
+type bulkItemResponse struct {
+	ID     string            `json:"id"`
+	Status int               `json:"status"`
+	Author *data.Author      `json:"author,omitempty"`
+	Error  string            `json:"error,omitempty"`
+	Fields []data.FieldError `json:"fields,omitempty"`
+}
+
+// bulkWriteHandler applies a batch of operations. It answers 200 OK when all of
+// them were applied and 207 Multi-Status when some failed.
+func (as *AuthorService) bulkWriteHandler(w http.ResponseWriter, r *http.Request) {
+	var request bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		encodeError(w, repository.InvalidArgument(err))
+		return
+	}
+	mode, err := repository.ParseBulkMode(string(request.Mode))
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	results, err := as.Service.BulkWrite(r.Context(), request.Operations, mode)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp := bulkResponse{Results: make([]bulkItemResponse, len(results))}
+	for index, result := range results {
+		item := bulkItemResponse{ID: result.ID, Status: http.StatusOK, Author: result.Author}
+		if request.Operations[index].Kind == repository.BulkCreate {
+			item.Status = http.StatusCreated
+		}
+		if result.Err != nil {
+			item = bulkItemResponse{ID: result.ID, Status: httpStatus(result.Err), Error: result.Err.Error()}
+			var verr *data.ValidationError
+			if errors.As(result.Err, &verr) {
+				item.Fields = verr.Fields
+			}
+			resp.Failed++
+		} else {
+			resp.Applied++
+		}
+		resp.Results[index] = item
+	}
+	if resp.Failed > 0 {
+		w.WriteHeader(http.StatusMultiStatus)
+	}
+	mhttp.EncodeResponse(w, resp)
+}
+
+// exportAuthorsHandler streams the authors in the format of the format query
+// parameter, ndjson by default.
+func (as *AuthorService) exportAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	format, err := repository.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"authors.%s\"", format))
+	if _, err := as.Service.ExportAuthors(r.Context(), w, format); err != nil {
+		// The response has already started, so the export is cut short.
+		repository.Logger(r.Context()).Error("unable to export the authors", "err", err)
+	}
+}
+
+// importAuthorsHandler imports the authors of the request body, in the format
+// of the format query parameter. The mode query parameter is upsert or insert,
+// and dryRun=true only checks the rows. It answers 200 OK when all the rows
+// were imported and 207 Multi-Status when some failed. A body larger than
+// MaxImportBytes is refused with 413 Request Entity Too Large, up front when
+// its length is announced and otherwise once the limit is read, the rows read
+// until then being imported.
+func (as *AuthorService) importAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := as.MaxImportBytes
+	if limit == 0 {
+		limit = DefaultMaxImportBytes
+	}
+	if r.ContentLength > limit {
+		encodeError(w, &http.MaxBytesError{Limit: limit})
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+	query := r.URL.Query()
+	format, err := repository.ParseFormat(query.Get("format"))
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+	mode, err := repository.ParseImportMode(query.Get("mode"))
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+	opts := repository.ImportOptions{Mode: mode}
+	if query.Has("dryRun") {
+		if opts.DryRun, err = strconv.ParseBool(query.Get("dryRun")); err != nil {
+			encodeError(w, fmt.Errorf("invalid dryRun %q: %w", query.Get("dryRun"), repository.ErrInvalidArgument))
+			return
+		}
+	}
+
+	report, err := as.Service.ImportAuthors(r.Context(), r.Body, format, opts)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+	if report.Failed > 0 {
+		w.WriteHeader(http.StatusMultiStatus)
+	}
+	mhttp.EncodeResponse(w, report)
+}
+
+// livenessHandler answers as long as the service serves requests, whatever the
+// state of its dependencies.
+func (as *AuthorService) livenessHandler(w http.ResponseWriter, _ *http.Request) {
+	mhttp.EncodeResponse(w, HealthReport{Status: HealthOK})
+}
+
+func (as *AuthorService) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := as.Service.Readiness(r.Context())
+
+	if !report.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	mhttp.EncodeResponse(w, report)
+}
+
 func (as *AuthorService) updateAuthorHandler(w http.ResponseWriter, r *http.Request) {
 	var request data.Author
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, repository.InvalidArgument(err))
 		return
 	}
 
-	resp, err := as.Service.UpdateAuthor(request)
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp, err := as.Service.UpdateAuthor(r.Context(), request, version)
 
 	if err != nil {
-		mhttp.EncodeError(w, err)
+		encodeError(w, err)
 		return
 	}
 
+	setETag(w, resp)
 	mhttp.EncodeResponse(w, resp)
 }
 
-func (as *AuthorService) listAllHandler(w http.ResponseWriter, _ *http.Request) {
-	resp := as.Service.ListAll()
+func (as *AuthorService) patchAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		encodeError(w, repository.InvalidArgument(err))
+		return
+	}
+	patch, err := data.ParseMergePatch(body)
+	if err != nil {
+		encodeError(w, repository.InvalidArgument(err))
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp, err := as.Service.PatchAuthor(r.Context(), id, patch, version)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	setETag(w, resp)
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) listAllHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !query.Has("pageSize") && !query.Has("pageToken") {
+		resp := as.Service.ListAll(r.Context())
+		mhttp.EncodeResponse(w, resp)
+		return
+	}
+
+	page := repository.PageRequest{Token: query.Get("pageToken")}
+	if query.Has("pageSize") {
+		size, err := strconv.Atoi(query.Get("pageSize"))
+		if err != nil {
+			encodeError(w, fmt.Errorf("invalid page size %q: %w", query.Get("pageSize"), repository.ErrInvalidArgument))
+			return
+		}
+		page.Size = size
+	}
+
+	resp, err := as.Service.ListPage(r.Context(), page)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) searchAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if query.Has("limit") {
+		value, err := strconv.Atoi(query.Get("limit"))
+		if err != nil {
+			encodeError(w, fmt.Errorf("invalid limit %q: %w", query.Get("limit"), repository.ErrInvalidArgument))
+			return
+		}
+		limit = value
+	}
+
+	resp, err := as.Service.SearchAuthors(r.Context(), query.Get("q"), limit)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) authorHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp, err := as.Service.AuthorHistory(r.Context(), id)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
 
 	mhttp.EncodeResponse(w, resp)
 }
+
+func (as *AuthorService) listRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp, err := as.Service.ListRevisions(r.Context(), id)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) getRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	version, err := revisionVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp, err := as.Service.GetRevision(r.Context(), id, version)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) getRevisionAtHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	value := r.URL.Query().Get("time")
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		encodeError(w, fmt.Errorf("invalid time %q: %w", value, repository.ErrInvalidArgument))
+		return
+	}
+
+	resp, err := as.Service.GetRevisionAt(r.Context(), id, at)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	mhttp.EncodeResponse(w, resp)
+}
+
+func (as *AuthorService) revertAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	version, err := revisionVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(r)
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	resp, err := as.Service.RevertAuthor(r.Context(), id, version, expectedVersion)
+
+	if err != nil {
+		encodeError(w, err)
+		return
+	}
+
+	setETag(w, resp)
+	mhttp.EncodeResponse(w, resp)
+}
+
+// revisionVersion returns the revision version of the route.
+func revisionVersion(r *http.Request) (int64, error) {
+	value := chi.URLParam(r, "version")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid revision %q: %w", value, repository.ErrInvalidArgument)
+	}
+	return version, nil
+}
+
+// actorMiddleware puts the actor in the request context: the authenticated
+// caller, or else the one named by the X-Actor header.
+func actorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+			r = r.WithContext(repository.WithActor(r.Context(), principal.Subject))
+		} else if actor := r.Header.Get("X-Actor"); actor != "" {
+			r = r.WithContext(repository.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setETag sets the ETag header to the version of the author.
+func setETag(w http.ResponseWriter, author data.Author) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(author.Version, 10)))
+}
+
+// ifMatchVersion returns the author version expected by the If-Match header,
+// or zero when the header is absent or "*".
+func ifMatchVersion(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header %q: %w", value, repository.ErrInvalidArgument)
+	}
+	return version, nil
+}
+
+// validationErrorResponse represents the response of an invalid author.
+type validationErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields []data.FieldError `json:"fields"`
+}
+
+// encodeError writes the error with the HTTP status code matching its kind.
+// Validation errors include the details of each invalid field.
+func encodeError(w http.ResponseWriter, err error) {
+	w.WriteHeader(httpStatus(err))
+	var verr *data.ValidationError
+	if errors.As(err, &verr) {
+		mhttp.EncodeResponse(w, validationErrorResponse{Error: err.Error(), Fields: verr.Fields})
+		return
+	}
+	mhttp.EncodeResponse(w, err.Error())
+}
+
+// httpStatus returns the HTTP status code matching the error.
+func httpStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, repository.ErrFailedPrecondition), errors.Is(err, repository.ErrAborted):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrDependencyFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/metrics"
 	"github.com/wcodesoft/mosha-author-service/repository"
 	mhttp "github.com/wcodesoft/mosha-service-common/http"
 
@@ -18,9 +21,12 @@ import (
 )
 
 func createHandler() http.Handler {
+	return createHandlerWithClient(repository.NewFakeClientRepository())
+}
+
+func createHandlerWithClient(clientRepo repository.ClientRepository, opts ...repository.Option) http.Handler {
 	memoryDatabase := repository.NewInMemoryDatabase()
-	clientRepo := repository.NewFakeClientRepository()
-	repo := repository.New(memoryDatabase, clientRepo)
+	repo := repository.New(memoryDatabase, clientRepo, opts...)
 	service := New(repo)
 	hs := AuthorService{
 		Service: service,
@@ -66,22 +72,38 @@ func TestHttp(t *testing.T) {
 		handler := createHandler()
 		author := data.NewAuthorBuilder().WithId(faker.UUID()).WithName(faker.Name()).Build()
 
-		Convey("When author already exist the response should be 500", func() {
+		Convey("When author already exist the response should be 409", func() {
 			req1 := httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author))
 			executeRequest(req1, handler)
 			req2 := httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author))
 			rr := executeRequest(req2, handler)
-			So(rr.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rr.Code, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("When author fields are invalid the response should be 400 with details", func() {
+			invalid := data.NewAuthorBuilder().WithPicUrl("invalid").Build()
+			rr := executeRequest(
+				httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(invalid)),
+				handler,
+			)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			var resp validationErrorResponse
+			_ = json.NewDecoder(rr.Body).Decode(&resp)
+			So(resp.Error, ShouldNotBeEmpty)
+			So(resp.Fields, ShouldResemble, []data.FieldError{
+				{Field: "name", Message: "must not be blank"},
+				{Field: "picUrl", Message: "must be a valid http or https URL"},
+			})
 		})
 
-		Convey("When author is invalid the response should be 500", func() {
+		Convey("When author is invalid the response should be 400", func() {
 			rr := executeRequest(
 				httptest.NewRequest("POST", "/api/v1/author",
 					jsonReaderFactory("invalid"),
 				),
 				handler,
 			)
-			So(rr.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
 		})
 	})
 
@@ -95,12 +117,94 @@ func TestHttp(t *testing.T) {
 			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s", author.ID), nil)
 			rr := executeRequest(req, handler)
 			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("ETag"), ShouldEqual, `"1"`)
 		})
 
-		Convey("When author does not exist the response should be 500", func() {
+		Convey("When author does not exist the response should be 404", func() {
 			req := httptest.NewRequest("GET", "/api/v1/author/456", nil)
 			rr := executeRequest(req, handler)
-			So(rr.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+
+	Convey("When adding an author with a profile", t, func() {
+		handler := createHandler()
+		id := faker.UUID()
+		body := fmt.Sprintf(`{"id":%q,"name":"Plato","biography":"Greek philosopher",`+
+			`"birthDate":"c. 428 BC","deathDate":"348 BC","nationality":"Greek",`+
+			`"occupations":["philosopher"],"alternateNames":["Aristocles"]}`, id)
+		rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author", bytes.NewBufferString(body)), handler)
+		So(rr.Code, ShouldEqual, http.StatusOK)
+
+		Convey("Getting the author should return the profile", func() {
+			rr := executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s", id), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var author data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&author)
+			So(author.Biography, ShouldEqual, "Greek philosopher")
+			So(*author.BirthDate, ShouldResemble, data.PartialDate{Year: -428, Circa: true})
+			So(*author.DeathDate, ShouldResemble, data.PartialDate{Year: -348})
+			So(author.Nationality, ShouldEqual, "Greek")
+			So(author.Occupations, ShouldResemble, []string{"philosopher"})
+			So(author.AlternateNames, ShouldResemble, []string{"Aristocles"})
+		})
+
+		Convey("Adding an author with a malformed date should return 400", func() {
+			body := `{"name":"Plato","birthDate":"yesterday"}`
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author", bytes.NewBufferString(body)), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+
+	Convey("When patching author", t, func() {
+		handler := createHandler()
+		picUrl := faker.ImageURL(100, 100)
+		author := data.NewAuthorBuilder().WithId(faker.UUID()).WithName(faker.Name()).WithPicUrl(picUrl).Build()
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+
+		Convey("When author exist only the sent fields should change", func() {
+			newName := faker.Name()
+			body := fmt.Sprintf(`{"name":%q,"nationality":"Brazilian"}`, newName)
+			req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/author/%s", author.ID), bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var patched data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&patched)
+			So(patched.Name, ShouldEqual, newName)
+			So(patched.Nationality, ShouldEqual, "Brazilian")
+			So(patched.PicURL, ShouldEqual, picUrl)
+		})
+
+		Convey("When a field is null it should be cleared", func() {
+			req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/author/%s", author.ID), bytes.NewBufferString(`{"picUrl":null}`))
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var patched data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&patched)
+			So(patched.PicURL, ShouldBeEmpty)
+			So(patched.Name, ShouldEqual, author.Name)
+		})
+
+		Convey("When the patch is invalid the response should be 400", func() {
+			for _, body := range []string{`not json`, `{"id":"other"}`, `{"name":null}`} {
+				req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/author/%s", author.ID), bytes.NewBufferString(body))
+				rr := executeRequest(req, handler)
+				So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			}
+		})
+
+		Convey("When the If-Match version is stale the response should be 412", func() {
+			req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/author/%s", author.ID), bytes.NewBufferString(`{"nationality":"Brazilian"}`))
+			req.Header.Set("If-Match", `"2"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusPreconditionFailed)
+		})
+
+		Convey("When author does not exist the response should be 404", func() {
+			req := httptest.NewRequest("PATCH", "/api/v1/author/456", bytes.NewBufferString(`{"name":"Name"}`))
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
 		})
 	})
 
@@ -116,10 +220,72 @@ func TestHttp(t *testing.T) {
 			So(rr.Code, ShouldEqual, http.StatusOK)
 		})
 
-		Convey("When author does not exist the response should be 500", func() {
+		Convey("When the If-Match version is stale the response should be 412", func() {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/delete/%s", author.ID), nil)
+			req.Header.Set("If-Match", `"2"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusPreconditionFailed)
+		})
+
+		Convey("When author does not exist the response should be 404", func() {
 			req := httptest.NewRequest("POST", "/api/v1/author/delete/456", nil)
 			rr := executeRequest(req, handler)
-			So(rr.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("When author exist it should be listed as deleted and restorable", func() {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/delete/%s", author.ID), nil)
+			req.Header.Set("X-Actor", "editor")
+			executeRequest(req, handler)
+			rr := executeRequest(httptest.NewRequest("GET", "/api/v1/author/deleted", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var deleted []data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&deleted)
+			So(len(deleted), ShouldEqual, 1)
+			So(deleted[0].DeletedBy, ShouldEqual, "editor")
+
+			req = httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/restore/%s", author.ID), nil)
+			req.Header.Set("If-Match", `"2"`)
+			rr = executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("ETag"), ShouldEqual, `"3"`)
+			rr = executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("When author is not deleted purging it should return 409", func() {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/purge/%s", author.ID), nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("When author is deleted and its retention is over it should be purged", func() {
+			handler := createHandlerWithClient(repository.NewFakeClientRepository(), repository.WithRetention(0))
+			executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+			executeRequest(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/delete/%s", author.ID), nil), handler)
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/purge", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var resp purgeResponse
+			_ = json.NewDecoder(rr.Body).Decode(&resp)
+			So(resp.Purged, ShouldResemble, []string{author.ID})
+		})
+
+		Convey("When quote service fails the author should be purged and its quotes left in the outbox", func() {
+			clientRepo := repository.NewFakeClientRepository()
+			clientRepo.SetDeleteAuthorQuotesReturn(false, fmt.Errorf("unavailable"))
+			handler := createHandlerWithClient(clientRepo, repository.WithRetention(0))
+			executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+			executeRequest(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/delete/%s", author.ID), nil), handler)
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/purge/%s", author.ID), nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+
+			rr = executeRequest(httptest.NewRequest("GET", "/api/v1/author/outbox", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var tasks []repository.OutboxTask
+			_ = json.NewDecoder(rr.Body).Decode(&tasks)
+			So(len(tasks), ShouldEqual, 1)
+			So(tasks[0].AuthorID, ShouldEqual, author.ID)
 		})
 
 	})
@@ -140,6 +306,60 @@ func TestHttp(t *testing.T) {
 			rr := executeRequest(req, handler)
 			So(rr.Code, ShouldEqual, http.StatusOK)
 		})
+
+		Convey("When listing by page the response should contain the page", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/all?pageSize=1", nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var page repository.AuthorPage
+			_ = json.NewDecoder(rr.Body).Decode(&page)
+			So(len(page.Authors), ShouldEqual, 1)
+			So(page.NextPageToken, ShouldNotBeEmpty)
+
+			req = httptest.NewRequest("GET", "/api/v1/author/all?pageSize=1&pageToken="+page.NextPageToken, nil)
+			rr = executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var next repository.AuthorPage
+			_ = json.NewDecoder(rr.Body).Decode(&next)
+			So(len(next.Authors), ShouldEqual, 1)
+			So(next.Authors[0].ID, ShouldNotEqual, page.Authors[0].ID)
+			So(next.NextPageToken, ShouldBeEmpty)
+		})
+
+		Convey("When the page size is invalid the response should be 400", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/all?pageSize=abc", nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+
+	Convey("When searching authors", t, func() {
+		handler := createHandler()
+		author := data.NewAuthorBuilder().WithId(faker.UUID()).WithName("Émile Zola").Build()
+		req := httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author))
+		executeRequest(req, handler)
+
+		Convey("When authors match the response should contain them", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/search?q=emile&limit=5", nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var authors []data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&authors)
+			So(len(authors), ShouldEqual, 1)
+			So(authors[0].ID, ShouldEqual, author.ID)
+		})
+
+		Convey("When the query is empty the response should be 400", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/search?q=", nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("When the limit is invalid the response should be 400", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/search?q=emile&limit=abc", nil)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
 	})
 
 	Convey("When updating author", t, func() {
@@ -161,11 +381,352 @@ func TestHttp(t *testing.T) {
 			So(parsedAuthor.ID, ShouldEqual, id)
 		})
 
-		Convey("When author does not exist the response should be 500", func() {
+		Convey("When the If-Match version is current the response should have the new ETag", func() {
+			author := data.NewAuthorBuilder().WithId(id).WithName(faker.Name()).Build()
+			req := httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author))
+			req.Header.Set("If-Match", `"1"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("ETag"), ShouldEqual, `"2"`)
+		})
+
+		Convey("When the If-Match version is stale the response should be 412", func() {
+			req := httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author))
+			req.Header.Set("If-Match", `"2"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusPreconditionFailed)
+		})
+
+		Convey("When the If-Match header is invalid the response should be 400", func() {
+			req := httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author))
+			req.Header.Set("If-Match", `"abc"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("When author does not exist the response should be 404", func() {
 			author := data.NewAuthorBuilder().WithId("426").WithName(faker.Name()).Build()
 			req := httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author))
 			rr := executeRequest(req, handler)
-			So(rr.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func TestHttpHealth(t *testing.T) {
+	Convey("When probing the liveness", t, func() {
+		rr := executeRequest(httptest.NewRequest("GET", "/healthz", nil), createHandler())
+		So(rr.Code, ShouldEqual, http.StatusOK)
+		var report HealthReport
+		_ = json.NewDecoder(rr.Body).Decode(&report)
+		So(report.Status, ShouldEqual, HealthOK)
+	})
+
+	Convey("When probing the readiness", t, func() {
+		Convey("With QuoteService unavailable the response should be 200 and degraded", func() {
+			clientRepo := repository.NewFakeClientRepository()
+			clientRepo.SetPingError(repository.ErrCircuitOpen)
+			rr := executeRequest(httptest.NewRequest("GET", "/readyz", nil), createHandlerWithClient(clientRepo))
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var report HealthReport
+			_ = json.NewDecoder(rr.Body).Decode(&report)
+			So(report.Status, ShouldEqual, HealthDegraded)
+			So(report.Dependencies[repository.DependencyQuoteService].Error, ShouldNotBeEmpty)
+		})
+
+		Convey("With the database unavailable the response should be 503", func() {
+			db := &switchableDatabase{Database: repository.NewInMemoryDatabase(), down: true}
+			hs := AuthorService{Service: New(repository.New(db, repository.NewFakeClientRepository()))}
+			rr := executeRequest(httptest.NewRequest("GET", "/readyz", nil), hs.MakeHandler())
+			So(rr.Code, ShouldEqual, http.StatusServiceUnavailable)
+			var report HealthReport
+			_ = json.NewDecoder(rr.Body).Decode(&report)
+			So(report.Status, ShouldEqual, HealthUnavailable)
+			So(report.Dependencies[repository.DependencyDatabase].Status, ShouldEqual, HealthUnavailable)
+		})
+	})
+}
+
+func TestHttpMetrics(t *testing.T) {
+	Convey("Given a service with metrics", t, func() {
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		hs := AuthorService{Service: New(repo), Metrics: metrics.New()}
+		handler := hs.MakeHandler()
+		executeRequest(httptest.NewRequest("GET", "/api/v1/author/missing", nil), handler)
+
+		Convey("The metrics should be exposed with the requests by route", func() {
+			rr := executeRequest(httptest.NewRequest("GET", "/metrics", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Body.String(), ShouldContainSubstring,
+				`mosha_author_http_requests_total{code="404",method="GET",route="/api/v1/author/{id}"} 1`)
+		})
+	})
+
+	Convey("Given a service without metrics", t, func() {
+		rr := executeRequest(httptest.NewRequest("GET", "/metrics", nil), createHandler())
+		So(rr.Code, ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestHttpMiddlewares(t *testing.T) {
+	Convey("Given a service with middlewares", t, func() {
+		var paths []string
+		recordPath := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				next.ServeHTTP(w, r)
+			})
+		}
+		repo := repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())
+		hs := AuthorService{Service: New(repo), Middlewares: []func(http.Handler) http.Handler{recordPath}}
+		handler := hs.MakeHandler()
+
+		Convey("They should run on every request", func() {
+			executeRequest(httptest.NewRequest("GET", "/api/v1/author/all", nil), handler)
+			executeRequest(httptest.NewRequest("GET", "/unknown", nil), handler)
+			So(paths, ShouldResemble, []string{"/api/v1/author/all", "/unknown"})
+		})
+	})
+}
+
+func TestHttpHistory(t *testing.T) {
+	Convey("Given an author that was renamed", t, func() {
+		handler := createHandler()
+		author := data.Author{ID: faker.UUID(), Name: "Ada"}
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+		author.Name = "Ada Lovelace"
+		req := httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author))
+		req.Header.Set("X-Actor", "editor")
+		executeRequest(req, handler)
+
+		Convey("Its history should list the changes from the oldest", func() {
+			rr := executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s/history", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var entries []repository.AuditEntry
+			_ = json.NewDecoder(rr.Body).Decode(&entries)
+			So(entries, ShouldHaveLength, 2)
+			So(entries[0].Action, ShouldEqual, repository.AuditCreated)
+			So(entries[1].Action, ShouldEqual, repository.AuditUpdated)
+			So(entries[1].Actor, ShouldEqual, "editor")
+			So(entries[1].Before.Name, ShouldEqual, "Ada")
+			So(entries[1].After.Name, ShouldEqual, "Ada Lovelace")
+			So(entries[1].RequestID, ShouldNotBeEmpty)
+		})
+
+		Convey("The history of an unknown author should return 404", func() {
+			rr := executeRequest(httptest.NewRequest("GET", "/api/v1/author/456/history", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func TestHttpRevisions(t *testing.T) {
+	Convey("Given an author that was renamed", t, func() {
+		handler := createHandler()
+		author := data.Author{ID: faker.UUID(), Name: "Ada"}
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+		author.Name = "Ada Lovelace"
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author)), handler)
+
+		Convey("Its revisions should be listed from the oldest", func() {
+			rr := executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s/revisions", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var revisions []repository.Revision
+			_ = json.NewDecoder(rr.Body).Decode(&revisions)
+			So(revisions, ShouldHaveLength, 2)
+			So(revisions[0].Version, ShouldEqual, 1)
+			So(revisions[0].Author.Name, ShouldEqual, "Ada")
+			So(revisions[1].Author.Name, ShouldEqual, "Ada Lovelace")
+		})
+
+		Convey("A revision should be returned by version", func() {
+			rr := executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s/revisions/1", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var revision repository.Revision
+			_ = json.NewDecoder(rr.Body).Decode(&revision)
+			So(revision.Author.Name, ShouldEqual, "Ada")
+
+			rr = executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s/revisions/5", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+			rr = executeRequest(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/author/%s/revisions/first", author.ID), nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("The revision at a time should be the last one made before it", func() {
+			url := fmt.Sprintf("/api/v1/author/%s/revisions/at?time=%s", author.ID, time.Now().Add(time.Hour).Format(time.RFC3339))
+			rr := executeRequest(httptest.NewRequest("GET", url, nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var revision repository.Revision
+			_ = json.NewDecoder(rr.Body).Decode(&revision)
+			So(revision.Version, ShouldEqual, 2)
+
+			url = fmt.Sprintf("/api/v1/author/%s/revisions/at?time=2000-01-01T00:00:00Z", author.ID)
+			rr = executeRequest(httptest.NewRequest("GET", url, nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusNotFound)
+			url = fmt.Sprintf("/api/v1/author/%s/revisions/at?time=yesterday", author.ID)
+			rr = executeRequest(httptest.NewRequest("GET", url, nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Reverting to a revision should return the author with its new version", func() {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/%s/revisions/1/revert", author.ID), nil)
+			req.Header.Set("If-Match", `"2"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("ETag"), ShouldEqual, `"3"`)
+			var reverted data.Author
+			_ = json.NewDecoder(rr.Body).Decode(&reverted)
+			So(reverted.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("Reverting an author changed in between should return 412", func() {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/author/%s/revisions/1/revert", author.ID), nil)
+			req.Header.Set("If-Match", `"1"`)
+			rr := executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusPreconditionFailed)
+		})
+	})
+}
+
+func TestHttpBulkWrite(t *testing.T) {
+	Convey("Given an author", t, func() {
+		handler := createHandler()
+		author := data.Author{ID: faker.UUID(), Name: "Ada"}
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+
+		Convey("A batch of valid operations should return 200 with the results", func() {
+			request := bulkRequest{Operations: []repository.BulkOperation{
+				{Kind: repository.BulkCreate, Author: data.Author{Name: "Grace"}},
+				{Kind: repository.BulkUpdate, Author: data.Author{ID: author.ID, Name: "Ada Lovelace"}, ExpectedVersion: 1},
+			}}
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", jsonReaderFactory(request)), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var resp bulkResponse
+			_ = json.NewDecoder(rr.Body).Decode(&resp)
+			So(resp.Applied, ShouldEqual, 2)
+			So(resp.Failed, ShouldEqual, 0)
+			So(resp.Results[0].Status, ShouldEqual, http.StatusCreated)
+			So(resp.Results[0].ID, ShouldNotBeEmpty)
+			So(resp.Results[1].Status, ShouldEqual, http.StatusOK)
+			So(resp.Results[1].Author.Version, ShouldEqual, 2)
+		})
+
+		Convey("A batch with failed operations should return 207 with their statuses", func() {
+			request := bulkRequest{Mode: repository.BulkBestEffort, Operations: []repository.BulkOperation{
+				{Kind: repository.BulkCreate, Author: data.Author{Name: ""}},
+				{Kind: repository.BulkDelete, ID: author.ID, ExpectedVersion: 3},
+				{Kind: repository.BulkDelete, ID: faker.UUID()},
+				{Kind: repository.BulkCreate, Author: data.Author{Name: "Grace"}},
+			}}
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", jsonReaderFactory(request)), handler)
+			So(rr.Code, ShouldEqual, http.StatusMultiStatus)
+			var resp bulkResponse
+			_ = json.NewDecoder(rr.Body).Decode(&resp)
+			So(resp.Applied, ShouldEqual, 1)
+			So(resp.Failed, ShouldEqual, 3)
+			So(resp.Results[0].Status, ShouldEqual, http.StatusBadRequest)
+			So(resp.Results[0].Fields, ShouldNotBeEmpty)
+			So(resp.Results[1].Status, ShouldEqual, http.StatusPreconditionFailed)
+			So(resp.Results[2].Status, ShouldEqual, http.StatusNotFound)
+			So(resp.Results[3].Status, ShouldEqual, http.StatusCreated)
+		})
+
+		Convey("A failed atomic batch should report the other operations as aborted", func() {
+			request := bulkRequest{Operations: []repository.BulkOperation{
+				{Kind: repository.BulkCreate, Author: data.Author{Name: "Grace"}},
+				{Kind: repository.BulkCreate, Author: author},
+			}}
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", jsonReaderFactory(request)), handler)
+			So(rr.Code, ShouldEqual, http.StatusMultiStatus)
+			var resp bulkResponse
+			_ = json.NewDecoder(rr.Body).Decode(&resp)
+			So(resp.Applied, ShouldEqual, 0)
+			So(resp.Results[0].Status, ShouldEqual, http.StatusConflict)
+			So(resp.Results[0].Author, ShouldBeNil)
+			So(resp.Results[1].Status, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("An invalid batch should return 400", func() {
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", jsonReaderFactory(bulkRequest{})), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			rr = executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", jsonReaderFactory(bulkRequest{Mode: "eventually"})), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			rr = executeRequest(httptest.NewRequest("POST", "/api/v1/author/batch", bytes.NewBufferString("{")), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestHttpImportExport(t *testing.T) {
+	Convey("Given an author", t, func() {
+		handler := createHandler()
+		author := data.Author{ID: faker.UUID(), Name: "Ada"}
+		executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+
+		Convey("The authors should be exported in the requested format", func() {
+			rr := executeRequest(httptest.NewRequest("GET", "/api/v1/author/export", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("Content-Type"), ShouldEqual, "application/x-ndjson")
+			var exported data.Author
+			So(json.NewDecoder(rr.Body).Decode(&exported), ShouldBeNil)
+			So(exported.ID, ShouldEqual, author.ID)
+
+			rr = executeRequest(httptest.NewRequest("GET", "/api/v1/author/export?format=csv", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			So(rr.Header().Get("Content-Type"), ShouldEqual, "text/csv")
+			So(rr.Header().Get("Content-Disposition"), ShouldEqual, `attachment; filename="authors.csv"`)
+			So(rr.Body.String(), ShouldContainSubstring, author.ID+",Ada,")
+
+			rr = executeRequest(httptest.NewRequest("GET", "/api/v1/author/export?format=xml", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Importing valid rows should return 200 with the report", func() {
+			body := bytes.NewBufferString("id,name\n" + author.ID + ",Ada Lovelace\n,Grace\n")
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/import?format=csv", body), handler)
+			So(rr.Code, ShouldEqual, http.StatusOK)
+			var report repository.ImportReport
+			_ = json.NewDecoder(rr.Body).Decode(&report)
+			So(report.Created, ShouldEqual, 1)
+			So(report.Updated, ShouldEqual, 1)
+			So(report.Errors, ShouldBeEmpty)
+		})
+
+		Convey("Importing failed rows should return 207 with their errors", func() {
+			body := bytes.NewBufferString(`{"id": "` + author.ID + `", "name": "Ada Lovelace"}` + "\n")
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/import?mode=insert&dryRun=true", body), handler)
+			So(rr.Code, ShouldEqual, http.StatusMultiStatus)
+			var report repository.ImportReport
+			_ = json.NewDecoder(rr.Body).Decode(&report)
+			So(report.DryRun, ShouldBeTrue)
+			So(report.Failed, ShouldEqual, 1)
+			So(report.Errors[0].Row, ShouldEqual, 1)
+			So(report.Errors[0].ID, ShouldEqual, author.ID)
+		})
+
+		Convey("An invalid import request should return 400", func() {
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/import?mode=merge", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			rr = executeRequest(httptest.NewRequest("POST", "/api/v1/author/import?dryRun=maybe", nil), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+			rr = executeRequest(httptest.NewRequest("POST", "/api/v1/author/import?format=csv", bytes.NewBufferString("name,quotes\n")), handler)
+			So(rr.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Importing a body larger than the limit should return 413", func() {
+			hs := AuthorService{Service: New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository())), MaxImportBytes: 64}
+			handler := hs.MakeHandler()
+			rows := strings.Repeat(`{"name": "Ada Lovelace"}`+"\n", 10)
+
+			rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author/import", strings.NewReader(rows)), handler)
+			So(rr.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+			rr = executeRequest(httptest.NewRequest("GET", "/api/v1/author/all", nil), handler)
+			So(rr.Body.String(), ShouldNotContainSubstring, "Ada Lovelace")
+
+			req := httptest.NewRequest("POST", "/api/v1/author/import?format=csv", strings.NewReader("name\n"+strings.Repeat("Ada Lovelace\n", 10)))
+			req.ContentLength = -1
+			rr = executeRequest(req, handler)
+			So(rr.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
 		})
 	})
 }
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the HTTP header carrying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// requestID returns the request ID received from the caller, or a new one
+// when there is none or it is not valid.
+func requestID(received string) string {
+	if repository.ValidRequestID(received) {
+		return received
+	}
+	return repository.NewRequestID()
+}
+
+// requestIDMiddleware puts the request ID of the X-Request-ID header in the
+// context, generating one when needed, and sends it back in the response.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(repository.WithRequestID(r.Context(), id)))
+	})
+}
+
+// accessLogMiddleware logs each request once served, with its request ID.
+// Requests failing with a server error are logged as errors.
+func accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		route := ""
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+		keyvals := []interface{}{
+			"method", r.Method,
+			"route", route,
+			"path", r.URL.Path,
+			"status", code,
+			"bytes", ww.BytesWritten(),
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+		}
+		logger := repository.Logger(r.Context())
+		if code >= http.StatusInternalServerError {
+			logger.Error("http request", keyvals...)
+			return
+		}
+		logger.Info("http request", keyvals...)
+	})
+}
+
+// grpcRequestContext puts the request ID of the x-request-id metadata in the
+// context, generating one when needed, and sends it back in the header.
+func grpcRequestContext(ctx context.Context) context.Context {
+	var received string
+	if ids := metadata.ValueFromIncomingContext(ctx, repository.RequestIDMetadata); len(ids) > 0 {
+		received = ids[0]
+	}
+	id := requestID(received)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(repository.RequestIDMetadata, id))
+	return repository.WithRequestID(ctx, id)
+}
+
+// logGrpcCall logs a call once done, with its request ID. Calls failing with
+// a server error are logged as errors.
+func logGrpcCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	keyvals := []interface{}{
+		"method", method,
+		"code", code.String(),
+		"duration", time.Since(start),
+	}
+	logger := repository.Logger(ctx)
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		logger.Error("grpc request", append(keyvals, "err", err)...)
+	default:
+		logger.Info("grpc request", keyvals...)
+	}
+}
+
+// loggingUnaryInterceptor handles the request ID of the unary calls and logs them.
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx = grpcRequestContext(ctx)
+	resp, err := handler(ctx, req)
+	logGrpcCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// requestStream is a server stream whose context carries the request ID.
+type requestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestStream) Context() context.Context {
+	return s.ctx
+}
+
+// loggingStreamInterceptor handles the request ID of the streaming calls and logs them.
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx := grpcRequestContext(ss.Context())
+	err := handler(srv, &requestStream{ServerStream: ss, ctx: ctx})
+	logGrpcCall(ctx, info.FullMethod, start, err)
+	return err
+}
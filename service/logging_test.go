@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// captureLogs makes the default logger write JSON to the returned buffer until
+// the returned function is called.
+func captureLogs() (*bytes.Buffer, func()) {
+	previous := log.Default()
+	var buf bytes.Buffer
+	logger := log.New(&buf)
+	logger.SetFormatter(log.JSONFormatter)
+	log.SetDefault(logger)
+	return &buf, func() { log.SetDefault(previous) }
+}
+
+func TestHttpLogging(t *testing.T) {
+	Convey("Given the HTTP handler", t, func() {
+		logs, restore := captureLogs()
+		defer restore()
+		handler := createHandler()
+
+		Convey("A request ID received from the caller should be used", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/missing", nil)
+			req.Header.Set(RequestIDHeader, "caller-request")
+			rr := executeRequest(req, handler)
+
+			So(rr.Header().Get(RequestIDHeader), ShouldEqual, "caller-request")
+			line := logs.String()
+			So(line, ShouldContainSubstring, `"request_id":"caller-request"`)
+			So(line, ShouldContainSubstring, `"route":"/api/v1/author/{id}"`)
+			So(line, ShouldContainSubstring, `"status":404`)
+		})
+
+		Convey("A request ID should be generated when none is valid", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/all", nil)
+			req.Header.Set(RequestIDHeader, "not valid")
+			rr := executeRequest(req, handler)
+
+			generated := rr.Header().Get(RequestIDHeader)
+			So(repository.ValidRequestID(generated), ShouldBeTrue)
+			So(generated, ShouldNotEqual, "not valid")
+			So(logs.String(), ShouldContainSubstring, `"request_id":"`+generated+`"`)
+		})
+	})
+
+	Convey("Given a handler failing with a server error", t, func() {
+		logs, restore := captureLogs()
+		defer restore()
+		handler := requestIDMiddleware(accessLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			repository.Logger(r.Context()).Error("database unreachable")
+			w.WriteHeader(http.StatusInternalServerError)
+		})))
+		rr := executeRequest(httptest.NewRequest("GET", "/", nil), handler)
+		requestID := `"request_id":"` + rr.Header().Get(RequestIDHeader) + `"`
+
+		Convey("The error logs should carry the request ID", func() {
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			So(len(lines), ShouldEqual, 2)
+			So(lines[0], ShouldContainSubstring, `"msg":"database unreachable"`)
+			So(lines[0], ShouldContainSubstring, requestID)
+			So(lines[1], ShouldContainSubstring, `"lvl":"error"`)
+			So(lines[1], ShouldContainSubstring, `"status":500`)
+			So(lines[1], ShouldContainSubstring, requestID)
+		})
+	})
+}
+
+// headerStream records the header sent by a streaming call.
+type headerStream struct {
+	ctx    context.Context
+	header metadata.MD
+	grpc.ServerStream
+}
+
+func (s *headerStream) Context() context.Context       { return s.ctx }
+func (s *headerStream) SetHeader(md metadata.MD) error { s.header = md; return nil }
+
+func TestGrpcLogging(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorservice.AuthorService/GetAuthor"}
+
+	Convey("Given the unary logging interceptor", t, func() {
+		logs, restore := captureLogs()
+		defer restore()
+
+		Convey("The request ID of the metadata should be in the context and the logs", func() {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(repository.RequestIDMetadata, "caller-request"))
+			var received string
+			_, err := loggingUnaryInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				received = repository.RequestIDFromContext(ctx)
+				return nil, status.Error(codes.NotFound, "not found")
+			})
+
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+			So(received, ShouldEqual, "caller-request")
+			So(logs.String(), ShouldContainSubstring, `"request_id":"caller-request"`)
+			So(logs.String(), ShouldContainSubstring, `"code":"NotFound"`)
+			So(logs.String(), ShouldContainSubstring, `"lvl":"info"`)
+		})
+
+		Convey("A request ID should be generated when the metadata has none", func() {
+			var received string
+			_, _ = loggingUnaryInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				received = repository.RequestIDFromContext(ctx)
+				return nil, status.Error(codes.Internal, "failed")
+			})
+
+			So(repository.ValidRequestID(received), ShouldBeTrue)
+			So(logs.String(), ShouldContainSubstring, `"lvl":"error"`)
+		})
+	})
+
+	Convey("Given the stream logging interceptor", t, func() {
+		_, restore := captureLogs()
+		defer restore()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(repository.RequestIDMetadata, "caller-request"))
+		stream := &headerStream{ctx: ctx}
+		var received string
+		err := loggingStreamInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				received = repository.RequestIDFromContext(ss.Context())
+				return nil
+			})
+
+		So(err, ShouldBeNil)
+		So(received, ShouldEqual, "caller-request")
+	})
+}
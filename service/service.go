@@ -1,61 +1,403 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/events"
 	"github.com/wcodesoft/mosha-author-service/repository"
+	"io"
+	"time"
 )
 
 // Service represents the service interface.
 type Service interface {
 
 	// CreateAuthor registers a new Author in the database.
-	CreateAuthor(author data.Author) (string, error)
+	CreateAuthor(ctx context.Context, author data.Author) (string, error)
 
 	// ListAll returns all authors in the database.
-	ListAll() []data.Author
+	ListAll(ctx context.Context) []data.Author
+
+	// ListPage returns a page of authors sorted by name.
+	ListPage(ctx context.Context, page repository.PageRequest) (repository.AuthorPage, error)
 
 	// GetAuthor returns an author by id
-	GetAuthor(id string) (data.Author, error)
+	GetAuthor(ctx context.Context, id string) (data.Author, error)
+
+	// DeleteAuthor deletes an author by id, if it is at the expected version.
+	// An expected version of zero deletes the author at any version. The author
+	// can be restored until it is purged.
+	DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error
+
+	// RestoreAuthor restores a deleted author, if it is at the expected version.
+	RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error)
+
+	// ListDeleted returns the deleted authors that can be restored.
+	ListDeleted(ctx context.Context) ([]data.Author, error)
+
+	// PurgeAuthor permanently deletes a deleted author once its retention period
+	// is over. Its quotes are deleted afterwards through the outbox.
+	PurgeAuthor(ctx context.Context, id string) error
+
+	// PurgeExpired permanently deletes the deleted authors whose retention period
+	// is over and returns their IDs.
+	PurgeExpired(ctx context.Context) ([]string, error)
+
+	// ListOutbox returns the outbox tasks that are still pending.
+	ListOutbox(ctx context.Context) ([]repository.OutboxTask, error)
+
+	// UpdateAuthor updates an author, if it is at the expected version. An
+	// expected version of zero updates the author at any version.
+	UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error)
+
+	// PatchAuthor changes only the patched fields of an author, if it is at the
+	// expected version. An expected version of zero patches the author at any version.
+	PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error)
+
+	// SearchAuthors returns the authors whose name matches the query.
+	SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error)
+
+	// AuthorHistory returns the changes of an author, the oldest first.
+	AuthorHistory(ctx context.Context, id string) ([]repository.AuditEntry, error)
+
+	// ListRevisions returns the revisions of an author, the oldest first.
+	ListRevisions(ctx context.Context, id string) ([]repository.Revision, error)
 
-	// DeleteAuthor deletes an author by id.
-	DeleteAuthor(id string) error
+	// GetRevision returns the revision of an author at the version.
+	GetRevision(ctx context.Context, id string, version int64) (repository.Revision, error)
 
-	// UpdateAuthor updates an author.
-	UpdateAuthor(author data.Author) (data.Author, error)
+	// GetRevisionAt returns the revision of an author as it was at the time.
+	GetRevisionAt(ctx context.Context, id string, at time.Time) (repository.Revision, error)
+
+	// RevertAuthor replaces an author with its revision at the version, if it
+	// is at the expected version. The revert creates a new revision.
+	RevertAuthor(ctx context.Context, id string, version int64, expectedVersion int64) (data.Author, error)
+
+	// BulkWrite applies a batch of creations, updates and deletions and returns
+	// the result of each of them. In atomic mode, either all the operations are
+	// applied or none is.
+	BulkWrite(ctx context.Context, ops []repository.BulkOperation, mode repository.BulkMode) ([]repository.BulkResult, error)
+
+	// ExportAuthors writes the authors in the format and returns their number.
+	ExportAuthors(ctx context.Context, w io.Writer, format repository.Format) (int, error)
+
+	// ImportAuthors adds the authors read in the format and reports the rows
+	// that could not be imported.
+	ImportAuthors(ctx context.Context, r io.Reader, format repository.Format, opts repository.ImportOptions) (repository.ImportReport, error)
+
+	// WatchAuthors subscribes to the events of the authors changed from now on.
+	// The subscription must be closed once done.
+	WatchAuthors() *events.Subscription
+
+	// Readiness checks the dependencies of the service and reports whether it
+	// can serve requests.
+	Readiness(ctx context.Context) HealthReport
 }
 
 type service struct {
-	repo repository.Repository
+	repo       repository.Repository
+	broker     *events.Broker
+	publishers []events.Publisher
 }
 
-// New creates a new service.
-func New(repo repository.Repository) Service {
-	return &service{
-		repo: repo,
+// Option configures a service.
+type Option func(*service)
+
+// WithPublisher also publishes the events of the authors to the publisher,
+// such as a message broker.
+func WithPublisher(publisher events.Publisher) Option {
+	return func(s *service) {
+		s.publishers = append(s.publishers, publisher)
+	}
+}
+
+// New creates a new service. The events of the authors are delivered to the
+// watchers of the service and to the publishers that are set.
+func New(repo repository.Repository, opts ...Option) Service {
+	s := &service{
+		repo:   repo,
+		broker: events.NewBroker(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// CreateAuthor registers a new Author in the database.
-func (s *service) CreateAuthor(author data.Author) (string, error) {
-	return s.repo.AddAuthor(author)
+// CreateAuthor registers a new Author in the database. An ID is generated when
+// the author has none.
+func (s *service) CreateAuthor(ctx context.Context, author data.Author) (string, error) {
+	if author.ID == "" {
+		author.ID = uuid.New().String()
+	}
+	if err := validateAuthor(author); err != nil {
+		return "", err
+	}
+	id, err := s.repo.AddAuthor(ctx, author)
+	if err != nil {
+		return "", err
+	}
+	author.Version = repository.InitialVersion
+	s.publish(ctx, events.Created, id, &author)
+	return id, nil
 }
 
 // GetAuthor returns an author by id.
-func (s *service) GetAuthor(id string) (data.Author, error) {
-	return s.repo.GetAuthor(id)
+func (s *service) GetAuthor(ctx context.Context, id string) (data.Author, error) {
+	return s.repo.GetAuthor(ctx, id)
 }
 
 // ListAll returns all authors in the database.
-func (s *service) ListAll() []data.Author {
-	return s.repo.ListAll()
+func (s *service) ListAll(ctx context.Context) []data.Author {
+	return s.repo.ListAll(ctx)
+}
+
+// ListPage returns a page of authors sorted by name.
+func (s *service) ListPage(ctx context.Context, page repository.PageRequest) (repository.AuthorPage, error) {
+	return s.repo.ListPage(ctx, page)
+}
+
+// DeleteAuthor deletes an author by id, if it is at the expected version.
+func (s *service) DeleteAuthor(ctx context.Context, id string, expectedVersion int64) error {
+	if err := s.repo.DeleteAuthor(ctx, id, expectedVersion); err != nil {
+		return err
+	}
+	s.publish(ctx, events.Deleted, id, nil)
+	return nil
+}
+
+// RestoreAuthor restores a deleted author, if it is at the expected version.
+func (s *service) RestoreAuthor(ctx context.Context, id string, expectedVersion int64) (data.Author, error) {
+	author, err := s.repo.RestoreAuthor(ctx, id, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.publish(ctx, events.Updated, author.ID, &author)
+	return author, nil
 }
 
-// DeleteAuthor deletes an author by id.
-func (s *service) DeleteAuthor(id string) error {
-	return s.repo.DeleteAuthor(id)
+// ListDeleted returns the deleted authors that can be restored.
+func (s *service) ListDeleted(ctx context.Context) ([]data.Author, error) {
+	return s.repo.ListDeleted(ctx)
 }
 
-// UpdateAuthor updates an author.
-func (s *service) UpdateAuthor(author data.Author) (data.Author, error) {
-	return s.repo.UpdateAuthor(author)
+// PurgeAuthor permanently deletes a deleted author.
+func (s *service) PurgeAuthor(ctx context.Context, id string) error {
+	return s.repo.PurgeAuthor(ctx, id)
+}
+
+// PurgeExpired permanently deletes the deleted authors whose retention period is over.
+func (s *service) PurgeExpired(ctx context.Context) ([]string, error) {
+	return s.repo.PurgeExpired(ctx)
+}
+
+// ListOutbox returns the outbox tasks that are still pending.
+func (s *service) ListOutbox(ctx context.Context) ([]repository.OutboxTask, error) {
+	return s.repo.ListOutbox(ctx)
+}
+
+// UpdateAuthor updates an author, if it is at the expected version.
+func (s *service) UpdateAuthor(ctx context.Context, author data.Author, expectedVersion int64) (data.Author, error) {
+	if err := validateAuthor(author); err != nil {
+		return data.Author{}, err
+	}
+	author, err := s.repo.UpdateAuthor(ctx, author, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.publish(ctx, events.Updated, author.ID, &author)
+	return author, nil
+}
+
+// PatchAuthor changes only the patched fields of an author, checking that the
+// patched author is valid. The patch is written only if the author did not
+// change since it was validated.
+func (s *service) PatchAuthor(ctx context.Context, id string, patch data.AuthorPatch, expectedVersion int64) (data.Author, error) {
+	if err := patch.Validate(); err != nil {
+		return data.Author{}, repository.InvalidArgument(err)
+	}
+	current, err := s.repo.GetAuthor(ctx, id)
+	if err != nil {
+		return data.Author{}, err
+	}
+	if err := repository.CheckVersion(id, current.Version, expectedVersion); err != nil {
+		return data.Author{}, err
+	}
+	if err := validateAuthor(patch.Apply(current)); err != nil {
+		return data.Author{}, err
+	}
+	author, err := s.repo.PatchAuthor(ctx, id, patch, current.Version)
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.publish(ctx, events.Updated, author.ID, &author)
+	return author, nil
+}
+
+// SearchAuthors returns the authors whose name matches the query.
+func (s *service) SearchAuthors(ctx context.Context, query string, limit int) ([]data.Author, error) {
+	return s.repo.SearchAuthors(ctx, query, limit)
+}
+
+// AuthorHistory returns the changes of an author, the oldest first.
+func (s *service) AuthorHistory(ctx context.Context, id string) ([]repository.AuditEntry, error) {
+	return s.repo.AuthorHistory(ctx, id)
+}
+
+// ListRevisions returns the revisions of an author, the oldest first.
+func (s *service) ListRevisions(ctx context.Context, id string) ([]repository.Revision, error) {
+	return s.repo.ListRevisions(ctx, id)
+}
+
+// GetRevision returns the revision of an author at the version.
+func (s *service) GetRevision(ctx context.Context, id string, version int64) (repository.Revision, error) {
+	return s.repo.GetRevision(ctx, id, version)
+}
+
+// GetRevisionAt returns the revision of an author as it was at the time.
+func (s *service) GetRevisionAt(ctx context.Context, id string, at time.Time) (repository.Revision, error) {
+	return s.repo.GetRevisionAt(ctx, id, at)
+}
+
+// RevertAuthor replaces an author with its revision at the version, if it is
+// at the expected version.
+func (s *service) RevertAuthor(ctx context.Context, id string, version int64, expectedVersion int64) (data.Author, error) {
+	author, err := s.repo.RevertAuthor(ctx, id, version, expectedVersion)
+	if err != nil {
+		return data.Author{}, err
+	}
+	s.publish(ctx, events.Updated, author.ID, &author)
+	return author, nil
+}
+
+// MaxBulkOperations is the largest number of operations of a batch.
+const MaxBulkOperations = 500
+
+// bulkEventTypes are the event types of the bulk operations.
+var bulkEventTypes = map[string]string{
+	repository.BulkCreate: events.Created,
+	repository.BulkUpdate: events.Updated,
+	repository.BulkDelete: events.Deleted,
+}
+
+// BulkWrite checks the operations of a batch and applies the valid ones. IDs
+// are generated for the created authors that have none. In atomic mode, an
+// invalid operation fails the whole batch before anything is stored.
+func (s *service) BulkWrite(ctx context.Context, ops []repository.BulkOperation, mode repository.BulkMode) ([]repository.BulkResult, error) {
+	if len(ops) == 0 || len(ops) > MaxBulkOperations {
+		return nil, fmt.Errorf("a batch must have from 1 to %d operations: %w", MaxBulkOperations, repository.ErrInvalidArgument)
+	}
+	results := make([]repository.BulkResult, len(ops))
+	var valid []repository.BulkOperation
+	var validIndexes []int
+	for index, op := range ops {
+		if op.Kind == repository.BulkCreate && op.Author.ID == "" {
+			op.Author.ID = uuid.New().String()
+		}
+		if err := validateBulkOperation(op); err != nil {
+			results[index] = repository.BulkResult{ID: op.AuthorID(), Err: err}
+			continue
+		}
+		valid = append(valid, op)
+		validIndexes = append(validIndexes, index)
+	}
+	if len(valid) < len(ops) && mode == repository.BulkAtomic {
+		for _, index := range validIndexes {
+			id := ops[index].AuthorID()
+			results[index] = repository.BulkResult{ID: id, Err: fmt.Errorf(
+				"author %q was not changed since another operation of the batch is invalid: %w", id, repository.ErrAborted)}
+		}
+		return results, nil
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+	applied, err := s.repo.BulkWrite(ctx, valid, mode)
+	if err != nil {
+		return nil, err
+	}
+	for position, result := range applied {
+		index := validIndexes[position]
+		results[index] = result
+		if result.Err != nil {
+			continue
+		}
+		author := result.Author
+		if ops[index].Kind == repository.BulkDelete {
+			author = nil
+		}
+		s.publish(ctx, bulkEventTypes[ops[index].Kind], result.ID, author)
+	}
+	return results, nil
+}
+
+// validateBulkOperation returns an invalid argument error when the operation
+// is unknown, has no author ID or would store an invalid author.
+func validateBulkOperation(op repository.BulkOperation) error {
+	switch op.Kind {
+	case repository.BulkCreate, repository.BulkUpdate:
+		return validateAuthor(op.Author)
+	case repository.BulkDelete:
+		if op.ID == "" {
+			return fmt.Errorf("deleted author has no ID: %w", repository.ErrInvalidArgument)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown bulk operation %q: %w", op.Kind, repository.ErrInvalidArgument)
+	}
+}
+
+// ExportAuthors writes the authors in the format and returns their number.
+func (s *service) ExportAuthors(ctx context.Context, w io.Writer, format repository.Format) (int, error) {
+	return s.repo.ExportAuthors(ctx, w, format)
+}
+
+// importEventTypes are the event types of the imported authors, by audit action.
+var importEventTypes = map[string]string{
+	repository.AuditCreated: events.Created,
+	repository.AuditUpdated: events.Updated,
+}
+
+// ImportAuthors adds the authors read in the format, publishing an event for
+// each imported author.
+func (s *service) ImportAuthors(ctx context.Context, r io.Reader, format repository.Format, opts repository.ImportOptions) (repository.ImportReport, error) {
+	onImport := opts.OnImport
+	opts.OnImport = func(action string, author data.Author) {
+		s.publish(ctx, importEventTypes[action], author.ID, &author)
+		if onImport != nil {
+			onImport(action, author)
+		}
+	}
+	return s.repo.ImportAuthors(ctx, r, format, opts)
+}
+
+// WatchAuthors subscribes to the events of the authors changed from now on.
+func (s *service) WatchAuthors() *events.Subscription {
+	return s.broker.Subscribe()
+}
+
+// publish delivers the event of a change made by the actor and request of the
+// context to the watchers and the publishers. The change is already stored, so
+// a failure to publish it is logged instead of failing the change.
+func (s *service) publish(ctx context.Context, eventType string, id string, author *data.Author) {
+	event := events.New(eventType, id, author, time.Now().UTC())
+	event.Actor = repository.ActorFromContext(ctx)
+	event.RequestID = repository.RequestIDFromContext(ctx)
+	_ = s.broker.Publish(ctx, event)
+	for _, publisher := range s.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			repository.Logger(ctx).Error("unable to publish the author event", "author_id", id, "type", eventType, "err", err)
+		}
+	}
+}
+
+// validateAuthor returns an invalid argument error wrapping the *data.ValidationError
+// of an invalid author.
+func validateAuthor(author data.Author) error {
+	if err := author.Validate(); err != nil {
+		return repository.InvalidArgument(err)
+	}
+	return nil
 }
@@ -1,16 +1,26 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	faker "github.com/brianvoe/gofakeit/v6"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/events"
+	cpb "github.com/wcodesoft/mosha-author-service/protos/authorcatalog"
 	"github.com/wcodesoft/mosha-author-service/repository"
 )
 
 func TestService(t *testing.T) {
+	ctx := context.Background()
 
 	name := faker.Name()
 	picUrl := faker.ImageURL(100, 100)
@@ -30,46 +40,148 @@ func TestService(t *testing.T) {
 		})
 
 		Convey("When adding an author", func() {
-			authorId, _ := service.CreateAuthor(author)
+			authorId, _ := service.CreateAuthor(ctx, author)
 			Convey("The list of authors should contain the new author", func() {
-				So(len(service.ListAll()), ShouldEqual, 1)
+				So(len(service.ListAll(ctx)), ShouldEqual, 1)
 			})
 
 			Convey("Getting the author by ID should return the correct author", func() {
-				author, _ := service.GetAuthor(authorId)
+				author, _ := service.GetAuthor(ctx, authorId)
 				So(author.ID, ShouldEqual, authorId)
 				So(author.Name, ShouldEqual, name)
 				So(author.PicURL, ShouldEqual, picUrl)
 			})
 		})
 
+		Convey("When listing authors by page", func() {
+			authorId, _ := service.CreateAuthor(ctx, author)
+			page, err := service.ListPage(ctx, repository.PageRequest{})
+			Convey("The page should contain the author", func() {
+				So(err, ShouldBeNil)
+				So(len(page.Authors), ShouldEqual, 1)
+				So(page.Authors[0].ID, ShouldEqual, authorId)
+				So(page.NextPageToken, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When adding an author without ID", func() {
+			authorId, err := service.CreateAuthor(ctx, data.Author{Name: name})
+			Convey("An ID should be generated", func() {
+				So(err, ShouldBeNil)
+				So(authorId, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When adding an invalid author", func() {
+			_, err := service.CreateAuthor(ctx, data.Author{PicURL: "invalid"})
+			Convey("A validation error should be returned", func() {
+				So(err, ShouldWrap, repository.ErrInvalidArgument)
+				var verr *data.ValidationError
+				So(errors.As(err, &verr), ShouldBeTrue)
+				So(len(verr.Fields), ShouldEqual, 2)
+				So(len(service.ListAll(ctx)), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When updating an author with invalid fields", func() {
+			authorId, _ := service.CreateAuthor(ctx, author)
+			_, err := service.UpdateAuthor(ctx, data.Author{ID: authorId, Name: ""}, 0)
+			Convey("A validation error should be returned", func() {
+				So(err, ShouldWrap, repository.ErrInvalidArgument)
+				stored, _ := service.GetAuthor(ctx, authorId)
+				So(stored.Name, ShouldEqual, name)
+			})
+		})
+
+		Convey("When patching an author", func() {
+			authorId, _ := service.CreateAuthor(ctx, author)
+
+			Convey("Only the patched fields should change", func() {
+				patched, err := service.PatchAuthor(ctx, authorId, data.AuthorPatch{
+					Author: data.Author{Nationality: "French"},
+					Fields: []string{data.FieldNationality},
+				}, 0)
+				So(err, ShouldBeNil)
+				So(patched.Nationality, ShouldEqual, "French")
+				So(patched.Name, ShouldEqual, name)
+				So(patched.PicURL, ShouldEqual, picUrl)
+			})
+
+			Convey("A patch making the author invalid should fail", func() {
+				_, err := service.PatchAuthor(ctx, authorId, data.AuthorPatch{Fields: []string{data.FieldName}}, 0)
+				So(err, ShouldWrap, repository.ErrInvalidArgument)
+				stored, _ := service.GetAuthor(ctx, authorId)
+				So(stored.Name, ShouldEqual, name)
+			})
+
+			Convey("A patch of unknown fields should fail", func() {
+				_, err := service.PatchAuthor(ctx, authorId, data.AuthorPatch{Fields: []string{"id"}}, 0)
+				So(err, ShouldWrap, repository.ErrInvalidArgument)
+			})
+
+			Convey("A patch of an author that does not exist should fail", func() {
+				_, err := service.PatchAuthor(ctx, "unknown", data.AuthorPatch{
+					Author: data.Author{Name: name},
+					Fields: []string{data.FieldName},
+				}, 0)
+				So(err, ShouldWrap, repository.ErrNotFound)
+			})
+
+			Convey("A patch of an author at another version should fail", func() {
+				_, err := service.PatchAuthor(ctx, authorId, data.AuthorPatch{
+					Author: data.Author{Nationality: "French"},
+					Fields: []string{data.FieldNationality},
+				}, 2)
+				So(err, ShouldWrap, repository.ErrConflict)
+				So(err.Error(), ShouldEqual, repository.CheckVersion(authorId, repository.InitialVersion, 2).Error())
+			})
+		})
+
+		Convey("When searching authors", func() {
+			authorId, _ := service.CreateAuthor(ctx, author)
+			authors, err := service.SearchAuthors(ctx, name, 5)
+			Convey("The results should contain the author", func() {
+				So(err, ShouldBeNil)
+				So(len(authors), ShouldEqual, 1)
+				So(authors[0].ID, ShouldEqual, authorId)
+			})
+		})
+
 		Convey("When deleting an author", func() {
-			authorId, _ := service.CreateAuthor(author)
-			err := service.DeleteAuthor(authorId)
+			authorId, _ := service.CreateAuthor(ctx, author)
+			err := service.DeleteAuthor(ctx, authorId, 0)
 			Convey("The list of authors should be empty", func() {
-				So(len(service.ListAll()), ShouldEqual, 0)
+				So(len(service.ListAll(ctx)), ShouldEqual, 0)
 			})
 
 			Convey("Getting the author by ID should return an error", func() {
-				_, getErr := service.GetAuthor(authorId)
+				_, getErr := service.GetAuthor(ctx, authorId)
 				So(getErr, ShouldNotBeNil)
 			})
 
 			Convey("Deleting the author should not return an error", func() {
 				So(err, ShouldBeNil)
 			})
+
+			Convey("Restoring the author should make it visible again", func() {
+				restored, restoreErr := service.RestoreAuthor(ctx, authorId, 0)
+				So(restoreErr, ShouldBeNil)
+				So(restored.IsDeleted(), ShouldBeFalse)
+				So(len(service.ListAll(ctx)), ShouldEqual, 1)
+			})
 		})
 
 		Convey("When updating an author", func() {
-			authorId, _ := service.CreateAuthor(author)
+			authorId, _ := service.CreateAuthor(ctx, author)
 
 			Convey("Updating the author should return the updated author", func() {
 				newName := faker.Name()
-				author, _ := service.UpdateAuthor(data.NewAuthorBuilder().
+				author, _ := service.UpdateAuthor(ctx, data.NewAuthorBuilder().
 					WithId(authorId).
 					WithName(newName).
 					WithPicUrl(picUrl).
 					Build(),
+					repository.InitialVersion,
 				)
 				So(author.ID, ShouldEqual, authorId)
 				So(author.Name, ShouldNotEqual, name)
@@ -79,3 +191,220 @@ func TestService(t *testing.T) {
 
 	})
 }
+
+// recordingPublisher records the events it publishes, failing with its error.
+type recordingPublisher struct {
+	events []events.Event
+	err    error
+}
+
+func (r *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func TestServiceEvents(t *testing.T) {
+	Convey("Given a service with a publisher", t, func() {
+		ctx := repository.WithRequestID(repository.WithActor(context.Background(), "editor"), "request")
+		publisher := &recordingPublisher{}
+		s := New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository()), WithPublisher(publisher))
+		subscription := s.WatchAuthors()
+		defer subscription.Close()
+
+		Convey("Each change should be published to the publisher and the watchers", func() {
+			id, err := s.CreateAuthor(ctx, data.Author{Name: "Ada"})
+			So(err, ShouldBeNil)
+			_, err = s.UpdateAuthor(ctx, data.Author{ID: id, Name: "Ada Lovelace"}, 0)
+			So(err, ShouldBeNil)
+			So(s.DeleteAuthor(ctx, id, 0), ShouldBeNil)
+			restored, err := s.RestoreAuthor(ctx, id, 0)
+			So(err, ShouldBeNil)
+			_, err = s.RevertAuthor(ctx, id, 1, 0)
+			So(err, ShouldBeNil)
+
+			So(publisher.events, ShouldHaveLength, 5)
+			types := make([]string, len(publisher.events))
+			for index, event := range publisher.events {
+				types[index] = event.Type
+				So(event.AuthorID, ShouldEqual, id)
+				So(event.Actor, ShouldEqual, "editor")
+				So(event.RequestID, ShouldEqual, "request")
+				So(<-subscription.Events(), ShouldResemble, event)
+			}
+			So(types, ShouldResemble, []string{events.Created, events.Updated, events.Deleted, events.Updated, events.Updated})
+			So(publisher.events[3].Version, ShouldEqual, restored.Version)
+			So(publisher.events[4].Author.Name, ShouldEqual, "Ada")
+		})
+
+		Convey("Failed changes should not be published", func() {
+			_, err := s.UpdateAuthor(ctx, data.Author{ID: "unknown", Name: "Ada"}, 0)
+			So(err, ShouldNotBeNil)
+			So(s.DeleteAuthor(ctx, "unknown", 0), ShouldNotBeNil)
+			So(publisher.events, ShouldBeEmpty)
+		})
+
+		Convey("A failure to publish should not fail the change", func() {
+			publisher.err = errors.New("broker down")
+			_, err := s.CreateAuthor(ctx, data.Author{Name: "Ada"})
+			So(err, ShouldBeNil)
+			So(publisher.events, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestServiceBulkWrite(t *testing.T) {
+	Convey("Given a service with an author and a publisher", t, func() {
+		ctx := repository.WithActor(context.Background(), "editor")
+		publisher := &recordingPublisher{}
+		s := New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository()), WithPublisher(publisher))
+		id, _ := s.CreateAuthor(ctx, data.Author{Name: "Ada"})
+		publisher.events = nil
+
+		Convey("A valid batch should generate the missing IDs and publish its changes", func() {
+			results, err := s.BulkWrite(ctx, []repository.BulkOperation{
+				{Kind: repository.BulkCreate, Author: data.Author{Name: "Grace"}},
+				{Kind: repository.BulkDelete, ID: id},
+			}, repository.BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldBeNil)
+			So(results[0].ID, ShouldNotBeEmpty)
+			So(results[1].Err, ShouldBeNil)
+
+			So(publisher.events, ShouldHaveLength, 2)
+			So(publisher.events[0].Type, ShouldEqual, events.Created)
+			So(publisher.events[0].AuthorID, ShouldEqual, results[0].ID)
+			So(publisher.events[1].Type, ShouldEqual, events.Deleted)
+			So(publisher.events[1].Author, ShouldBeNil)
+		})
+
+		Convey("An invalid operation should abort an atomic batch", func() {
+			results, err := s.BulkWrite(ctx, []repository.BulkOperation{
+				{Kind: repository.BulkCreate, Author: data.Author{Name: "Grace"}},
+				{Kind: repository.BulkUpdate, Author: data.Author{ID: id}},
+				{Kind: "rename", ID: id},
+			}, repository.BulkAtomic)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, repository.ErrAborted)
+			So(results[1].Err, ShouldWrap, repository.ErrInvalidArgument)
+			So(results[2].Err, ShouldWrap, repository.ErrInvalidArgument)
+			So(publisher.events, ShouldBeEmpty)
+		})
+
+		Convey("An invalid operation should not prevent the others of a best effort batch", func() {
+			results, err := s.BulkWrite(ctx, []repository.BulkOperation{
+				{Kind: repository.BulkDelete},
+				{Kind: repository.BulkUpdate, Author: data.Author{ID: id, Name: "Ada Lovelace"}, ExpectedVersion: 1},
+			}, repository.BulkBestEffort)
+			So(err, ShouldBeNil)
+			So(results[0].Err, ShouldWrap, repository.ErrInvalidArgument)
+			So(results[1].Err, ShouldBeNil)
+			So(results[1].Author.Name, ShouldEqual, "Ada Lovelace")
+			So(publisher.events, ShouldHaveLength, 1)
+		})
+
+		Convey("A batch should have from 1 to MaxBulkOperations operations", func() {
+			_, err := s.BulkWrite(ctx, nil, repository.BulkAtomic)
+			So(err, ShouldWrap, repository.ErrInvalidArgument)
+			_, err = s.BulkWrite(ctx, make([]repository.BulkOperation, MaxBulkOperations+1), repository.BulkAtomic)
+			So(err, ShouldWrap, repository.ErrInvalidArgument)
+		})
+	})
+}
+
+func TestServiceImportAuthors(t *testing.T) {
+	Convey("Given a service with an author and a publisher", t, func() {
+		ctx := repository.WithActor(context.Background(), "editor")
+		publisher := &recordingPublisher{}
+		s := New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository()), WithPublisher(publisher))
+		id, _ := s.CreateAuthor(ctx, data.Author{Name: "Ada"})
+		publisher.events = nil
+		rows := `{"id": "` + id + `", "name": "Ada Lovelace"}` + "\n" + `{"id": "grace", "name": "Grace"}`
+
+		Convey("Each imported author should be published and audited", func() {
+			report, err := s.ImportAuthors(ctx, strings.NewReader(rows), repository.FormatNDJSON, repository.ImportOptions{})
+			So(err, ShouldBeNil)
+			So(report.Created, ShouldEqual, 1)
+			So(report.Updated, ShouldEqual, 1)
+			So(publisher.events, ShouldHaveLength, 2)
+			So(publisher.events[0].Type, ShouldEqual, events.Updated)
+			So(publisher.events[1].Type, ShouldEqual, events.Created)
+			So(publisher.events[1].Author.Name, ShouldEqual, "Grace")
+
+			entries, _ := s.AuthorHistory(ctx, "grace")
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Actor, ShouldEqual, "editor")
+		})
+
+		Convey("A dry run should not publish anything", func() {
+			_, err := s.ImportAuthors(ctx, strings.NewReader(rows), repository.FormatNDJSON, repository.ImportOptions{DryRun: true})
+			So(err, ShouldBeNil)
+			So(publisher.events, ShouldBeEmpty)
+		})
+
+		Convey("The authors should be exported", func() {
+			var buf strings.Builder
+			count, err := s.ExportAuthors(ctx, &buf, repository.FormatCSV)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+			So(buf.String(), ShouldContainSubstring, id+",Ada,")
+		})
+	})
+}
+
+func TestServiceConcurrency(t *testing.T) {
+	Convey("Given the HTTP and gRPC servers sharing a service over the in-memory stores", t, func() {
+		s := New(repository.New(repository.NewInMemoryDatabase(), repository.NewFakeClientRepository()))
+		handler := (&AuthorService{Service: s, Name: "AuthorService"}).MakeHandler()
+		catalog := newCatalogServer(s)
+		subscription := s.WatchAuthors()
+		defer subscription.Close()
+		const workers = 8
+
+		Convey("Concurrent requests on both servers should all succeed", func() {
+			var wg sync.WaitGroup
+			failures := make(chan string, workers*2)
+			for worker := 0; worker < workers; worker++ {
+				wg.Add(2)
+				go func(worker int) {
+					defer wg.Done()
+					author := data.Author{ID: fmt.Sprintf("http-%d", worker), Name: "Author"}
+					rr := executeRequest(httptest.NewRequest("POST", "/api/v1/author", jsonReaderFactory(author)), handler)
+					author.Name = "Renamed"
+					rr2 := executeRequest(httptest.NewRequest("POST", "/api/v1/author/update", jsonReaderFactory(author)), handler)
+					rr3 := executeRequest(httptest.NewRequest("GET", "/api/v1/author/all", nil), handler)
+					if rr.Code != http.StatusOK || rr2.Code != http.StatusOK || rr3.Code != http.StatusOK {
+						failures <- fmt.Sprintf("http worker %d: %d %d %d", worker, rr.Code, rr2.Code, rr3.Code)
+					}
+				}(worker)
+				go func(worker int) {
+					defer wg.Done()
+					ctx := context.Background()
+					created, err := catalog.CreateAuthor(ctx, &cpb.CreateAuthorRequest{Author: &cpb.Author{Id: fmt.Sprintf("grpc-%d", worker), Name: "Author"}})
+					if err == nil {
+						_, err = catalog.UpdateAuthor(ctx, &cpb.UpdateAuthorRequest{Author: &cpb.Author{Id: created.Id, Name: "Renamed"}})
+					}
+					if err == nil {
+						_, err = catalog.ListAuthorsPage(ctx, &cpb.ListAuthorsPageRequest{PageSize: 5})
+					}
+					if err != nil {
+						failures <- fmt.Sprintf("grpc worker %d: %v", worker, err)
+					}
+				}(worker)
+			}
+			wg.Wait()
+			close(failures)
+			var reported []string
+			for failure := range failures {
+				reported = append(reported, failure)
+			}
+			So(reported, ShouldBeEmpty)
+
+			authors := s.ListAll(context.Background())
+			So(authors, ShouldHaveLength, workers*2)
+			for _, author := range authors {
+				So(author.Name, ShouldEqual, "Renamed")
+				So(author.Version, ShouldEqual, 2)
+			}
+		})
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wcodesoft/mosha-author-service/lifecycle"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	mdb "github.com/wcodesoft/mosha-service-common/database"
+)
+
+const (
+	// storageMongo keeps the authors in the mongo database of MONGO_DB_HOST.
+	storageMongo = "mongo"
+	// storageMemory keeps the authors in memory, losing them on exit.
+	storageMemory = "memory"
+	// storageFile keeps the authors in the files of the STORAGE_PATH directory.
+	storageFile = "file"
+	// storagePostgres keeps the authors in the PostgreSQL database of STORAGE_DSN.
+	storagePostgres = "postgres"
+	// storageSQLite keeps the authors in the SQLite file of STORAGE_DSN.
+	storageSQLite = "sqlite"
+
+	defaultStoragePath = "data"
+	defaultPostgresDSN = "postgres://localhost:5432/mosha"
+	defaultSQLiteDSN   = "authors.db"
+)
+
+// storage is the database of the authors selected by STORAGE_BACKEND with the
+// stores kept next to it.
+type storage struct {
+	// Database is the database of the authors.
+	Database repository.Database
+	// Outbox is the outbox of the quote deletions, nil to keep it in memory.
+	Outbox repository.OutboxStore
+	// Audit is the audit log, nil to keep it in memory.
+	Audit repository.AuditStore
+	// System is the name of the database system in the traces.
+	System string
+	// Close releases the storage.
+	Close func(ctx context.Context) error
+}
+
+// openStorage opens the storage of the backend, which is mongo, memory, file,
+// postgres or sqlite.
+func openStorage(backend string) (storage, error) {
+	switch backend {
+	case storageMongo:
+		return openMongoStorage(getEnv("MONGO_DB_HOST", defaultMongoHost))
+	case storageMemory:
+		log.Warn("authors are kept in memory and lost on exit")
+		return storage{
+			Database: repository.NewInMemoryDatabase(),
+			System:   "memory",
+			Close:    func(context.Context) error { return nil },
+		}, nil
+	case storageFile:
+		return openFileStorage(getEnv("STORAGE_PATH", defaultStoragePath))
+	case storagePostgres:
+		return openSQLStorage(repository.DialectPostgres, getEnv("STORAGE_DSN", defaultPostgresDSN), "postgresql")
+	case storageSQLite:
+		return openSQLStorage(repository.DialectSQLite, getEnv("STORAGE_DSN", defaultSQLiteDSN), "sqlite")
+	default:
+		return storage{}, fmt.Errorf("invalid STORAGE_BACKEND %q, expected mongo, memory, file, postgres or sqlite", backend)
+	}
+}
+
+// openMongoStorage connects to the mongo database of the host and creates the
+// indexes of its collections.
+func openMongoStorage(host string) (storage, error) {
+	mongoClient, err := mdb.NewMongoClient(host)
+	if err != nil {
+		return storage{}, err
+	}
+	ctx := context.Background()
+	connection := mdb.NewMongoConnection(mongoClient, defaultDatabase, "authors")
+	if err := repository.CreateMongoIndexes(ctx, connection); err != nil {
+		log.Error("unable to create mongo indexes: ", err)
+	}
+	revisionsConnection := mdb.NewMongoConnection(mongoClient, defaultDatabase, "author_revisions")
+	if err := repository.CreateMongoRevisionIndexes(ctx, revisionsConnection); err != nil {
+		log.Error("unable to create mongo revision indexes: ", err)
+	}
+	outboxConnection := mdb.NewMongoConnection(mongoClient, defaultDatabase, "author_outbox")
+	if err := repository.CreateMongoOutboxIndexes(ctx, outboxConnection); err != nil {
+		log.Error("unable to create mongo outbox indexes: ", err)
+	}
+	auditConnection := mdb.NewMongoConnection(mongoClient, defaultDatabase, "author_audit")
+	if err := repository.CreateMongoAuditIndexes(ctx, auditConnection); err != nil {
+		log.Error("unable to create mongo audit indexes: ", err)
+	}
+	return storage{
+		Database: repository.NewMongoDatabase(connection, repository.WithRevisions(revisionsConnection)),
+		Outbox:   repository.NewMongoOutbox(outboxConnection),
+		Audit:    repository.NewMongoAudit(auditConnection),
+		System:   "mongodb",
+		Close:    lifecycle.DisconnectMongo(mongoClient),
+	}, nil
+}
+
+// openFileStorage opens the authors, the outbox and the audit log persisted in
+// the files of the directory.
+func openFileStorage(path string) (storage, error) {
+	db, err := repository.NewFileDatabase(path)
+	if err != nil {
+		return storage{}, fmt.Errorf("unable to open STORAGE_PATH %s: %w", path, err)
+	}
+	outbox, err := repository.NewFileOutbox(path)
+	if err != nil {
+		_ = db.Close()
+		return storage{}, fmt.Errorf("unable to open the outbox in STORAGE_PATH %s: %w", path, err)
+	}
+	audit, err := repository.NewFileAudit(path)
+	if err != nil {
+		_ = outbox.Close()
+		_ = db.Close()
+		return storage{}, fmt.Errorf("unable to open the audit log in STORAGE_PATH %s: %w", path, err)
+	}
+	log.Infof("authors are kept in %s", path)
+	return storage{
+		Database: db,
+		Outbox:   outbox,
+		Audit:    audit,
+		System:   "file",
+		Close: func(context.Context) error {
+			return errors.Join(db.Close(), outbox.Close(), audit.Close())
+		},
+	}, nil
+}
+
+// openSQLStorage opens the SQL database of the data source name, migrates its
+// schema and keeps the outbox and the audit log in it.
+func openSQLStorage(dialect repository.SQLDialect, dsn string, system string) (storage, error) {
+	db, err := repository.OpenSQL(dialect, dsn)
+	if err != nil {
+		return storage{}, err
+	}
+	ctx := context.Background()
+	version, err := repository.MigrateSQL(ctx, db, dialect)
+	if err != nil {
+		_ = db.Close()
+		return storage{}, fmt.Errorf("unable to migrate the %s database: %w", dialect, err)
+	}
+	log.Infof("%s schema is at version %d", dialect, version)
+	database, err := repository.NewSQLDatabase(ctx, db, dialect)
+	if err != nil {
+		_ = db.Close()
+		return storage{}, err
+	}
+	outbox, err := repository.NewSQLOutbox(ctx, db, dialect)
+	if err != nil {
+		_ = db.Close()
+		return storage{}, err
+	}
+	audit, err := repository.NewSQLAudit(ctx, db, dialect)
+	if err != nil {
+		_ = db.Close()
+		return storage{}, err
+	}
+	return storage{
+		Database: database,
+		Outbox:   outbox,
+		Audit:    audit,
+		System:   system,
+		Close:    func(context.Context) error { return db.Close() },
+	}, nil
+}
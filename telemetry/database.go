@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"context"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the service.
+const instrumentationName = "github.com/wcodesoft/mosha-author-service"
+
+// databaseObserver creates a child span for each database operation.
+type databaseObserver struct {
+	system     string
+	collection string
+}
+
+// Observe starts the span of an operation.
+func (o databaseObserver) Observe(ctx context.Context, operation string) (context.Context, func(err error)) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, o.collection+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(o.system),
+			semconv.DBOperation(operation),
+			attribute.String("db.collection", o.collection),
+		),
+	)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// TraceDatabase returns a database creating a span for each operation of db,
+// stored in the collection of the database system, such as "mongodb".
+func TraceDatabase(db repository.Database, system string, collection string) repository.Database {
+	return repository.ObserveDatabase(db, databaseObserver{system: system, collection: collection})
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/wcodesoft/mosha-author-service/data"
+	"github.com/wcodesoft/mosha-author-service/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestTraceDatabase(t *testing.T) {
+	Convey("Given a traced database", t, func() {
+		recorder := recordSpans()
+		db := TraceDatabase(repository.NewInMemoryDatabase(), "mongodb", "authors")
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /api/v1/author/{id}")
+
+		Convey("Each operation should be a child span", func() {
+			_, err := db.AddAuthor(ctx, data.Author{ID: "1", Name: "Ada Lovelace"})
+			So(err, ShouldBeNil)
+			_, err = db.GetAuthor(ctx, "missing")
+			So(err, ShouldWrap, repository.ErrNotFound)
+			parent.End()
+
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 3)
+			So(spans[0].Name(), ShouldEqual, "authors.AddAuthor")
+			So(spans[0].Parent().SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+			So(spans[0].Attributes(), ShouldContain, attribute.String("db.system", "mongodb"))
+			So(spans[0].Attributes(), ShouldContain, attribute.String("db.operation", "AddAuthor"))
+			So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+			So(spans[1].Name(), ShouldEqual, "authors.GetAuthor")
+			So(spans[1].Status().Code, ShouldEqual, codes.Error)
+		})
+	})
+}
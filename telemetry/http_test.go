@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHttpMiddleware(t *testing.T) {
+	Convey("Given a router traced by the middleware", t, func() {
+		recorder := recordSpans()
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		var traceID trace.TraceID
+		r := chi.NewRouter()
+		r.Use(HttpMiddleware)
+		r.Get("/api/v1/author/{id}", func(w http.ResponseWriter, r *http.Request) {
+			traceID = trace.SpanContextFromContext(r.Context()).TraceID()
+		})
+
+		Convey("The span should be named after the route", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/author/1", nil))
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 1)
+			So(spans[0].Name(), ShouldEqual, "GET /api/v1/author/{id}")
+			So(spans[0].SpanKind(), ShouldEqual, trace.SpanKindServer)
+			So(traceID, ShouldEqual, spans[0].SpanContext().TraceID())
+		})
+
+		Convey("The trace of the caller should be continued", func() {
+			req := httptest.NewRequest("GET", "/api/v1/author/1", nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			So(traceID.String(), ShouldEqual, "4bf92f3577b34da6a3ce929d0e0e4736")
+		})
+
+		Convey("Requests matching no route should keep the method as name", func() {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 1)
+			So(spans[0].Name(), ShouldEqual, "GET")
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording the ended spans.
+func recordSpans() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("When setting up tracing without exporter", t, func() {
+		shutdown, err := Setup(ctx, Config{ServiceName: "AuthorService", Exporter: ExporterNone})
+		So(err, ShouldBeNil)
+		So(shutdown(ctx), ShouldBeNil)
+	})
+
+	Convey("When setting up tracing with an unknown exporter", t, func() {
+		_, err := Setup(ctx, Config{ServiceName: "AuthorService", Exporter: "jaeger"})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("When setting up tracing to a file", t, func() {
+		path := filepath.Join(t.TempDir(), "traces.json")
+		shutdown, err := Setup(ctx, Config{
+			ServiceName: "AuthorService",
+			Version:     "dev",
+			Exporter:    ExporterFile,
+			FilePath:    path,
+			SampleRatio: 1,
+		})
+		So(err, ShouldBeNil)
+		_, span := otel.Tracer("test").Start(ctx, "GetAuthor")
+		span.End()
+
+		Convey("The spans should be written to the file on shutdown", func() {
+			So(shutdown(ctx), ShouldBeNil)
+			content, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldContainSubstring, `"Name":"GetAuthor"`)
+			So(string(content), ShouldContainSubstring, "AuthorService")
+		})
+	})
+}